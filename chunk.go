@@ -0,0 +1,205 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// chunk is the slice [start, end) of a list of commands sent in a single
+// request. context holds the commands restoring the configuration mode
+// that the previous chunk left the session in.
+type chunk struct {
+	start, end int
+	context    []interface{}
+}
+
+// splitCommands splits commands into chunks of at most size commands. A
+// size below 1 selects the default of maxCmdBuflen. There is always at
+// least one chunk, even if commands is empty.
+func splitCommands(commands []interface{}, size int) []chunk {
+	if size < 1 {
+		size = maxCmdBuflen
+	}
+	if len(commands) <= size {
+		return []chunk{{start: 0, end: len(commands)}}
+	}
+	var chunks []chunk
+	var modes modeStack
+	for start := 0; start < len(commands); start += size {
+		end := start + size
+		if end > len(commands) {
+			end = len(commands)
+		}
+		chunks = append(chunks, chunk{start: start, end: end, context: modes.context()})
+		for _, cmd := range commands[start:end] {
+			modes.update(cmd)
+		}
+	}
+	return chunks
+}
+
+var configureRegex = regexp.MustCompile(`^configure(\s+terminal|\s+session\s+\S+)?$`)
+
+// configModes maps the prefixes of the commands entering a configuration
+// mode from global configuration to the prefixes of the commands entering
+// a sub-mode of it. Commands entering other modes are not recognised, so a
+// chunk starting within such a mode resumes in the enclosing mode.
+var configModes = map[string][]string{
+	"interface ":                      nil,
+	"vlan ":                           nil,
+	"router bgp ":                     {"address-family ", "vrf ", "vlan ", "vlan-aware-bundle "},
+	"router ":                         {"address-family ", "vrf "},
+	"route-map ":                      nil,
+	"ip access-list ":                 nil,
+	"ipv6 access-list ":               nil,
+	"mac access-list ":                nil,
+	"management ":                     {"vrf "},
+	"mlag configuration":              nil,
+	"daemon ":                         nil,
+	"policy-map ":                     {"class "},
+	"class-map ":                      nil,
+	"vrf instance ":                   nil,
+	"spanning-tree mst configuration": nil,
+	"aaa group server ":               nil,
+}
+
+// configModePrefixes holds the keys of configModes
+var configModePrefixes = func() []string {
+	prefixes := make([]string, 0, len(configModes))
+	for prefix := range configModes {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}()
+
+var sessionEndRegex = regexp.MustCompile(`^(commit(\s+timer\s+\S+)?|abort)$`)
+
+// modeArgs restricts the configModes prefixes shared with global one-liners
+// (e.g. "vlan internal order ...") to the commands that enter the mode.
+var modeArgs = map[string]*regexp.Regexp{
+	"vlan ": regexp.MustCompile(`^vlan\s+\d+(\s*[-,]\s*\d+)*$`),
+}
+
+// modePrefix returns the longest prefix of modes that cmd starts with
+func modePrefix(cmd string, modes []string) (string, bool) {
+	var match string
+	for _, prefix := range modes {
+		if (strings.HasPrefix(cmd, prefix) || cmd == strings.TrimSpace(prefix)) &&
+			len(prefix) > len(match) {
+			if re, ok := modeArgs[prefix]; ok && !re.MatchString(cmd) {
+				continue
+			}
+			match = prefix
+		}
+	}
+	return match, match != ""
+}
+
+// modeStack tracks the configuration mode a list of commands leaves the
+// CLI session in: the command entering configuration mode, followed by
+// the commands entering the mode and sub-mode, if any.
+type modeStack struct {
+	entries []interface{}
+	mode    string // configModes prefix of entries[1]
+}
+
+// context returns the commands restoring the tracked mode
+func (m *modeStack) context() []interface{} {
+	return append([]interface{}(nil), m.entries...)
+}
+
+// update tracks the mode change made by the command entry
+func (m *modeStack) update(entry interface{}) {
+	cmd := strings.TrimSpace(commandString(entry))
+	switch {
+	case configureRegex.MatchString(cmd):
+		m.entries = []interface{}{entry}
+		return
+	case cmd == "end", sessionEndRegex.MatchString(cmd), len(m.entries) == 0:
+		// commit and abort end a configuration session like end does
+		m.entries = nil
+		return
+	case cmd == "exit":
+		m.entries = m.entries[:len(m.entries)-1]
+		return
+	}
+	if len(m.entries) > 1 {
+		if _, ok := modePrefix(cmd, configModes[m.mode]); ok {
+			m.entries = append(m.entries[:2], entry)
+			return
+		}
+	}
+	if prefix, ok := modePrefix(cmd, configModePrefixes); ok {
+		m.entries = append(m.entries[:1], entry)
+		m.mode = prefix
+	}
+}
+
+// rebaseChunkError rebases err, returned for the request cmds holding the
+// commands of c after offset internal commands, so that it refers to the
+// whole list of commands. results are the results of the commands of the
+// chunks preceding c.
+func rebaseChunkError(err error, cmds []interface{}, offset int, c chunk,
+	results []map[string]interface{}) error {
+	err = rebaseCommandError(err, cmds, offset)
+	if cmdErr, ok := err.(*CommandError); ok {
+		if cmdErr.CommandIndex >= 0 {
+			cmdErr.CommandIndex += c.start
+		}
+		cmdErr.Results = append(append([]map[string]interface{}(nil), results...),
+			cmdErr.Results...)
+	}
+	if decErr, ok := err.(*DecodeError); ok {
+		decErr.CommandIndex += c.start
+	}
+	return err
+}
+
+// chunkError rebases err, returned for the request cmds holding the
+// commands of c after offset internal commands, like rebaseChunkError. The
+// results of the commands of the chunks preceding c are taken from done.
+// If they cannot be decoded, the decoding error is joined to err.
+func chunkError(err error, cmds []interface{}, offset int, c chunk,
+	done []*JSONRPCResponse) error {
+	results, resultsErr := resultsOf(done)
+	err = rebaseChunkError(err, cmds, offset, c, results)
+	if resultsErr != nil {
+		return errors.Join(err, resultsErr)
+	}
+	return err
+}
@@ -0,0 +1,165 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommands_UnitTest(t *testing.T) {
+	session := "configure session s1"
+	tests := []struct {
+		cmds     []interface{}
+		size     int
+		contexts [][]interface{}
+	}{
+		{cmdsToInterface([]string{"show version"}), 0, [][]interface{}{nil}},
+		{nil, 2, [][]interface{}{nil}},
+		{cmdsToInterface([]string{"show version", "show clock", "show hostname"}), 2,
+			[][]interface{}{nil, nil}},
+		{cmdsToInterface([]string{"configure terminal", "hostname foo", "interface Ethernet1",
+			"description foo", "exit", "vlan 10"}), 2,
+			[][]interface{}{nil, {"configure terminal"},
+				{"configure terminal", "interface Ethernet1"}}},
+		{cmdsToInterface([]string{session, "router bgp 65000", "vrf blue", "rd 1:1",
+			"address-family ipv4", "end", "show clock"}), 3,
+			[][]interface{}{nil, {session, "router bgp 65000", "vrf blue"}, nil}},
+		{cmdsToInterface([]string{"configure", "interface Ethernet1", "vrf blue",
+			"router ospf 1", "exit", "exit", "show clock"}), 2,
+			[][]interface{}{nil, {"configure", "interface Ethernet1"},
+				{"configure", "router ospf 1"}, nil}},
+		{cmdsToInterface([]string{session, "vlan 10", "commit", "show clock",
+			session, "vlan 20", "abort", "show clock", session, "vlan 30",
+			"commit timer 00:05:00", "show clock"}), 3,
+			[][]interface{}{nil, nil, {session, "vlan 20"}, {session}}},
+		{cmdsToInterface([]string{session, "vlan 10", "abort", "show clock",
+			session, "commit timer 00:05:00", "show clock"}), 3,
+			[][]interface{}{nil, nil, nil}},
+		{cmdsToInterface([]string{"configure", "vlan internal order ascending range 1006 1199",
+			"hostname foo", "vlan 10-20,30", "name foo"}), 2,
+			[][]interface{}{nil, {"configure"}, {"configure", "vlan 10-20,30"}}},
+		{[]interface{}{"configure", map[string]interface{}{"cmd": "banner motd",
+			"input": "hello\nEOF"}, "management api http-commands", "vrf default"}, 3,
+			[][]interface{}{nil, {"configure", "management api http-commands"}}},
+	}
+	for i, tt := range tests {
+		chunks := splitCommands(tt.cmds, tt.size)
+		if len(chunks) != len(tt.contexts) {
+			t.Fatalf("%d: Expected %d chunks, got %#v", i, len(tt.contexts), chunks)
+		}
+		next := 0
+		for j, c := range chunks {
+			if c.start != next || c.end < c.start ||
+				(tt.size > 0 && c.end-c.start > tt.size) {
+				t.Fatalf("%d: Invalid chunk %#v", i, c)
+			}
+			next = c.end
+			if !reflect.DeepEqual(c.context, tt.contexts[j]) {
+				t.Fatalf("%d: Expected context %#v, got %#v", i, tt.contexts[j],
+					c.context)
+			}
+		}
+		if next != len(tt.cmds) {
+			t.Fatalf("%d: Chunks cover %d of %d commands", i, next, len(tt.cmds))
+		}
+	}
+}
+
+func TestRebaseChunkError_UnitTest(t *testing.T) {
+	c := chunk{start: 4, end: 6, context: []interface{}{"configure terminal"}}
+	cmds := []interface{}{"enable", "configure terminal", "vlan 10", "vlan 5000"}
+	err := rebaseChunkError(&CommandError{CommandIndex: 3,
+		Results: []map[string]interface{}{{}, {}, {"a": 1}}}, cmds, 2, c,
+		[]map[string]interface{}{{"b": 2}})
+	cmdErr, ok := err.(*CommandError)
+	if !ok || cmdErr.CommandIndex != 5 || cmdErr.Command != "vlan 5000" {
+		t.Fatalf("Expected command 5 to fail, got %#v", err)
+	}
+	want := []map[string]interface{}{{"b": 2}, {"a": 1}}
+	if !reflect.DeepEqual(cmdErr.Results, want) {
+		t.Fatalf("Expected results %#v, got %#v", want, cmdErr.Results)
+	}
+
+	err = rebaseChunkError(&CommandError{CommandIndex: 1}, cmds, 2, c, nil)
+	if cmdErr, ok := err.(*CommandError); !ok || cmdErr.CommandIndex != -1 {
+		t.Fatalf("Expected context failure at -1, got %#v", err)
+	}
+
+	err = rebaseChunkError(&DecodeError{CommandIndex: 2}, cmds, 2, c, nil)
+	if decErr, ok := err.(*DecodeError); !ok || decErr.CommandIndex != 4 {
+		t.Fatalf("Expected decode failure of command 4, got %#v", err)
+	}
+}
+
+func TestNodeChunkSize_UnitTest(t *testing.T) {
+	dummyNode.SetChunkSize(3)
+	defer dummyNode.SetChunkSize(0)
+
+	var cmds []string
+	for i := 1; i <= 5; i++ {
+		cmds = append(cmds, fmt.Sprintf("vlan %d", i))
+	}
+	if err := dummyNode.ConfigWithErr(cmds...); err != nil {
+		t.Fatalf("ConfigWithErr: %s", err)
+	}
+	want := []interface{}{"enable", "configure terminal", "vlan 2", "vlan 3",
+		"vlan 4", "vlan 5"}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+
+	resp, err := dummyNode.RunCommands(cmds, "text")
+	if err != nil || len(resp.Result) != len(cmds) {
+		t.Fatalf("Expected %d results, got %#v %v", len(cmds), resp, err)
+	}
+
+	h, _ := dummyNode.GetHandle("json")
+	h.SetChunkSize(4)
+	for _, cmd := range cmds {
+		h.AddCommandStr(cmd, new(MyShow))
+	}
+	if err := h.Call(); err != nil {
+		t.Fatalf("Call: %s", err)
+	}
+	want = []interface{}{"enable", "vlan 5"}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+	meta := h.DecodeMetadata()
+	if len(meta) != len(cmds) || meta[4].CommandIndex != 4 ||
+		meta[4].Command != "vlan 5" {
+		t.Fatalf("Unexpected metadata %#v", meta)
+	}
+}
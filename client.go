@@ -33,6 +33,7 @@
 package goeapi
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/user"
@@ -40,7 +41,10 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/aristanetworks/goeapi/config"
 	"github.com/vaughan0/go-ini"
 )
 
@@ -57,11 +61,27 @@ const (
 // object forms the base for communicating with devices.
 type Node struct {
 	conn          EapiConnectionEntity
-	runningConfig string
-	startupConfig string
+	configMu      sync.Mutex
+	configTTL     time.Duration
+	runningConfig cachedConfig
+	startupConfig cachedConfig
 	autoRefresh   bool
 	enablePasswd  string
 	versionNumber string
+	session       *ConfigSession
+	record        func(commands []string)
+	chunkSize     int
+}
+
+// DefaultConfigCacheTTL is how long the Nodes created by Connect,
+// ConnectTLS and ConnectTo cache the config fetched from the node.
+const DefaultConfigCacheTTL = 30 * time.Second
+
+// cachedConfig holds a copy of a config fetched from the node
+type cachedConfig struct {
+	text      string
+	tree      *config.Config
+	fetchedAt time.Time
 }
 
 // GetConnection returns the EapiConnectionEntity
@@ -96,10 +116,49 @@ func (n *Node) SetConnection(c EapiConnectionEntity) {
 //	val (bool): If True, the running-config and startup-config are
 //	            refreshed on config events.  If False, then the config
 //	            properties must be manually refreshed.
+//
+// Config events now always invalidate the cached config (see
+// SetConfigCacheTTL), so val no longer has any effect.
 func (n *Node) SetAutoRefresh(val bool) {
 	n.autoRefresh = val
 }
 
+// SetChunkSize sets the maximum number of commands sent to the node in a
+// single request. Larger batches passed to RunCommands, Config and the
+// handles returned by GetHandle are split into requests of at most size
+// commands, each restoring the configuration mode the previous one left
+// the session in, and their results are merged back in order. Execution
+// stops at the first failing request.
+//
+// Args:
+//
+//	size (int): Maximum number of commands per request. Values below 1
+//	            select the default of 64.
+func (n *Node) SetChunkSize(size int) {
+	n.chunkSize = size
+}
+
+// SetConfigCacheTTL sets how long the running-config and startup-config
+// fetched from the node are cached.
+//
+// The cache is shared by RunningConfig, StartupConfig and GetSection. It
+// is invalidated by Refresh and whenever config commands are sent through
+// the Node (Config, ConfigWithErr, ConfigContext and ConfigSession).
+// Changes made by other means, such as RunCommands or another client, are
+// only picked up once the cached copy expires.
+//
+// Args:
+//
+//	ttl (time.Duration): How long a cached config is used before it is
+//	                     fetched again. Zero or a negative value disables
+//	                     the cache. Nodes created by Connect, ConnectTLS
+//	                     and ConnectTo use DefaultConfigCacheTTL.
+func (n *Node) SetConfigCacheTTL(ttl time.Duration) {
+	n.configMu.Lock()
+	defer n.configMu.Unlock()
+	n.configTTL = ttl
+}
+
 // EnableAuthentication configures the enable mode authentication
 // password present in passwd
 //
@@ -112,31 +171,64 @@ func (n *Node) EnableAuthentication(passwd string) {
 }
 
 // RunningConfig returns the running configuration for the Arista EOS
-// device. A copy is cached locally if one does not already exist.
+// device. A copy is cached locally if one does not already exist (see
+// SetConfigCacheTTL).
 //
 // Returns:
 //
 //	String format of the running config
 func (n *Node) RunningConfig() string {
-	if n.runningConfig != "" {
-		return n.runningConfig
-	}
-	n.runningConfig, _ = n.getConfigText(RunningConfig, "all")
-	return n.runningConfig
+	n.configMu.Lock()
+	defer n.configMu.Unlock()
+	cache, _ := n.loadConfig(RunningConfig)
+	return cache.text
 }
 
 // StartupConfig returns the startup configuration for the Arista EOS
-// device. A copy is cached locally if one does not already exist.
+// device. A copy is cached locally if one does not already exist (see
+// SetConfigCacheTTL).
 //
 // Returns:
 //
 //	String format of the startup config
 func (n *Node) StartupConfig() string {
-	if n.startupConfig != "" {
-		return n.startupConfig
+	n.configMu.Lock()
+	defer n.configMu.Unlock()
+	cache, _ := n.loadConfig(StartupConfig)
+	return cache.text
+}
+
+// ConfigTree returns the running configuration or the startup
+// configuration of the node parsed into a config.Config, which can be
+// navigated and queried by path. The tree is built from the cached copy of
+// the config (see SetConfigCacheTTL) and is shared, so it must not be
+// modified.
+//
+// Args:
+//
+//	name (string): Either RunningConfig or StartupConfig. The default
+//	               value is RunningConfig.
+//
+// Returns:
+//
+//	The parsed config or error on failure
+func (n *Node) ConfigTree(name string) (*config.Config, error) {
+	if name == "" {
+		name = RunningConfig
+	}
+	if name != RunningConfig && name != StartupConfig {
+		return nil, fmt.Errorf("Invalid config type: %s", name)
 	}
-	n.startupConfig, _ = n.getConfigText(StartupConfig, "")
-	return n.startupConfig
+	n.configMu.Lock()
+	defer n.configMu.Unlock()
+	cache, err := n.loadConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	if cache.tree == nil {
+		cache.tree = config.Parse(cache.text)
+	}
+	return cache.tree, nil
 }
 
 // Refresh refreshes the config properties.
@@ -146,8 +238,33 @@ func (n *Node) StartupConfig() string {
 // clear the current internal instance variables.  On the next call the
 // instance variables will be repopulated with the current config
 func (n *Node) Refresh() {
-	n.runningConfig = ""
-	n.runningConfig = ""
+	n.configMu.Lock()
+	defer n.configMu.Unlock()
+	n.runningConfig = cachedConfig{}
+	n.startupConfig = cachedConfig{}
+}
+
+// loadConfig returns the cached copy of name, which is either the
+// running-config (with defaults) or the startup-config, fetching it from
+// the node unless the cached copy is still valid. configMu must be held.
+func (n *Node) loadConfig(name string) (*cachedConfig, error) {
+	cache, params := &n.runningConfig, "all"
+	if name == StartupConfig {
+		cache, params = &n.startupConfig, ""
+	}
+	if cache.text != "" && time.Since(cache.fetchedAt) < n.configTTL {
+		return cache, nil
+	}
+	text, err := n.getConfigText(name, params)
+	if err != nil {
+		return &cachedConfig{}, err
+	}
+	fetched := cachedConfig{text: text, fetchedAt: time.Now()}
+	if n.configTTL <= 0 {
+		return &fetched, nil
+	}
+	*cache = fetched
+	return cache, nil
 }
 
 // GetHandle returns the EapiReqHandle for the connection.
@@ -222,67 +339,142 @@ func (n *Node) getConfigText(config, params string) (string, error) {
 	return strings.TrimSpace(result["output"].(string)), nil
 }
 
-// GetSection retrieves the config section from the Node
+// GetSection retrieves the config section from the Node. The section is
+// searched in the cached copy of the config (see SetConfigCacheTTL).
 //
 // Args:
 //
-//	regex (string):
-//	config (string):
+//	regex (string): The regular expression matching the first line of the
+//	                section, including its indentation. A regex that
+//	                matches no single line, e.g. one spanning several
+//	                lines, is matched against the whole config text
+//	                instead, and the section then starts at the match.
+//	name (string): Either RunningConfig or StartupConfig. The default
+//	               value is RunningConfig.
 //
 // Returns:
 //
 //	String value of the config section requested.
 //	Error returned on failure.
-func (n *Node) GetSection(regex string, config string) (string, error) {
-	var params string
-	if config == "" || config == RunningConfig {
-		config = RunningConfig
-		params = "all"
-	}
-	if config != RunningConfig && config != StartupConfig {
-		return "", fmt.Errorf("Invalid config type: %s", config)
-	}
+func (n *Node) GetSection(regex string, name string) (string, error) {
 	sectionRegex, err := regexp.Compile(regex)
 	if err != nil {
 		return "", fmt.Errorf("Invalid regexp")
 	}
-	config, err = n.getConfigText(config, params)
-	if err != nil || config == "" {
+	tree, err := n.ConfigTree(name)
+	if err != nil || len(tree.Children()) == 0 {
 		return "", err
 	}
 
-	match := sectionRegex.FindStringIndex(config)
-	if match == nil {
-		return "", fmt.Errorf("Config section not found %d", match)
+	var section string
+	tree.Walk(func(line *config.Line) bool {
+		if section == "" && !line.IsComment() &&
+			sectionRegex.MatchString(line.Raw()) {
+			section = line.String()
+		}
+		return section == ""
+	})
+	if section == "" {
+		return n.getSectionText(sectionRegex, name)
 	}
+	return section, nil
+}
 
-	blockStart := match[0]
-	lineEnd := match[1]
+// getSectionText is the fallback of GetSection for regexes that match no
+// single line, such as those spanning several lines: sectionRegex is
+// matched against the whole config text and the section runs from the
+// start of the match to the next line that is not indented.
+func (n *Node) getSectionText(sectionRegex *regexp.Regexp,
+	name string) (string, error) {
+	if name == "" {
+		name = RunningConfig
+	}
+	n.configMu.Lock()
+	cache, err := n.loadConfig(name)
+	n.configMu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	text := cache.text
 
-	blockRegex := regexp.MustCompile(`(?m)^[^\s]`)
-	match = blockRegex.FindStringIndex(config[lineEnd:])
+	match := sectionRegex.FindStringIndex(text)
+	if match == nil {
+		return "", fmt.Errorf("Config section not found: %s", sectionRegex)
+	}
+	blockStart, lineEnd := match[0], match[1]
+	match = regexp.MustCompile(`(?m)^[^\s]`).FindStringIndex(text[lineEnd:])
 	if match == nil {
 		return "", fmt.Errorf("Block section/end not found")
 	}
-
-	blockEnd := match[0]
-	blockEnd = lineEnd + blockEnd
-
-	return config[blockStart:blockEnd], nil
+	return text[blockStart : lineEnd+match[0]], nil
 }
 
 // ConfigWithErr the node with the specified commands
 //
 // This method is used to send configuration commands to the node.
 // It will takes a list of strings and prepend the necessary commands
-// to put the session into config mode. Returns error if issues arise.
+// to put the session into config mode. Returns error if issues arise. If a
+// command is rejected by the node, the error is a *CommandError whose
+// CommandIndex refers to commands.
 func (n *Node) ConfigWithErr(commands ...string) error {
-	commands = append([]string{"configure terminal"}, commands...)
-	_, err := n.RunCommands(commands, "json")
-	if n.autoRefresh {
-		n.Refresh()
+	return n.ConfigContext(context.Background(), commands...)
+}
+
+// ConfigContext the node with the specified commands using ctx
+//
+// This method is used to send configuration commands to the node.
+// It will takes a list of strings and prepend the necessary commands
+// to put the session into config mode. If ctx is cancelled or its deadline
+// passes before the node responds, the request is aborted and ctx.Err()
+// is returned. If the Node is bound to a ConfigSession (see
+// ConfigSession.Node), the commands are staged into that session. If the
+// Node is a recording Node (see RecordingNode), the commands are only
+// recorded.
+func (n *Node) ConfigContext(ctx context.Context, commands ...string) error {
+	if n.record != nil {
+		n.record(commands)
+		return nil
+	}
+	return n.config(ctx, cmdsToInterface(commands))
+}
+
+// ConfigWithInput the node with the specified commands using ctx
+//
+// This method behaves like ConfigContext, but each command is sent along
+// with the input it reads. A recording Node cannot record input, so it
+// returns an error if any command has input.
+func (n *Node) ConfigWithInput(ctx context.Context, commands ...Command) error {
+	if n.record != nil {
+		cmds := make([]string, 0, len(commands))
+		for _, cmd := range commands {
+			if cmd.Input != "" {
+				return fmt.Errorf("Cannot record input of command: %s", cmd.Cmd)
+			}
+			cmds = append(cmds, cmd.Cmd)
+		}
+		n.record(cmds)
+		return nil
+	}
+	return n.config(ctx, commandEntries(commands))
+}
+
+// config sends the runCmds command entries cmds from within config mode,
+// or the configuration session the Node is bound to.
+func (n *Node) config(ctx context.Context, cmds []interface{}) error {
+	cmds = append([]interface{}{n.ConfigureCommand()}, cmds...)
+	_, err := n.runCommands(ctx, cmds, "json", RequestOptions{})
+	n.Refresh()
+	return rebaseCommandError(err, cmds, 1)
+}
+
+// ConfigureCommand returns the command Config sends to enter config mode:
+// "configure terminal", or "configure session <name>" if the Node is bound
+// to a ConfigSession (see ConfigSession.Node).
+func (n *Node) ConfigureCommand() string {
+	if n.session != nil {
+		return n.session.enter()
 	}
-	return err
+	return "configure terminal"
 }
 
 // Config the node with the specified commands
@@ -295,6 +487,44 @@ func (n *Node) Config(commands ...string) bool {
 	return (err == nil)
 }
 
+// RecordingNode returns a Node sharing this Node's connection whose
+// configuration commands are passed to record instead of being sent.
+// Config, ConfigWithErr and ConfigContext on the returned Node call record
+// with the commands of each call and always succeed; show commands and
+// config reads are still sent to the node. This allows the module entities
+// to be used to build a list of commands that is sent later.
+//
+// Args:
+//
+//	record (func([]string)): Called with the commands of each
+//	                         configuration call
+//
+// Returns:
+//
+//	Pointer to the recording Node
+func (n *Node) RecordingNode(record func(commands []string)) *Node {
+	r := n.derive()
+	r.record = record
+	return r
+}
+
+// derive returns a new Node sharing the connection and settings of n, with
+// its own config cache.
+func (n *Node) derive() *Node {
+	n.configMu.Lock()
+	configTTL := n.configTTL
+	n.configMu.Unlock()
+	return &Node{
+		conn:          n.conn,
+		configTTL:     configTTL,
+		autoRefresh:   n.autoRefresh,
+		enablePasswd:  n.enablePasswd,
+		versionNumber: n.versionNumber,
+		session:       n.session,
+		chunkSize:     n.chunkSize,
+	}
+}
+
 // Version returns the EOS version for this node
 func (n *Node) Version() string {
 	return n.versionNumber
@@ -302,8 +532,8 @@ func (n *Node) Version() string {
 
 // getVersionNumber is used to populate the versionNumber for this node. This
 // is called during the Connect.
-func (n *Node) getVersionNumber() error {
-	result, err := n.RunCommands([]string{"show version"}, "json")
+func (n *Node) getVersionNumber(ctx context.Context) error {
+	result, err := n.RunCommandsContext(ctx, []string{"show version"}, "json")
 	if err != nil {
 		return fmt.Errorf("getVersionNumber: %v", err)
 	}
@@ -372,11 +602,94 @@ func (n *Node) Enable(commands []string) ([]map[string]string, error) {
 // Returns:
 //
 //	This method will return the raw response from the connection
-//	which is a JSONRPCResponse object or error on failure.
+//	which is a JSONRPCResponse object or error on failure. If a command
+//	is rejected by the node, the error is a *CommandError whose
+//	CommandIndex refers to commands.
 func (n *Node) RunCommands(commands []string,
 	encoding string) (*JSONRPCResponse, error) {
-	var cmds []interface{}
+	return n.RunCommandsContext(context.Background(), commands, encoding)
+}
+
+// RunCommandsContext sends the commands over the transport to the device
+// using ctx
+//
+// This method behaves like RunCommands, but binds the request to ctx so
+// that cancelling ctx (or reaching its deadline) aborts the in-flight
+// request and returns ctx.Err().
+//
+// Args:
+//
+//	ctx (context.Context): context controlling cancellation of the request
+//	commands (array): The ordered list of commands to send to the
+//	                 device using the transport
+//	encoding (string): The encoding method to use for the request and
+//	                excpected response. ('json' or 'text')
+//
+// Returns:
+//
+//	This method will return the raw response from the connection
+//	which is a JSONRPCResponse object or error on failure.
+func (n *Node) RunCommandsContext(ctx context.Context, commands []string,
+	encoding string) (*JSONRPCResponse, error) {
+	return n.RunCommandsWithOptions(ctx, commands, encoding, RequestOptions{})
+}
+
+// RunCommandsWithOptions sends the commands over the transport to the
+// device using ctx and the optional runCmds parameters in opts
+//
+// This method behaves like RunCommandsContext. If opts.Timestamps is set,
+// the Meta of the returned JSONRPCResponse holds the execution time of
+// each command.
+//
+// Args:
+//
+//	ctx (context.Context): context controlling cancellation of the request
+//	commands (array): The ordered list of commands to send to the
+//	                 device using the transport
+//	encoding (string): The encoding method to use for the request and
+//	                excpected response. ('json' or 'text')
+//	opts (RequestOptions): The optional runCmds parameters
+//
+// Returns:
+//
+//	This method will return the raw response from the connection
+//	which is a JSONRPCResponse object or error on failure.
+func (n *Node) RunCommandsWithOptions(ctx context.Context, commands []string,
+	encoding string, opts RequestOptions) (*JSONRPCResponse, error) {
+	return n.runCommands(ctx, cmdsToInterface(commands), encoding, opts)
+}
+
+// RunCommandsWithInput sends the commands, along with the input each of
+// them reads, over the transport to the device using ctx
+//
+// This method behaves like RunCommandsContext, but allows answering the
+// prompts of commands such as "copy" or "reload", or feeding a heredoc to
+// "bash".
+//
+// Args:
+//
+//	ctx (context.Context): context controlling cancellation of the request
+//	commands ([]Command): The ordered list of commands to send to the
+//	                 device using the transport
+//	encoding (string): The encoding method to use for the request and
+//	                excpected response. ('json' or 'text')
+//
+// Returns:
+//
+//	This method will return the raw response from the connection
+//	which is a JSONRPCResponse object or error on failure.
+func (n *Node) RunCommandsWithInput(ctx context.Context, commands []Command,
+	encoding string) (*JSONRPCResponse, error) {
+	return n.runCommands(ctx, commandEntries(commands), encoding,
+		RequestOptions{})
+}
 
+// runCommands sends the runCmds command entries cmds, preceded by the
+// enable command, in chunks of at most the chunk size of the Node (see
+// SetChunkSize), and returns the merged response without the results of
+// enable and the mode context replayed for each chunk.
+func (n *Node) runCommands(ctx context.Context, cmds []interface{},
+	encoding string, opts RequestOptions) (*JSONRPCResponse, error) {
 	// Check to see if enablePasswd has been set. In the case where
 	// enablePassword is provided, the following cmds value format would let
 	// you enter exec mode and clear interface counters
@@ -384,20 +697,42 @@ func (n *Node) RunCommands(commands []string,
 	// [ { "cmd": "enable", "input": <enablePasswd> },  "clear counters" ]
 	//
 	// In these cases we prepend this sequence to the commands.
+	enable := []interface{}{"enable"}
 	if n.enablePasswd != "" {
-		cmds = n.prependEnableSequence(commands)
-	} else {
-		commands = append([]string{"enable"}, commands...)
-		cmds = cmdsToInterface(commands)
+		enable = n.prependEnable(nil)
 	}
 
-	result, err := n.conn.Execute(cmds, encoding)
-	if err != nil {
-		return nil, err
+	var response *JSONRPCResponse
+	for _, c := range splitCommands(cmds, n.chunkSize) {
+		req := append(append(append([]interface{}(nil), enable...), c.context...),
+			cmds[c.start:c.end]...)
+		offset := len(req) - (c.end - c.start)
+		result, err := execute(ctx, n.conn, req, encoding, opts)
+		if err != nil {
+			var results []map[string]interface{}
+			if response != nil {
+				results = response.Result
+			}
+			return nil, rebaseChunkError(err, req, offset, c, results)
+		}
+		// pop the results for enable and the mode context off the result list
+		result.Result = append(result.Result[:0], result.Result[offset:]...)
+		if len(result.Meta) > 0 {
+			result.Meta = result.Meta[offset:]
+		}
+		if response == nil {
+			response = result
+			continue
+		}
+		if len(result.Meta) > 0 {
+			for len(response.Meta) < len(response.Result) {
+				response.Meta = append(response.Meta, CommandMeta{})
+			}
+			response.Meta = append(response.Meta, result.Meta...)
+		}
+		response.Result = append(response.Result, result.Result...)
 	}
-	// pop the result for enable off the result list
-	result.Result = append(result.Result[:0], result.Result[1:]...)
-	return result, err
+	return response, nil
 }
 
 // prependEnableSequence helper fuction to convert the provided array of
@@ -412,19 +747,17 @@ func (n *Node) RunCommands(commands []string,
 //
 //	An array of []interface{} if successful.
 func (n *Node) prependEnableSequence(commands []string) []interface{} {
-	length := len(commands) + 1
+	return n.prependEnable(cmdsToInterface(commands))
+}
 
-	var interfaceSlice []interface{}
-	interfaceSlice = make([]interface{}, length)
-	interfaceSlice[0] = map[string]interface{}{
+// prependEnable returns the runCmds command entries cmds preceded by the
+// entry map[string]interface {"cmd":"enable","input":enablePasswd}
+func (n *Node) prependEnable(cmds []interface{}) []interface{} {
+	enable := map[string]interface{}{
 		"cmd":   "enable",
 		"input": n.enablePasswd,
 	}
-
-	for i := 1; i < length; i++ {
-		interfaceSlice[i] = commands[i-1]
-	}
-	return interfaceSlice
+	return append([]interface{}{enable}, cmds...)
 }
 
 // cmdsToInterface is a helper fuction that converts a given array
@@ -734,7 +1067,29 @@ func ConfigFor(name string) ini.Section {
 //	This function will return an instance of Node with the settings
 //	from the config instance.
 func ConnectTo(name string) (*Node, error) {
-	section := ConfigFor(name)
+	return configGlobal.ConnectTo(name)
+}
+
+// ConnectTo Creates a Node instance based on an entry from this EapiConfig
+//
+// Args:
+//
+//	name (string): The name of the connection to load from the config.  The
+//	            name argument should be the connection name (everything
+//	            right of the colon from the INI file)
+//
+// Returns:
+//
+//	This function will return an instance of Node with the settings
+//	from the config instance.
+func (e *EapiConfig) ConnectTo(name string) (*Node, error) {
+	return e.connectTo(context.Background(), name)
+}
+
+// connectTo creates a Node instance based on an entry from this EapiConfig,
+// using ctx for the requests issued while connecting.
+func (e *EapiConfig) connectTo(ctx context.Context, name string) (*Node, error) {
+	section := e.GetConnection(name)
 	if section == nil {
 		return nil, fmt.Errorf("Connection profile not found in config")
 	}
@@ -754,19 +1109,21 @@ func ConnectTo(name string) (*Node, error) {
 	if ok {
 		port, _ = strconv.Atoi(section["port"])
 	}
-	var node *Node
+	var conn EapiConnectionEntity
 	var err error
 	if transport == "https_certs" {
-		node, err = ConnectTLS(transport, host, keyFile, certFile, caCertFile, port)
+		conn, err = ConnectionTLS(transport, host, keyFile, certFile, caCertFile, port)
 	} else {
-		node, err = Connect(transport, host, username, passwd, port)
+		conn, err = Connection(transport, host, username, passwd, port)
 	}
 	if err != nil {
 		return nil, err
 	}
+	node := &Node{conn: conn, autoRefresh: true,
+		configTTL: DefaultConfigCacheTTL}
 	node.EnableAuthentication(enablepwd)
 	// Populate the versionNumber for this node
-	node.getVersionNumber()
+	node.getVersionNumber(ctx)
 	return node, nil
 }
 
@@ -800,9 +1157,10 @@ func Connect(transport string, host string, username string, passwd string,
 	if err != nil {
 		return nil, err
 	}
-	node := &Node{conn: conn, autoRefresh: true}
+	node := &Node{conn: conn, autoRefresh: true,
+		configTTL: DefaultConfigCacheTTL}
 	// Populate the versionNumber for this node
-	node.getVersionNumber()
+	node.getVersionNumber(context.Background())
 
 	return node, nil
 }
@@ -812,9 +1170,10 @@ func ConnectTLS(transport, host, keyFile, certFile, caCertFile string, port int)
 	if err != nil {
 		return nil, err
 	}
-	node := &Node{conn: conn, autoRefresh: true}
+	node := &Node{conn: conn, autoRefresh: true,
+		configTTL: DefaultConfigCacheTTL}
 	// Populate the versionNumber for this node
-	node.getVersionNumber()
+	node.getVersionNumber(context.Background())
 
 	return node, nil
 }
@@ -30,13 +30,24 @@
 package goeapi
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/user"
+	"reflect"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestConfigExpandPath_UnitTest(t *testing.T) {
@@ -272,6 +283,7 @@ func TestClientGetSection_UnitTest(t *testing.T) {
 
 func TestClientGetSectionConnectionError_UnitTest(t *testing.T) {
 	conn := dummyNode.GetConnection().(*DummyEapiConnection)
+	dummyNode.Refresh()
 	conn.setReturnError(true)
 	ret, err := dummyNode.GetSection(`(?m)^interface Ethernet1$`, "startup-config")
 	if ret != "" && err == nil {
@@ -687,3 +699,379 @@ func TestClientNodeGetConnection_UnitTest(t *testing.T) {
 		}
 	}
 }
+
+func TestClientRunCommandsContextCanceled_UnitTest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := dummyNode.RunCommandsContext(ctx, []string{"show version"}, "json")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if err := dummyNode.ConfigContext(ctx, "hostname test"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// basicConnection implements only the EapiConnectionEntity methods.
+type basicConnection struct {
+	commands []interface{}
+}
+
+func (conn *basicConnection) Execute(commands []interface{},
+	encoding string) (*JSONRPCResponse, error) {
+	conn.commands = commands
+	resp := &JSONRPCResponse{Jsonrpc: "2.0"}
+	for range commands {
+		resp.Result = append(resp.Result, map[string]interface{}{})
+	}
+	return resp, nil
+}
+
+func (conn *basicConnection) SetTimeout(uint32)        {}
+func (conn *basicConnection) SetDisableKeepAlive(bool) {}
+func (conn *basicConnection) Error() error             { return nil }
+
+func TestClientBasicConnection_UnitTest(t *testing.T) {
+	conn := &basicConnection{}
+	node := &Node{conn: conn}
+	if _, err := node.RunCommands([]string{"show version"}, "json"); err != nil {
+		t.Fatalf("RunCommands failed: %s", err)
+	}
+	want := []interface{}{"enable", "show version"}
+	if !reflect.DeepEqual(conn.commands, want) {
+		t.Fatalf("Expected %v, got %v", want, conn.commands)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := node.RunCommandsContext(ctx, []string{"show version"}, "json")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if _, err := node.RunCommandsWithOptions(context.Background(),
+		[]string{"show version"}, "json",
+		RequestOptions{AutoComplete: true}); err == nil {
+		t.Fatal("Expected an error for request options on a basic connection")
+	}
+}
+
+func TestClientRunCommandsContextDeadline_UnitTest(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	conn := NewHTTPEapiConnection("http", addr.IP.String(), "admin", "", addr.Port)
+	node := &Node{conn: conn}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := node.RunCommandsContext(ctx, []string{"show version"}, "json")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if conn.Error() != err {
+		t.Fatalf("Connection error not set: %v", conn.Error())
+	}
+}
+
+func TestClientConnectionReusesTransport_UnitTest(t *testing.T) {
+	var mu sync.Mutex
+	newConns := 0
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc": "2.0", "id": "1", "result": [{}, {}]}`)
+	}))
+	srv.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			newConns++
+			mu.Unlock()
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	conn := NewHTTPEapiConnection("http", addr.IP.String(), "admin", "", addr.Port)
+	node := &Node{conn: conn}
+
+	for i := 0; i < 3; i++ {
+		if _, err := node.RunCommands([]string{"show version"}, "json"); err != nil {
+			t.Fatalf("RunCommands failed: %s", err)
+		}
+	}
+	mu.Lock()
+	if newConns != 1 {
+		t.Fatalf("Expected 1 connection for 3 requests, got %d", newConns)
+	}
+	mu.Unlock()
+
+	if err := conn.(io.Closer).Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+	if _, err := node.RunCommands([]string{"show version"}, "json"); err != nil {
+		t.Fatalf("RunCommands after Close failed: %s", err)
+	}
+	mu.Lock()
+	if newConns != 2 {
+		t.Fatalf("Expected new connection after Close, got %d", newConns)
+	}
+	mu.Unlock()
+}
+
+func TestClientConnectionTLSInvalidCerts_UnitTest(t *testing.T) {
+	conn, _ := ConnectionTLS("https_certs", "127.0.0.1", GetFixture("bogus.key"),
+		GetFixture("bogus.cert"), "", 443)
+	if _, err := conn.Execute([]interface{}{"show version"}, "json"); err == nil {
+		t.Fatal("Should fail to load missing key pair")
+	}
+	if conn.Error() == nil {
+		t.Fatal("Connection error not set")
+	}
+}
+
+func TestClientRunCommandsCommandError_UnitTest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc": "2.0", "id": "1", "error": {"code": 1002,
+			"message": "CLI command 4 of 4 'vlan 5000' failed: invalid command",
+			"data": [{}, {}, {}, {"errors": ["Invalid input"]}]}}`)
+	}))
+	defer srv.Close()
+
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	conn := NewHTTPEapiConnection("http", addr.IP.String(), "admin", "", addr.Port)
+	node := &Node{conn: conn}
+
+	err := node.ConfigWithErr("vlan 10", "vlan 5000")
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Expected *CommandError, got %#v", err)
+	}
+	if cmdErr.CommandIndex != 1 || cmdErr.Command != "vlan 5000" {
+		t.Fatalf("Unexpected index/command: %d/%s", cmdErr.CommandIndex,
+			cmdErr.Command)
+	}
+	if len(cmdErr.Results) != 1 || cmdErr.Errors[0] != "Invalid input" {
+		t.Fatalf("Unexpected results/errors: %#v/%#v", cmdErr.Results,
+			cmdErr.Errors)
+	}
+
+	_, err = node.RunCommands([]string{"configure", "vlan 10", "vlan 5000"}, "json")
+	if !errors.As(err, &cmdErr) || cmdErr.CommandIndex != 2 {
+		t.Fatalf("Unexpected RunCommands error: %#v", err)
+	}
+}
+
+// newConfigCacheTestNode returns a Node whose server answers every command
+// with a running-config and counts the requests it receives.
+func newConfigCacheTestNode(t *testing.T) (*Node, *int32) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		var req Request
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := JSONRPCResponse{Jsonrpc: "2.0", ID: req.ID}
+		for range req.Params.Cmds {
+			resp.Result = append(resp.Result, map[string]interface{}{
+				"output": "hostname test\ninterface Ethernet1\n   shutdown\n!\nend\n"})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	conn := NewHTTPEapiConnection("http", addr.IP.String(), "admin", "", addr.Port)
+	return &Node{conn: conn, configTTL: DefaultConfigCacheTTL}, &requests
+}
+
+func TestClientGetSectionMultiline_UnitTest(t *testing.T) {
+	node, _ := newConfigCacheTestNode(t)
+
+	section, err := node.GetSection(`hostname test\ninterface Ethernet1`, "")
+	want := "hostname test\ninterface Ethernet1\n   shutdown\n"
+	if err != nil || section != want {
+		t.Fatalf("Expected %q, got %q %v", want, section, err)
+	}
+	if _, err := node.GetSection(`interface Ethernet2\n`, ""); err == nil {
+		t.Fatal("Expected missing section to fail")
+	}
+}
+
+func TestClientConfigCache_UnitTest(t *testing.T) {
+	node, requests := newConfigCacheTestNode(t)
+
+	for i := 0; i < 3; i++ {
+		section, err := node.GetSection(`(?m)^interface Ethernet1$`, "")
+		if err != nil || section != "interface Ethernet1\n   shutdown\n" {
+			t.Fatalf("GetSection failed: %q %v", section, err)
+		}
+	}
+	if node.RunningConfig() == "" {
+		t.Fatal("RunningConfig returned no config")
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Fatalf("Expected 1 request, got %d", got)
+	}
+
+	if !node.Config("hostname test") {
+		t.Fatal("Config failed")
+	}
+	node.GetSection(`(?m)^interface Ethernet1$`, "")
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Fatalf("Expected Config to invalidate the cache, got %d requests", got)
+	}
+
+	node.StartupConfig()
+	node.StartupConfig()
+	if got := atomic.LoadInt32(requests); got != 4 {
+		t.Fatalf("Expected startup-config to be cached, got %d requests", got)
+	}
+
+	node.Refresh()
+	node.RunningConfig()
+	if got := atomic.LoadInt32(requests); got != 5 {
+		t.Fatalf("Expected Refresh to invalidate the cache, got %d requests", got)
+	}
+}
+
+func TestClientConfigCacheTTL_UnitTest(t *testing.T) {
+	node, requests := newConfigCacheTestNode(t)
+
+	node.SetConfigCacheTTL(50 * time.Millisecond)
+	node.RunningConfig()
+	node.RunningConfig()
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Fatalf("Expected 1 request, got %d", got)
+	}
+	time.Sleep(60 * time.Millisecond)
+	node.RunningConfig()
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Fatalf("Expected expired config to be fetched, got %d requests", got)
+	}
+
+	node.SetConfigCacheTTL(0)
+	node.RunningConfig()
+	node.RunningConfig()
+	if got := atomic.LoadInt32(requests); got != 4 {
+		t.Fatalf("Expected cache to be disabled, got %d requests", got)
+	}
+}
+
+func TestClientConfigTree_UnitTest(t *testing.T) {
+	node, requests := newConfigCacheTestNode(t)
+
+	tree, err := node.ConfigTree("")
+	if err != nil {
+		t.Fatalf("ConfigTree failed: %s", err)
+	}
+	if tree.Value("hostname") != "test" || !tree.Has("interface Ethernet1 > shutdown") {
+		t.Fatalf("Unexpected config tree:\n%s", tree)
+	}
+	if again, _ := node.ConfigTree(RunningConfig); again != tree {
+		t.Fatal("Expected the cached config tree to be reused")
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Fatalf("Expected 1 request, got %d", got)
+	}
+	if _, err := node.ConfigTree("bogus-config"); err == nil {
+		t.Fatal("Expected error for invalid config type")
+	}
+}
+
+func TestClientRecordingNode_UnitTest(t *testing.T) {
+	var recorded [][]string
+	node := dummyNode.RecordingNode(func(commands []string) {
+		recorded = append(recorded, commands)
+	})
+	if err := dummyNode.ConfigWithErr("hostname before"); err != nil {
+		t.Fatalf("Config failed: %s", err)
+	}
+	if ok := node.Config("vlan 10", "name foo"); !ok {
+		t.Fatal("Config failed")
+	}
+	if err := node.ConfigWithErr("vlan 20"); err != nil {
+		t.Fatalf("ConfigWithErr failed: %s", err)
+	}
+	want := [][]string{{"vlan 10", "name foo"}, {"vlan 20"}}
+	if !reflect.DeepEqual(recorded, want) {
+		t.Fatalf("Expected %#v, got %#v", want, recorded)
+	}
+	if got := dummyConnection.GetCommands(); got[len(got)-1] != "hostname before" {
+		t.Fatalf("Recorded commands should not be sent, got %#v", got)
+	}
+	if node.RunningConfig() == "" {
+		t.Fatal("Recording node should read the running-config")
+	}
+}
+
+func TestClientRunCommandsWithInput_UnitTest(t *testing.T) {
+	commands := []Command{
+		{Cmd: "show version"},
+		{Cmd: "copy running-config startup-config", Input: "y\n"},
+	}
+	resp, err := dummyNode.RunCommandsWithInput(context.Background(), commands, "json")
+	if err != nil || len(resp.Result) != 2 {
+		t.Fatalf("RunCommandsWithInput failed: %#v %v", resp, err)
+	}
+	want := []interface{}{
+		"enable",
+		"show version",
+		map[string]interface{}{"cmd": "copy running-config startup-config", "input": "y\n"},
+	}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+
+	dummyNode.EnableAuthentication("root")
+	defer dummyNode.EnableAuthentication("")
+	dummyNode.RunCommandsWithInput(context.Background(), commands[:1], "json")
+	want = []interface{}{
+		map[string]interface{}{"cmd": "enable", "input": "root"},
+		"show version",
+	}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+}
+
+func TestClientConfigWithInput_UnitTest(t *testing.T) {
+	err := dummyNode.ConfigWithInput(context.Background(),
+		Command{Cmd: "banner motd", Input: "Authorized use only\nEOF"},
+		Command{Cmd: "hostname foo"})
+	if err != nil {
+		t.Fatalf("ConfigWithInput failed: %s", err)
+	}
+	want := []interface{}{
+		"enable",
+		"configure terminal",
+		map[string]interface{}{"cmd": "banner motd", "input": "Authorized use only\nEOF"},
+		"hostname foo",
+	}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+
+	var recorded [][]string
+	node := dummyNode.RecordingNode(func(commands []string) {
+		recorded = append(recorded, commands)
+	})
+	if err := node.ConfigWithInput(context.Background(),
+		Command{Cmd: "vlan 10"}); err != nil {
+		t.Fatalf("ConfigWithInput failed: %s", err)
+	}
+	if err := node.ConfigWithInput(context.Background(),
+		Command{Cmd: "banner motd", Input: "hi\nEOF"}); err == nil {
+		t.Fatal("Expected an error recording a command with input")
+	}
+	if want := [][]string{{"vlan 10"}}; !reflect.DeepEqual(recorded, want) {
+		t.Fatalf("Expected %#v, got %#v", want, recorded)
+	}
+}
@@ -0,0 +1,446 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// kind is the JSON type of a value seen in a sample response.
+type kind int
+
+const (
+	kindNull kind = iota
+	kindBool
+	kindInt
+	kindFloat
+	kindString
+	kindObject
+	kindArray
+	kindMixed
+)
+
+// shape describes the merged type of every value seen at one position in
+// a sample response.
+type shape struct {
+	kind   kind
+	fields map[string]*shape // members of an object
+	elem   *shape            // element of an array or value of a map
+	isMap  bool              // object with dynamic keys
+}
+
+// infer returns the shape of v, which must have been decoded with
+// json.Decoder.UseNumber.
+func infer(v interface{}) *shape {
+	switch val := v.(type) {
+	case nil:
+		return &shape{kind: kindNull}
+	case bool:
+		return &shape{kind: kindBool}
+	case json.Number:
+		if strings.ContainsAny(val.String(), ".eE") {
+			return &shape{kind: kindFloat}
+		}
+		return &shape{kind: kindInt}
+	case string:
+		return &shape{kind: kindString}
+	case []interface{}:
+		s := &shape{kind: kindArray}
+		for _, e := range val {
+			s.elem = merge(s.elem, infer(e))
+		}
+		return s
+	case map[string]interface{}:
+		s := &shape{kind: kindObject, fields: make(map[string]*shape)}
+		for k, e := range val {
+			s.fields[k] = infer(e)
+		}
+		return s
+	}
+	return &shape{kind: kindMixed}
+}
+
+// merge combines two shapes seen at the same position. Integers widen to
+// floats and anything else that disagrees becomes kindMixed.
+func merge(a, b *shape) *shape {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.kind == kindNull:
+		return b
+	case b.kind == kindNull:
+		return a
+	case a.kind == kindInt && b.kind == kindFloat,
+		a.kind == kindFloat && b.kind == kindInt:
+		return &shape{kind: kindFloat}
+	case a.kind != b.kind:
+		return &shape{kind: kindMixed}
+	case a.kind == kindArray:
+		return &shape{kind: kindArray, elem: merge(a.elem, b.elem)}
+	case a.kind == kindObject:
+		s := &shape{kind: kindObject, fields: make(map[string]*shape)}
+		for k, f := range a.fields {
+			s.fields[k] = f
+		}
+		for k, f := range b.fields {
+			s.fields[k] = merge(s.fields[k], f)
+		}
+		return s
+	}
+	return a
+}
+
+var memberKeyRegex = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+
+// resolve decides which objects are maps rather than structs. An object is
+// a map if its path is in maps or if any of its keys does not look like an
+// eAPI member name (e.g. interface names, addresses or vlan ids). path is
+// the dotted list of keys leading to s, with "*" standing for map values.
+func resolve(s *shape, path string, maps map[string]bool) {
+	if s == nil {
+		return
+	}
+	switch s.kind {
+	case kindArray:
+		resolve(s.elem, path, maps)
+	case kindObject:
+		s.isMap = maps[path] || len(s.fields) == 0
+		for k := range s.fields {
+			if !memberKeyRegex.MatchString(k) {
+				s.isMap = true
+			}
+		}
+		if s.isMap {
+			for _, f := range s.fields {
+				s.elem = merge(s.elem, f)
+			}
+			resolve(s.elem, joinPath(path, "*"), maps)
+			return
+		}
+		for k, f := range s.fields {
+			resolve(f, joinPath(path, k), maps)
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// initialisms are upper-cased when they make up a whole word of a name.
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ARP": true, "ASCII": true, "BGP": true,
+	"CPU": true, "DNS": true, "EOF": true, "GUID": true, "HTML": true,
+	"HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"LLDP": true, "MAC": true, "MTU": true, "PTP": true, "RAM": true,
+	"RPC": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UID": true, "URI": true, "URL": true, "UUID": true,
+	"VRF": true, "XML": true,
+}
+
+// splitWords splits s into words at non-alphanumeric characters and at
+// lower to upper case transitions.
+func splitWords(s string) []string {
+	var words []string
+	var word []rune
+	var prev rune
+	for _, r := range s {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = nil
+		case unicode.IsUpper(r) && len(word) > 0 &&
+			(unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			words = append(words, string(word))
+			word = []rune{r}
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+// goName returns the exported Go identifier for the JSON key or CLI
+// command s, e.g. "systemMacAddress" becomes "SystemMACAddress" and
+// "show ip bgp summary" becomes "ShowIPBGPSummary".
+func goName(s string) string {
+	var b strings.Builder
+	for _, w := range splitWords(s) {
+		if up := strings.ToUpper(w); initialisms[up] {
+			b.WriteString(up)
+			continue
+		}
+		r := []rune(w)
+		b.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// singular returns name with a plural suffix removed. It names the
+// element type of arrays and maps.
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"),
+		strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "ies"):
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") &&
+		!strings.HasSuffix(name, "us"):
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+// config holds the options for a single generator run.
+type config struct {
+	Cmd      string          // CLI command the response belongs to
+	TypeName string          // name of the top-level struct
+	Package  string          // package of the generated files
+	Revision int             // model revision of the sample, if pinned
+	Maps     map[string]bool // paths of objects to generate as maps
+}
+
+// generator emits the struct declarations for a resolved shape.
+type generator struct {
+	cfg     config
+	buf     bytes.Buffer
+	pending []*typeDecl
+	used    map[string]bool
+}
+
+type typeDecl struct {
+	name string
+	s    *shape
+}
+
+// sampleForm is the layout of a captured response.
+type sampleForm int
+
+const (
+	formResult   sampleForm = iota // a bare command result
+	formResults                    // a list of command results
+	formResponse                   // a full JSON-RPC response
+)
+
+// readSample decodes a captured response. Where data holds several
+// results, the last one is used.
+func readSample(data []byte) (interface{}, sampleForm, error) {
+	var sample interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&sample); err != nil {
+		return nil, formResult, err
+	}
+	form := formResult
+	if list, ok := sample.([]interface{}); ok {
+		if len(list) == 0 {
+			return nil, formResults, fmt.Errorf("Sample has no results")
+		}
+		sample, form = list[len(list)-1], formResults
+	}
+	obj, ok := sample.(map[string]interface{})
+	if !ok {
+		return nil, form, fmt.Errorf("Sample is not a JSON object")
+	}
+	if _, found := obj["jsonrpc"]; !found || form != formResult {
+		return sample, form, nil
+	}
+	if obj["error"] != nil {
+		return nil, formResponse, fmt.Errorf("Sample is an error response")
+	}
+	results, _ := obj["result"].([]interface{})
+	if len(results) == 0 {
+		return nil, formResponse, fmt.Errorf("Sample response has no results")
+	}
+	return results[len(results)-1], formResponse, nil
+}
+
+// generate returns the formatted source declaring cfg.TypeName and its
+// nested types for the response in data.
+func generate(cfg config, data []byte) ([]byte, error) {
+	sample, _, err := readSample(data)
+	if err != nil {
+		return nil, err
+	}
+	s := infer(sample)
+	resolve(s, "", cfg.Maps)
+	if s.isMap {
+		return nil, fmt.Errorf("Top-level response must be a struct")
+	}
+
+	g := &generator{cfg: cfg, used: make(map[string]bool)}
+	fmt.Fprintf(&g.buf, "// Code generated by goeapi-gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&g.buf, "package %s\n", cfg.Package)
+	g.declare(cfg.TypeName, s)
+	for len(g.pending) > 0 {
+		decl := g.pending[0]
+		g.pending = g.pending[1:]
+		g.emitStruct(decl)
+		if decl.name == cfg.TypeName {
+			fmt.Fprintf(&g.buf, "\n// GetCmd returns the command type this EapiCommand relates to\n")
+			fmt.Fprintf(&g.buf, "func (s *%s) GetCmd() string {\n\treturn %q\n}\n",
+				cfg.TypeName, cfg.Cmd)
+			if cfg.Revision > 0 {
+				fmt.Fprintf(&g.buf, "\n// GetRevision returns the revision of the '%s' model\n", cfg.Cmd)
+				fmt.Fprintf(&g.buf, "// %s was generated from\n", cfg.TypeName)
+				fmt.Fprintf(&g.buf, "func (s *%s) GetRevision() int {\n\treturn %d\n}\n",
+					cfg.TypeName, cfg.Revision)
+			}
+		}
+	}
+	return format.Source(g.buf.Bytes())
+}
+
+// declare queues a struct declaration for s and returns its unique name.
+func (g *generator) declare(name string, s *shape) string {
+	unique := name
+	for i := 2; g.used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.used[unique] = true
+	g.pending = append(g.pending, &typeDecl{name: unique, s: s})
+	return unique
+}
+
+// typeOf returns the Go type for s, declaring a struct named name if s
+// is one.
+func (g *generator) typeOf(s *shape, name string) string {
+	if s == nil {
+		return "interface{}"
+	}
+	switch s.kind {
+	case kindBool:
+		return "bool"
+	case kindInt:
+		return "int"
+	case kindFloat:
+		return "float64"
+	case kindString:
+		return "string"
+	case kindArray:
+		return "[]" + g.typeOf(s.elem, singular(name))
+	case kindObject:
+		if s.isMap {
+			return "map[string]" + g.typeOf(s.elem, singular(name))
+		}
+		return g.declare(name, s)
+	}
+	return "interface{}"
+}
+
+func (g *generator) emitStruct(decl *typeDecl) {
+	keys := make([]string, 0, len(decl.s.fields))
+	for k := range decl.s.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(&g.buf, "\n// %s defined data structure for mapping JSON response\n", decl.name)
+	fmt.Fprintf(&g.buf, "// of '%s' to manageable object\n", g.cfg.Cmd)
+	fmt.Fprintf(&g.buf, "type %s struct {\n", decl.name)
+	fields := make(map[string]bool)
+	for _, k := range keys {
+		field := goName(k)
+		for i := 2; fields[field]; i++ {
+			field = fmt.Sprintf("%s%d", goName(k), i)
+		}
+		fields[field] = true
+		typ := g.typeOf(decl.s.fields[k], decl.name+field)
+		fmt.Fprintf(&g.buf, "\t%s %s `json:%q`\n", field, typ, k)
+	}
+	fmt.Fprintf(&g.buf, "}\n")
+}
+
+// generateTest returns the formatted source of a unit test that decodes
+// the fixture at fixturePath into cfg.TypeName and fails on any member
+// the struct does not declare. fixturePath is relative to the directory
+// of the test.
+func generateTest(cfg config, data []byte, fixturePath string) ([]byte, error) {
+	_, form, err := readSample(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by goeapi-gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", cfg.Package)
+	fmt.Fprintf(&b, "import (\n\t\"bytes\"\n\t\"encoding/json\"\n\t\"os\"\n\t\"testing\"\n)\n\n")
+	fmt.Fprintf(&b, "func Test%s_UnitTest(t *testing.T) {\n", cfg.TypeName)
+	fmt.Fprintf(&b, "\tdata, err := os.ReadFile(%q)\n", filepath.ToSlash(fixturePath))
+	fmt.Fprintf(&b, "\tif err != nil {\n\t\tt.Fatal(err)\n\t}\n")
+	switch form {
+	case formResults:
+		fmt.Fprintf(&b, "\tvar results []json.RawMessage\n")
+		fmt.Fprintf(&b, "\tif err := json.Unmarshal(data, &results); err != nil || len(results) == 0 {\n")
+		fmt.Fprintf(&b, "\t\tt.Fatalf(\"Invalid fixture: %%v\", err)\n\t}\n")
+		fmt.Fprintf(&b, "\tdata = results[len(results)-1]\n")
+	case formResponse:
+		fmt.Fprintf(&b, "\tvar resp struct {\n\t\tResult []json.RawMessage `json:\"result\"`\n\t}\n")
+		fmt.Fprintf(&b, "\tif err := json.Unmarshal(data, &resp); err != nil || len(resp.Result) == 0 {\n")
+		fmt.Fprintf(&b, "\t\tt.Fatalf(\"Invalid fixture: %%v\", err)\n\t}\n")
+		fmt.Fprintf(&b, "\tdata = resp.Result[len(resp.Result)-1]\n")
+	}
+	fmt.Fprintf(&b, "\n\tvar v %s\n", cfg.TypeName)
+	fmt.Fprintf(&b, "\tdec := json.NewDecoder(bytes.NewReader(data))\n")
+	fmt.Fprintf(&b, "\tdec.DisallowUnknownFields()\n")
+	fmt.Fprintf(&b, "\tif err := dec.Decode(&v); err != nil {\n")
+	fmt.Fprintf(&b, "\t\tt.Fatalf(\"Decoding fixture failed: %%v\", err)\n\t}\n")
+	fmt.Fprintf(&b, "\tif got := v.GetCmd(); got != %q {\n", cfg.Cmd)
+	fmt.Fprintf(&b, "\t\tt.Fatalf(\"Expected %%q, got %%q\", %q, got)\n\t}\n", cfg.Cmd)
+	fmt.Fprintf(&b, "}\n")
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,190 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGoName_UnitTest(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"show ip bgp summary", "ShowIPBGPSummary"},
+		{"show mac address-table", "ShowMACAddressTable"},
+		{"systemMacAddress", "SystemMACAddress"},
+		{"internalBuildId", "InternalBuildID"},
+		{"ipv4Routes", "Ipv4Routes"},
+		{"lastSyncTime", "LastSyncTime"},
+		{"10", "X10"},
+	}
+	for _, tt := range tests {
+		if got := goName(tt.in); got != tt.want {
+			t.Errorf("goName(%q): expected %q, got %q", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestSingular_UnitTest(t *testing.T) {
+	tests := map[string]string{
+		"Peers":     "Peer",
+		"Addresses": "Address",
+		"Entries":   "Entry",
+		"Status":    "Status",
+		"Access":    "Access",
+		"EntryList": "EntryList",
+	}
+	for in, want := range tests {
+		if got := singular(in); got != want {
+			t.Errorf("singular(%q): expected %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestGenerate_UnitTest(t *testing.T) {
+	sample := `{
+		"vrfs": {"default": {"routerId": "1.1.1.1", "peers": {
+			"10.0.0.1": {"asn": 65001, "upDownTime": 1524094401.78},
+			"10.0.0.2": {"asn": 65002, "upDownTime": 0, "underMaintenance": true}
+		}}},
+		"entries": [{"count": 1}, {"count": 2.5, "note": null}],
+		"tags": [],
+		"value": null
+	}`
+	cfg := config{
+		Cmd:      "show bgp test",
+		TypeName: "ShowBgpTest",
+		Package:  "module",
+		Maps:     map[string]bool{"vrfs": true},
+	}
+	src, err := generate(cfg, []byte(sample))
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "", src, 0); err != nil {
+		t.Fatalf("Generated source does not parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{
+		"package module\n",
+		"Vrfs    map[string]ShowBgpTestVrf `json:\"vrfs\"`",
+		"Peers    map[string]ShowBgpTestVrfPeer `json:\"peers\"`",
+		"RouterID string",
+		"UpDownTime       float64 `json:\"upDownTime\"`",
+		"UnderMaintenance bool",
+		"Entries []ShowBgpTestEntry",
+		"Count float64     `json:\"count\"`",
+		"Note  interface{} `json:\"note\"`",
+		"Tags    []interface{}",
+		"Value   interface{}",
+		"func (s *ShowBgpTest) GetCmd() string {\n\treturn \"show bgp test\"\n}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Expected generated source to contain %q:\n%s", want, src)
+		}
+	}
+	if strings.Contains(string(src), "GetRevision") {
+		t.Errorf("Expected no GetRevision without a revision:\n%s", src)
+	}
+}
+
+func TestGenerateFixture_UnitTest(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/fixtures/show_interfaces.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{Cmd: "show interfaces", TypeName: "ShowInterfaces", Package: "module",
+		Revision: 2}
+	src, err := generate(cfg, data)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	for _, want := range []string{
+		"Interfaces map[string]ShowInterfacesInterface `json:\"interfaces\"`",
+		"AlignmentErrors int `json:\"alignmentErrors\"`",
+		"func (s *ShowInterfaces) GetRevision() int {\n\treturn 2\n}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Expected generated source to contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateTest_UnitTest(t *testing.T) {
+	cfg := config{Cmd: "show version", TypeName: "ShowVersion", Package: "module"}
+	tests := []struct {
+		sample string
+		want   string
+	}{
+		{`{"version": "4.30"}`, "var v ShowVersion"},
+		{`[{}, {"version": "4.30"}]`, "data = results[len(results)-1]"},
+		{`{"jsonrpc": "2.0", "id": "1", "result": [{}, {"version": "4.30"}]}`,
+			"data = resp.Result[len(resp.Result)-1]"},
+	}
+	for _, tt := range tests {
+		src, err := generateTest(cfg, []byte(tt.sample), "../testdata/fixtures/show_version.json")
+		if err != nil {
+			t.Fatalf("generateTest failed: %v", err)
+		}
+		for _, want := range []string{
+			tt.want,
+			"func TestShowVersion_UnitTest(t *testing.T) {",
+			`os.ReadFile("../testdata/fixtures/show_version.json")`,
+			"dec.DisallowUnknownFields()",
+		} {
+			if !strings.Contains(string(src), want) {
+				t.Errorf("Expected generated test to contain %q:\n%s", want, src)
+			}
+		}
+	}
+}
+
+func TestGenerateErrors_UnitTest(t *testing.T) {
+	cfg := config{Cmd: "show version", TypeName: "ShowVersion", Package: "module"}
+	for _, sample := range []string{
+		``,
+		`"version"`,
+		`[]`,
+		`{"Ethernet1": {}}`,
+		`{"jsonrpc": "2.0", "error": {"code": 1002, "message": "failed"}}`,
+		`{"jsonrpc": "2.0", "result": []}`,
+	} {
+		if _, err := generate(cfg, []byte(sample)); err == nil {
+			t.Errorf("Expected an error for sample %q", sample)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+// Command goeapi-gen generates the response struct of an eAPI show command
+// from a captured JSON response, such as those in testdata/fixtures.
+//
+// The generated struct has a json tag for every member of the response
+// and a GetCmd method, so it implements goeapi.EapiCommand and can be used
+// with goeapi.Run. Optionally a unit test is generated that decodes the
+// captured response and fails on any member the struct does not declare.
+//
+// Usage:
+//
+//	goeapi-gen -cmd "show ip bgp summary" [-type ShowIPBGPSummary]
+//		[-package module] [-o show_ip_bgp_summary.go]
+//		[-test show_ip_bgp_summary_test.go] [-map vrfs] [-revision 1]
+//		response.json
+//
+// Objects whose keys are not eAPI member names (interface names, addresses
+// and the like) are generated as maps. Objects that the sample does not
+// reveal as maps, such as "vrfs" holding only "default", can be forced
+// with -map, which takes a comma-separated list of dotted key paths where
+// "*" stands for the values of a map. With -revision the struct also gets
+// a GetRevision method, so that goeapi requests the model revision the
+// sample was captured with.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	cmd := flag.String("cmd", "", "CLI command the response belongs to (required)")
+	typeName := flag.String("type", "", "name of the generated struct (default derived from -cmd)")
+	pkg := flag.String("package", "module", "package of the generated files")
+	out := flag.String("o", "", "output file (default stdout)")
+	testOut := flag.String("test", "", "output file for the generated unit test")
+	maps := flag.String("map", "", "comma-separated key paths of objects to generate as maps")
+	revision := flag.Int("revision", 0, "model revision of the response, requested by the generated struct")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: goeapi-gen -cmd command [flags] response.json\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *cmd == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*cmd, *typeName, *pkg, *out, *testOut, *maps, *revision, flag.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "goeapi-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(cmd, typeName, pkg, out, testOut, maps string, revision int,
+	fixture string) error {
+	cfg := config{
+		Cmd:      cmd,
+		TypeName: typeName,
+		Package:  pkg,
+		Revision: revision,
+		Maps:     make(map[string]bool),
+	}
+	if cfg.TypeName == "" {
+		cfg.TypeName = goName(cmd)
+	}
+	for _, path := range strings.Split(maps, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			cfg.Maps[path] = true
+		}
+	}
+
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		return err
+	}
+	src, err := generate(cfg, data)
+	if err != nil {
+		return fmt.Errorf("%s: %s", fixture, err)
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(out, src, 0644)
+	}
+	if err != nil || testOut == "" {
+		return err
+	}
+
+	fixturePath, err := relativeTo(filepath.Dir(testOut), fixture)
+	if err != nil {
+		return err
+	}
+	src, err = generateTest(cfg, data, fixturePath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(testOut, src, 0644)
+}
+
+// relativeTo returns the path of file relative to dir.
+func relativeTo(dir, file string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(absDir, absFile)
+}
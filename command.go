@@ -0,0 +1,64 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+// Command is a CLI command together with the input it reads. Input answers
+// the prompts of commands such as "copy" or "reload", or is fed to "bash"
+// as a heredoc. A Command without Input is sent as a plain command.
+type Command struct {
+	Cmd   string
+	Input string
+}
+
+// entry returns the runCmds command entry for c
+func (c Command) entry() interface{} {
+	if c.Input == "" {
+		return c.Cmd
+	}
+	return map[string]interface{}{
+		"cmd":   c.Cmd,
+		"input": c.Input,
+	}
+}
+
+// commandEntries returns the runCmds command entries for commands
+func commandEntries(commands []Command) []interface{} {
+	if len(commands) == 0 {
+		return nil
+	}
+	entries := make([]interface{}, 0, len(commands))
+	for _, cmd := range commands {
+		entries = append(entries, cmd.entry())
+	}
+	return entries
+}
@@ -0,0 +1,310 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+// Package config parses the text configuration of an EOS node into a tree
+// of lines.
+//
+// EOS nests configuration by indentation: a line whose following lines are
+// indented further is a section (such as "interface Ethernet1") and the
+// indented lines are its children. Parse builds the tree, which can be
+// navigated from parent to children and back, queried by path and
+// serialized back to text.
+//
+// A path is a list of elements separated by ">", each selecting the lines
+// at one level of the tree. An element selects the lines whose text is
+// equal to it, or begins with it followed by a space:
+//
+//	cfg := config.Parse(node.RunningConfig())
+//	vlan := cfg.Value("interface Ethernet1 > switchport access vlan")
+//	for _, intf := range cfg.FindAll("interface") {
+//		fmt.Println(intf.Text())
+//	}
+//
+// Diff and Merge compare two configurations and return the commands that
+// transform one into the other.
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Line is a single line of configuration. A Line with children is a
+// section.
+type Line struct {
+	raw      string
+	text     string
+	indent   int
+	parent   *Line
+	children []*Line
+}
+
+// Config is the tree of lines of a parsed configuration. The lines at the
+// root of the configuration are the children of Config.
+type Config struct {
+	Line
+}
+
+// Parse parses the text configuration of an EOS node.
+//
+// Blank lines are dropped. Comments (lines starting with "!") are kept so
+// that the configuration can be serialized unchanged, but are skipped by
+// navigation and queries. The text of banners, up to the closing EOF line,
+// is kept as the children of the banner line.
+func Parse(text string) *Config {
+	cfg := &Config{Line: Line{indent: -1}}
+	stack := []*Line{&cfg.Line}
+	var banner *Line
+	for _, raw := range strings.Split(text, "\n") {
+		raw = strings.TrimRight(raw, "\r")
+		if banner != nil {
+			banner.add(&Line{raw: raw, text: strings.TrimSpace(raw),
+				indent: banner.indent + 1})
+			if strings.TrimSpace(raw) == "EOF" {
+				banner = nil
+			}
+			continue
+		}
+		text := strings.TrimSpace(raw)
+		if text == "" {
+			continue
+		}
+		line := &Line{
+			raw:    strings.TrimRight(raw, " \t"),
+			text:   text,
+			indent: len(raw) - len(strings.TrimLeft(raw, " ")),
+		}
+		for len(stack) > 1 && stack[len(stack)-1].indent >= line.indent {
+			stack = stack[:len(stack)-1]
+		}
+		stack[len(stack)-1].add(line)
+		if strings.HasPrefix(text, "banner ") {
+			banner = line
+			continue
+		}
+		if !line.IsComment() {
+			stack = append(stack, line)
+		}
+	}
+	return cfg
+}
+
+// add appends child to the children of l
+func (l *Line) add(child *Line) {
+	child.parent = l
+	l.children = append(l.children, child)
+}
+
+// root returns true if l is the root of a Config
+func (l *Line) root() bool {
+	return l.indent < 0
+}
+
+// Text returns the text of the line without indentation
+func (l *Line) Text() string {
+	return l.text
+}
+
+// IsComment returns true if the line is a comment
+func (l *Line) IsComment() bool {
+	return strings.HasPrefix(l.text, "!")
+}
+
+// IsSection returns true if the line has children
+func (l *Line) IsSection() bool {
+	return len(l.Children()) > 0
+}
+
+// Parent returns the section containing the line, or nil if the line is at
+// the root of the configuration.
+func (l *Line) Parent() *Line {
+	if l.parent == nil || l.parent.root() {
+		return nil
+	}
+	return l.parent
+}
+
+// Children returns the lines nested in the line, excluding comments
+func (l *Line) Children() []*Line {
+	var children []*Line
+	for _, child := range l.children {
+		if !child.IsComment() {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// Path returns the path of the line, which is the text of the line
+// preceded by the text of its parents.
+func (l *Line) Path() string {
+	var words []string
+	for line := l; line != nil && !line.root(); line = line.parent {
+		words = append([]string{line.text}, words...)
+	}
+	return strings.Join(words, " > ")
+}
+
+// matches returns true if the line is selected by the path element elem
+func (l *Line) matches(elem string) bool {
+	return l.text == elem || strings.HasPrefix(l.text, elem+" ")
+}
+
+// splitPath splits path into its elements
+func splitPath(path string) []string {
+	elems := strings.Split(path, ">")
+	for idx := range elems {
+		elems[idx] = strings.Join(strings.Fields(elems[idx]), " ")
+	}
+	return elems
+}
+
+// FindAll returns the lines selected by path below l, in order
+func (l *Line) FindAll(path string) []*Line {
+	lines := []*Line{l}
+	for _, elem := range splitPath(path) {
+		var found []*Line
+		for _, line := range lines {
+			for _, child := range line.Children() {
+				if child.matches(elem) {
+					found = append(found, child)
+				}
+			}
+		}
+		lines = found
+	}
+	return lines
+}
+
+// Find returns the first line selected by path below l. A line whose text
+// is equal to the last element of path is preferred over a line that only
+// begins with it. Find returns nil if no line is selected.
+func (l *Line) Find(path string) *Line {
+	lines := l.FindAll(path)
+	if len(lines) == 0 {
+		return nil
+	}
+	elems := splitPath(path)
+	for _, line := range lines {
+		if line.text == elems[len(elems)-1] {
+			return line
+		}
+	}
+	return lines[0]
+}
+
+// Child returns the child of l whose text is equal to text, or nil
+func (l *Line) Child(text string) *Line {
+	for _, child := range l.Children() {
+		if child.text == text {
+			return child
+		}
+	}
+	return nil
+}
+
+// Has returns true if path selects a line below l
+func (l *Line) Has(path string) bool {
+	return l.Find(path) != nil
+}
+
+// Value returns the text following the last element of path in the first
+// line selected by path below l, or "" if no line is selected.
+//
+// Example:
+//
+//	section.Value("switchport access vlan") // "10"
+func (l *Line) Value(path string) string {
+	line := l.Find(path)
+	if line == nil {
+		return ""
+	}
+	elems := splitPath(path)
+	return strings.TrimSpace(strings.TrimPrefix(line.text,
+		elems[len(elems)-1]))
+}
+
+// Values returns the text following the last element of path in each line
+// selected by path below l.
+func (l *Line) Values(path string) []string {
+	elems := splitPath(path)
+	var values []string
+	for _, line := range l.FindAll(path) {
+		values = append(values, strings.TrimSpace(
+			strings.TrimPrefix(line.text, elems[len(elems)-1])))
+	}
+	return values
+}
+
+// Match returns the first child of l whose text matches re, or nil
+func (l *Line) Match(re *regexp.Regexp) *Line {
+	for _, child := range l.Children() {
+		if re.MatchString(child.text) {
+			return child
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for each line below l in depth-first order, including
+// comments. If fn returns false, the lines nested in that line are
+// skipped.
+func (l *Line) Walk(fn func(line *Line) bool) {
+	for _, child := range l.children {
+		if fn(child) {
+			child.Walk(fn)
+		}
+	}
+}
+
+// Raw returns the line as found in the configuration, with its
+// indentation.
+func (l *Line) Raw() string {
+	return l.raw
+}
+
+// String returns the text configuration of the line and of the lines
+// nested in it, each terminated by a newline. For a Config, this is the
+// whole configuration.
+func (l *Line) String() string {
+	var b strings.Builder
+	if !l.root() {
+		b.WriteString(l.raw)
+		b.WriteByte('\n')
+	}
+	l.Walk(func(line *Line) bool {
+		b.WriteString(line.raw)
+		b.WriteByte('\n')
+		return true
+	})
+	return b.String()
+}
@@ -0,0 +1,174 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package config
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const testConfig = `! Command: show running-config
+hostname veos01
+!
+interface Ethernet1
+   description uplink
+   switchport trunk group foo
+   switchport trunk group bar
+   switchport access vlan 10
+!
+interface Ethernet10
+   shutdown
+!
+router bgp 65000
+   router-id 1.1.1.1
+   neighbor 1.1.1.2 remote-as 65001
+   !
+   vrf blue
+      rd 1:1
+      neighbor 2.2.2.2 remote-as 65002
+!
+banner motd
+interface Ethernet2
+EOF
+end
+`
+
+func TestParseTree_UnitTest(t *testing.T) {
+	cfg := Parse(testConfig)
+
+	var texts []string
+	for _, line := range cfg.Children() {
+		texts = append(texts, line.Text())
+	}
+	want := "hostname veos01,interface Ethernet1,interface Ethernet10," +
+		"router bgp 65000,banner motd,end"
+	if got := strings.Join(texts, ","); got != want {
+		t.Fatalf("Expected root lines %q, got %q", want, got)
+	}
+
+	vrf := cfg.Find("router bgp > vrf blue")
+	if vrf == nil || !vrf.IsSection() || len(vrf.Children()) != 2 {
+		t.Fatalf("Unexpected vrf section %#v", vrf)
+	}
+	if vrf.Parent() != cfg.Find("router bgp 65000") ||
+		vrf.Parent().Parent() != nil {
+		t.Fatal("Unexpected parents for vrf section")
+	}
+	rd := vrf.Find("rd")
+	if rd.Path() != "router bgp 65000 > vrf blue > rd 1:1" {
+		t.Fatalf("Unexpected path %q", rd.Path())
+	}
+	if rd.IsSection() || rd.Raw() != "      rd 1:1" {
+		t.Fatalf("Unexpected line %q", rd.Raw())
+	}
+
+	banner := cfg.Find("banner motd")
+	if len(banner.Children()) != 2 || cfg.Has("interface Ethernet2") {
+		t.Fatal("Banner text should not be parsed as config")
+	}
+}
+
+func TestParseQueries_UnitTest(t *testing.T) {
+	cfg := Parse(testConfig)
+
+	tests := []struct {
+		path   string
+		values []string
+	}{
+		{"interface Ethernet1 > switchport access vlan", []string{"10"}},
+		{"interface Ethernet1 > switchport trunk group", []string{"foo", "bar"}},
+		{"interface > shutdown", []string{""}},
+		{"interface", []string{"Ethernet1", "Ethernet10"}},
+		{"  router   bgp>neighbor  ", []string{"1.1.1.2 remote-as 65001"}},
+		{"router bgp > vrf > neighbor 2.2.2.2 remote-as", []string{"65002"}},
+		{"interface Ethernet1 > switchport trunk", []string{"group foo", "group bar"}},
+		{"interface Ethernet1 > switchport trun", nil},
+		{"interface Ethernet2", nil},
+		{"interface Ethernet > description", nil},
+	}
+	for _, tt := range tests {
+		values := cfg.Values(tt.path)
+		if strings.Join(values, ",") != strings.Join(tt.values, ",") {
+			t.Fatalf("Values(%q): expected %q, got %q", tt.path, tt.values, values)
+		}
+		if len(tt.values) > 0 && cfg.Value(tt.path) != tt.values[0] {
+			t.Fatalf("Value(%q): expected %q, got %q", tt.path, tt.values[0],
+				cfg.Value(tt.path))
+		}
+	}
+
+	if got := cfg.Find("interface Ethernet1").Text(); got != "interface Ethernet1" {
+		t.Fatalf("Find should prefer an exact match, got %q", got)
+	}
+	if cfg.Find("interface Ethernet10") == nil || cfg.Find("bogus") != nil {
+		t.Fatal("Unexpected Find result")
+	}
+	if cfg.Child("interface Ethernet1") == nil || cfg.Child("interface") != nil {
+		t.Fatal("Child should only match the whole text")
+	}
+	re := regexp.MustCompile(`^interface Ethernet1\d$`)
+	if got := cfg.Match(re); got == nil || got.Text() != "interface Ethernet10" {
+		t.Fatalf("Unexpected Match result %#v", got)
+	}
+}
+
+func TestParseString_UnitTest(t *testing.T) {
+	cfg := Parse(testConfig)
+	if got := cfg.String(); got != testConfig {
+		t.Fatalf("Expected config:\n%s\ngot:\n%s", testConfig, got)
+	}
+
+	want := "router bgp 65000\n   router-id 1.1.1.1\n" +
+		"   neighbor 1.1.1.2 remote-as 65001\n   !\n   vrf blue\n" +
+		"      rd 1:1\n      neighbor 2.2.2.2 remote-as 65002\n"
+	if got := cfg.Find("router bgp").String(); got != want {
+		t.Fatalf("Expected section:\n%s\ngot:\n%s", want, got)
+	}
+
+	data, err := ioutil.ReadFile("../testdata/fixtures/running_config.text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	want = strings.Join(lines, "\n") + "\n"
+	if got := Parse(string(data)).String(); got != want {
+		t.Fatal("Parsed running-config does not serialize to the same text")
+	}
+}
@@ -0,0 +1,267 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package config
+
+import (
+	"strings"
+)
+
+// freeTextSettings are the settings whose value may be made of several
+// words.
+var freeTextSettings = map[string]bool{
+	"description": true,
+	"name":        true,
+	"comment":     true,
+}
+
+// multiValuedSettings are the settings that may be configured several
+// times with different values, each line adding a value rather than
+// replacing the previous one. This includes the sections, which may have
+// no children.
+var multiValuedSettings = []string{
+	"address-family",
+	"channel-group",
+	"interface",
+	"ip access-list",
+	"ip helper-address",
+	"ip name-server",
+	"ip prefix-list",
+	"ip route",
+	"ipv6 access-list",
+	"ipv6 route",
+	"logging host",
+	"neighbor",
+	"network",
+	"ntp server",
+	"route-map",
+	"router",
+	"switchport trunk group",
+	"trunk group",
+	"username",
+	"vlan",
+	"vrf",
+	"vxlan vlan",
+}
+
+// Diff returns the commands that transform the configuration from into the
+// configuration to, in the order they must be entered in configuration
+// mode.
+//
+// Lines of to missing from from are added and lines of from missing from
+// to are removed with their "no" form, or their "default" form for lines
+// that are already negated and for physical interfaces, which cannot be
+// removed. A line changing the value of a setting replaces the line
+// holding the previous value without removing it first. Sections are
+// compared recursively: the commands changing a section are preceded by
+// its first line, which enters the section, and followed by "exit".
+//
+// Both configurations must be of the same form: diffing a configuration
+// against the output of "show running-config all" removes every default
+// setting that the configuration does not spell out. Use Merge to only add
+// and change lines.
+//
+// Example:
+//
+//	running, _ := node.ConfigTree(goeapi.RunningConfig)
+//	commands := config.Merge(running, config.Parse(intended))
+//	err := node.ConfigWithErr(commands...)
+func Diff(from, to *Config) []string {
+	return diffSection(&from.Line, &to.Line, true)
+}
+
+// Merge returns the commands that add the lines of to missing from from,
+// in the order they must be entered in configuration mode. Unlike Diff,
+// the lines of from missing from to are kept, unless they hold a setting
+// that to changes.
+func Merge(from, to *Config) []string {
+	return diffSection(&from.Line, &to.Line, false)
+}
+
+// diffSection returns the commands transforming the children of from
+// into the children of to. Removals come first so that a section can be
+// replaced by one conflicting with it (such as "router bgp" with another
+// AS number).
+func diffSection(from, to *Line, purge bool) []string {
+	var removed, added []string
+	var newLines []*Line
+	for _, line := range to.Children() {
+		if from.Child(line.text) == nil {
+			newLines = append(newLines, line)
+		}
+	}
+
+	for _, line := range from.Children() {
+		if line.text == "end" || to.Child(line.text) != nil {
+			continue
+		}
+		if !line.IsSection() && replaced(line, newLines) {
+			continue
+		}
+		if purge {
+			removed = append(removed, negate(line.text))
+		}
+	}
+
+	for _, line := range to.Children() {
+		if line.text == "end" {
+			continue
+		}
+		match := from.Child(line.text)
+		switch {
+		case match == nil:
+			added = append(added, line.commands()...)
+		case strings.HasPrefix(line.text, "banner "):
+			if match.String() != line.String() {
+				added = append(added, line.commands()...)
+			}
+		case line.IsSection() || match.IsSection():
+			cmds := diffSection(match, line, purge)
+			if len(cmds) > 0 {
+				added = append(added, line.text)
+				added = append(added, cmds...)
+				added = append(added, "exit")
+			}
+		}
+	}
+	return append(removed, added...)
+}
+
+// commands returns the commands configuring the line and the lines nested
+// in it.
+func (l *Line) commands() []string {
+	cmds := []string{l.text}
+	if strings.HasPrefix(l.text, "banner ") {
+		for _, child := range l.children {
+			cmds = append(cmds, child.raw)
+		}
+		return cmds
+	}
+	children := l.Children()
+	if len(children) == 0 {
+		return cmds
+	}
+	for _, child := range children {
+		cmds = append(cmds, child.commands()...)
+	}
+	return append(cmds, "exit")
+}
+
+// replaced returns true if one of lines sets the setting held by line
+func replaced(line *Line, lines []*Line) bool {
+	for _, other := range lines {
+		if !other.IsSection() && sameSetting(line.text, other.text) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSetting returns true if the lines a and b configure the same
+// setting, so that entering one overrides the other.
+func sameSetting(a, b string) bool {
+	a, aNeg := negated(a)
+	b, bNeg := negated(b)
+	switch {
+	case aNeg && bNeg:
+		return a == b
+	case aNeg:
+		return a == b || a == settingKey(b)
+	case bNeg:
+		return b == a || b == settingKey(a)
+	}
+	if multiValued(a) || multiValued(b) {
+		return false
+	}
+	return settingKey(a) == settingKey(b)
+}
+
+// negated strips the "no" or "default" keyword from line, returning true
+// if one was found.
+func negated(line string) (string, bool) {
+	for _, prefix := range []string{"no ", "default "} {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimPrefix(line, prefix), true
+		}
+	}
+	return line, false
+}
+
+// settingKey returns the words of line that identify the setting it
+// configures, that is all but its value.
+func settingKey(line string) string {
+	words := strings.Fields(line)
+	switch {
+	case len(words) == 1:
+		return line
+	case freeTextSettings[words[0]]:
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], " ")
+}
+
+// multiValued returns true if line configures a multi-valued setting
+func multiValued(line string) bool {
+	for _, prefix := range multiValuedSettings {
+		if hasWordPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWordPrefix returns true if the words of prefix begin line
+func hasWordPrefix(line, prefix string) bool {
+	return line == prefix || strings.HasPrefix(line, prefix+" ")
+}
+
+// negate returns the command removing line
+func negate(line string) string {
+	if text, neg := negated(line); neg {
+		return "default " + text
+	}
+	if physicalInterface(line) {
+		return "default " + line
+	}
+	return "no " + line
+}
+
+// physicalInterface returns true if line is the first line of the section
+// of an interface that cannot be removed.
+func physicalInterface(line string) bool {
+	for _, prefix := range []string{"interface Ethernet", "interface Management"} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
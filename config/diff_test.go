@@ -0,0 +1,182 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const diffRunning = `hostname veos01
+!
+vlan 10
+   name ten
+!
+vlan 20
+!
+interface Ethernet1
+   description old uplink
+   shutdown
+   switchport access vlan 10
+   switchport trunk group foo
+   switchport trunk group bar
+!
+interface Ethernet2
+   description unused
+!
+interface Port-Channel10
+   no shutdown
+!
+router bgp 65000
+   router-id 1.1.1.1
+   neighbor 1.1.1.2 remote-as 65001
+   vrf blue
+      rd 1:1
+!
+end
+`
+
+const diffIntended = `hostname veos01
+!
+vlan 10
+   name ten
+!
+vlan 30
+   name thirty
+   state suspend
+!
+interface Ethernet1
+   description new uplink
+   no shutdown
+   switchport access vlan 30
+   switchport trunk group foo
+!
+interface Port-Channel10
+   shutdown
+!
+router bgp 65000
+   router-id 1.1.1.1
+   neighbor 1.1.1.2 remote-as 65001
+   neighbor 1.1.1.3 remote-as 65001
+   vrf blue
+      rd 1:2
+   vrf red
+      rd 2:2
+!
+end
+`
+
+func TestDiff_UnitTest(t *testing.T) {
+	want := []string{
+		"no vlan 20",
+		"default interface Ethernet2",
+		"vlan 30", "name thirty", "state suspend", "exit",
+		"interface Ethernet1",
+		"no switchport trunk group bar",
+		"description new uplink",
+		"no shutdown",
+		"switchport access vlan 30",
+		"exit",
+		"interface Port-Channel10", "shutdown", "exit",
+		"router bgp 65000",
+		"neighbor 1.1.1.3 remote-as 65001",
+		"vrf blue", "rd 1:2", "exit",
+		"vrf red", "rd 2:2", "exit",
+		"exit",
+	}
+	got := Diff(Parse(diffRunning), Parse(diffIntended))
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Expected commands:\n%s\ngot:\n%s", strings.Join(want, "\n"),
+			strings.Join(got, "\n"))
+	}
+
+	if got := Diff(Parse(diffRunning), Parse(diffRunning)); len(got) != 0 {
+		t.Fatalf("Expected no commands for identical configs, got %q", got)
+	}
+}
+
+func TestMerge_UnitTest(t *testing.T) {
+	want := []string{
+		"vlan 30", "name thirty", "state suspend", "exit",
+		"interface Ethernet1",
+		"description new uplink",
+		"no shutdown",
+		"switchport access vlan 30",
+		"exit",
+		"interface Port-Channel10", "shutdown", "exit",
+		"router bgp 65000",
+		"neighbor 1.1.1.3 remote-as 65001",
+		"vrf blue", "rd 1:2", "exit",
+		"vrf red", "rd 2:2", "exit",
+		"exit",
+	}
+	got := Merge(Parse(diffRunning), Parse(diffIntended))
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Expected commands:\n%s\ngot:\n%s", strings.Join(want, "\n"),
+			strings.Join(got, "\n"))
+	}
+}
+
+func TestDiffReplaceSection_UnitTest(t *testing.T) {
+	from := Parse("router bgp 65000\n   router-id 1.1.1.1\n")
+	to := Parse("router bgp 65001\n   router-id 1.1.1.1\n")
+	want := "no router bgp 65000,router bgp 65001,router-id 1.1.1.1,exit"
+	if got := strings.Join(Diff(from, to), ","); got != want {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestDiffSameSetting_UnitTest(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"description foo", "description bar baz", true},
+		{"switchport access vlan 10", "switchport access vlan 20", true},
+		{"shutdown", "no shutdown", true},
+		{"no ip address", "ip address 1.1.1.1/24", true},
+		{"no spanning-tree portfast", "spanning-tree portfast", true},
+		{"default mtu", "no mtu", true},
+		{"no switchport", "switchport mode trunk", false},
+		{"switchport trunk group foo", "switchport trunk group bar", false},
+		{"neighbor 1.1.1.1 remote-as 1", "neighbor 1.1.1.1 remote-as 2", false},
+		{"vlan 20", "vlan 30", false},
+		{"ip address 1.1.1.1/24", "ip address 1.1.2.1/24 secondary", false},
+	}
+	for _, tt := range tests {
+		if got := sameSetting(tt.a, tt.b); got != tt.want {
+			t.Fatalf("sameSetting(%q, %q): expected %t, got %t", tt.a, tt.b,
+				tt.want, got)
+		}
+	}
+}
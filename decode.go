@@ -0,0 +1,218 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// DecodeMetadata describes how the response to one command was decoded
+// into its EapiCommand. Paths are dotted lists of response keys, with map
+// keys and slice indexes in brackets, e.g. "vrfs[default].peers[10.0.0.1].asn".
+type DecodeMetadata struct {
+	// CommandIndex is the index of the command within the commands added
+	// to the handle.
+	CommandIndex int
+	// Command is the text of the command.
+	Command string
+	// Unused lists the response keys that no struct field decoded.
+	Unused []string
+	// Unset lists the struct fields that no response key was decoded into.
+	Unset []string
+	// Missing lists the fields of Unset that are tagged `eapi:"required"`.
+	Missing []string
+	// Exec holds the execution time of the command if timestamps were
+	// requested with SetOptions, and is nil otherwise.
+	Exec *CommandMeta
+}
+
+// DecodeError is returned by a strict EapiReqHandle when a response does
+// not match the EapiCommand it is decoded into.
+type DecodeError struct {
+	// CommandIndex is the index of the command within the commands added
+	// to the handle.
+	CommandIndex int
+	// Command is the text of the command.
+	Command string
+	// Unused lists the response keys that no struct field decoded.
+	Unused []string
+	// Missing lists the required fields absent from the response.
+	Missing []string
+	// Errors lists the type mismatches found, each naming the path of
+	// the offending key.
+	Errors []string
+}
+
+// Error returns the string representation of the DecodeError
+func (e *DecodeError) Error() string {
+	var problems []string
+	if len(e.Unused) > 0 {
+		problems = append(problems, "unknown keys: "+strings.Join(e.Unused, ", "))
+	}
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing required keys: "+strings.Join(e.Missing, ", "))
+	}
+	problems = append(problems, e.Errors...)
+	return fmt.Sprintf("Invalid response to %q: %s", e.Command,
+		strings.Join(problems, "; "))
+}
+
+// SetStrict enables or disables strict decoding for the handle. A strict
+// handle fails Call with a *DecodeError if a response has keys that the
+// EapiCommand does not declare, lacks a field tagged `eapi:"required"`, or
+// has a value of the wrong type. By default unknown keys are ignored and
+// missing fields are left at their zero value.
+func (handle *EapiReqHandle) SetStrict(strict bool) {
+	if handle != nil {
+		handle.strict = strict
+	}
+}
+
+// DecodeMetadata returns the metadata of the responses decoded by the
+// last Call, one entry per command that has an EapiCommand.
+func (handle *EapiReqHandle) DecodeMetadata() []DecodeMetadata {
+	if handle == nil {
+		return nil
+	}
+	return handle.metadata
+}
+
+// decode decodes result, the response to the command at index, into the
+// EapiCommand of cmd.
+func (handle *EapiReqHandle) decode(index int, cmd commandBlock,
+	result map[string]interface{}) (DecodeMetadata, error) {
+	meta := DecodeMetadata{CommandIndex: index, Command: commandString(cmd.command)}
+
+	var md mapstructure.Metadata
+	d, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:  "json",
+		Result:   cmd.EapiCommand,
+		Metadata: &md,
+	})
+	if err != nil {
+		return meta, err
+	}
+	err = d.Decode(result)
+
+	meta.Unused = md.Unused
+	meta.Unset = md.Unset
+	sort.Strings(meta.Unused)
+	sort.Strings(meta.Unset)
+	t := reflect.TypeOf(cmd.EapiCommand)
+	for _, path := range meta.Unset {
+		if isRequired(t, path) {
+			meta.Missing = append(meta.Missing, path)
+		}
+	}
+	if !handle.strict {
+		return meta, err
+	}
+
+	decErr := &DecodeError{
+		CommandIndex: index,
+		Command:      meta.Command,
+		Unused:       meta.Unused,
+		Missing:      meta.Missing,
+	}
+	var msErr *mapstructure.Error
+	if errors.As(err, &msErr) {
+		decErr.Errors = msErr.Errors
+		sort.Strings(decErr.Errors)
+	} else if err != nil {
+		return meta, err
+	}
+	if len(decErr.Unused) > 0 || len(decErr.Missing) > 0 || len(decErr.Errors) > 0 {
+		return meta, decErr
+	}
+	return meta, nil
+}
+
+// splitPath splits a decode path into its keys, dropping the map keys and
+// slice indexes in brackets, which may themselves contain dots.
+func splitPath(path string) []string {
+	var keys []string
+	var key strings.Builder
+	depth := 0
+	for _, r := range path {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth > 0:
+		case r == '.':
+			keys = append(keys, key.String())
+			key.Reset()
+		default:
+			key.WriteRune(r)
+		}
+	}
+	return append(keys, key.String())
+}
+
+// isRequired reports whether the field at path within type t is tagged
+// `eapi:"required"`. Fields are matched the way the decoder matches them:
+// by json tag name, or case-insensitively by field name if untagged.
+func isRequired(t reflect.Type, path string) bool {
+	var field reflect.StructField
+	for _, key := range splitPath(path) {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Map ||
+			t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return false
+		}
+		found := false
+		for i := 0; i < t.NumField() && !found; i++ {
+			field = t.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" {
+				found = strings.EqualFold(field.Name, key)
+			} else {
+				found = name == key
+			}
+		}
+		if !found {
+			return false
+		}
+		t = field.Type
+	}
+	return field.Tag.Get("eapi") == "required"
+}
@@ -0,0 +1,227 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type decodeTestPeer struct {
+	Asn   int    `json:"asn" eapi:"required"`
+	State string `json:"peerState"`
+}
+
+type decodeTestSummary struct {
+	RouterID string                    `json:"routerId" eapi:"required"`
+	Asn      int                       `json:"asn" eapi:"required"`
+	Peers    map[string]decodeTestPeer `json:"peers"`
+	Uptime   float64
+}
+
+func (s *decodeTestSummary) GetCmd() string {
+	return "show decode test"
+}
+
+// newDecodeTestNode returns a Node whose server answers every command
+// other than enable with result.
+func newDecodeTestNode(t *testing.T, result map[string]interface{}) *Node {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := JSONRPCResponse{Jsonrpc: "2.0", ID: req.ID}
+		for _, cmd := range req.Params.Cmds {
+			if cmd == "enable" {
+				resp.Result = append(resp.Result, map[string]interface{}{})
+				continue
+			}
+			resp.Result = append(resp.Result, result)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	conn := NewHTTPEapiConnection("http", addr.IP.String(), "admin", "", addr.Port)
+	return &Node{conn: conn}
+}
+
+var decodeTestResult = map[string]interface{}{
+	"routerId": "1.1.1.1",
+	"vrf":      "default",
+	"uptime":   10.5,
+	"peers": map[string]interface{}{
+		"10.0.0.1": map[string]interface{}{"asn": 65001, "peerState": "Established"},
+		"10.0.0.2": map[string]interface{}{"peerState": "Idle", "msgSent": 3},
+	},
+}
+
+func TestDecodeMetadata_UnitTest(t *testing.T) {
+	node := newDecodeTestNode(t, decodeTestResult)
+	handle, _ := node.GetHandle("json")
+	var summary decodeTestSummary
+	handle.AddCommand(&summary)
+	if err := handle.Call(); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if summary.RouterID != "1.1.1.1" || summary.Uptime != 10.5 ||
+		summary.Peers["10.0.0.1"].Asn != 65001 {
+		t.Fatalf("Unexpected response: %#v", summary)
+	}
+
+	want := []DecodeMetadata{{
+		CommandIndex: 0,
+		Command:      "show decode test",
+		Unused:       []string{"peers[10.0.0.2].msgSent", "vrf"},
+		Unset:        []string{"asn", "peers[10.0.0.2].asn"},
+		Missing:      []string{"asn", "peers[10.0.0.2].asn"},
+	}}
+	if got := handle.DecodeMetadata(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected metadata %#v, got %#v", want, got)
+	}
+}
+
+func TestDecodeStrict_UnitTest(t *testing.T) {
+	node := newDecodeTestNode(t, decodeTestResult)
+	handle, _ := node.GetHandle("json")
+	handle.SetStrict(true)
+	var version runTestHostname
+	var summary decodeTestSummary
+	handle.AddCommandStr("show hostname", &version)
+	handle.AddCommand(&summary)
+
+	err := handle.Call()
+	var decErr *DecodeError
+	if !errors.As(err, &decErr) {
+		t.Fatalf("Expected a DecodeError, got %v", err)
+	}
+	if decErr.CommandIndex != 0 || decErr.Command != "show hostname" {
+		t.Fatalf("Unexpected DecodeError: %#v", decErr)
+	}
+
+	handle, _ = node.GetHandle("json")
+	handle.SetStrict(true)
+	handle.AddCommand(&summary)
+	err = handle.Call()
+	if !errors.As(err, &decErr) {
+		t.Fatalf("Expected a DecodeError, got %v", err)
+	}
+	want := &DecodeError{
+		CommandIndex: 0,
+		Command:      "show decode test",
+		Unused:       []string{"peers[10.0.0.2].msgSent", "vrf"},
+		Missing:      []string{"asn", "peers[10.0.0.2].asn"},
+	}
+	if !reflect.DeepEqual(decErr, want) {
+		t.Fatalf("Expected %#v, got %#v", want, decErr)
+	}
+	if decErr.Error() != `Invalid response to "show decode test": `+
+		`unknown keys: peers[10.0.0.2].msgSent, vrf; missing required keys: `+
+		`asn, peers[10.0.0.2].asn` {
+		t.Fatalf("Unexpected error string: %s", decErr)
+	}
+}
+
+func TestDecodeStrictTypeMismatch_UnitTest(t *testing.T) {
+	node := newDecodeTestNode(t, map[string]interface{}{
+		"routerId": "1.1.1.1",
+		"asn":      65000,
+		"peers": map[string]interface{}{
+			"10.0.0.1": map[string]interface{}{"asn": "65001"},
+		},
+	})
+	handle, _ := node.GetHandle("json")
+	handle.SetStrict(true)
+	var summary decodeTestSummary
+	handle.AddCommand(&summary)
+
+	err := handle.Call()
+	var decErr *DecodeError
+	if !errors.As(err, &decErr) || len(decErr.Errors) != 1 {
+		t.Fatalf("Expected a DecodeError with one type error, got %v", err)
+	}
+	want := "'peers[10.0.0.1].asn' expected type 'int', got unconvertible type 'string', value: '65001'"
+	if decErr.Errors[0] != want {
+		t.Fatalf("Expected %q, got %q", want, decErr.Errors[0])
+	}
+
+	handle, _ = node.GetHandle("json")
+	handle.AddCommand(&summary)
+	if err := handle.Call(); err == nil || errors.As(err, &decErr) {
+		t.Fatalf("Expected the decoder error without strict mode, got %v", err)
+	}
+}
+
+func TestDecodeStrictClean_UnitTest(t *testing.T) {
+	node := newDecodeTestNode(t, map[string]interface{}{
+		"routerId": "1.1.1.1",
+		"asn":      65000,
+	})
+	handle, _ := node.GetHandle("json")
+	handle.SetStrict(true)
+	var summary decodeTestSummary
+	handle.AddCommand(&summary)
+	if err := handle.Call(); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	meta := handle.DecodeMetadata()
+	if len(meta) != 1 || !reflect.DeepEqual(meta[0].Unset, []string{"Uptime", "peers"}) ||
+		meta[0].Missing != nil {
+		t.Fatalf("Unexpected metadata: %#v", meta)
+	}
+}
+
+func TestDecodeIsRequired_UnitTest(t *testing.T) {
+	typ := reflect.TypeOf(&decodeTestSummary{})
+	tests := map[string]bool{
+		"routerId":                  true,
+		"asn":                       true,
+		"peers":                     false,
+		"Uptime":                    false,
+		"uptime":                    false,
+		"peers[10.0.0.1].asn":       true,
+		"peers[10.0.0.1].peerState": false,
+		"bogus":                     false,
+		"routerId.bogus":            false,
+	}
+	for path, want := range tests {
+		if got := isRequired(typ, path); got != want {
+			t.Errorf("isRequired(%q): expected %t, got %t", path, want, got)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// responseDecoder decodes the HTTP response to an eAPI request
+type responseDecoder func(resp *http.Response) (*JSONRPCResponse, error)
+
+// rawExecutor is implemented by the connections able to keep the raw
+// results of a response for direct decoding, see decodeRawEapiResponse.
+type rawExecutor interface {
+	executeRaw(ctx context.Context, commands []interface{}, encoding string,
+		opts RequestOptions) (*JSONRPCResponse, error)
+}
+
+// executeRaw sends commands over conn like execute, keeping the raw
+// results of the response if conn is a rawExecutor.
+func executeRaw(ctx context.Context, conn EapiConnectionEntity,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
+	if executor, ok := conn.(rawExecutor); ok {
+		return executor.executeRaw(ctx, commands, encoding, opts)
+	}
+	return execute(ctx, conn, commands, encoding, opts)
+}
+
+// SetDirectDecode enables or disables direct decoding for the handle. A
+// direct handle decodes the response to each command straight into its
+// EapiCommand with encoding/json, skipping the intermediate
+// map[string]interface{}. This cuts the allocations made for large
+// responses by about ten times and keeps the precision of integer
+// counters, which are no longer converted to float64. Numbers decoded
+// into interface{} values are float64 either way.
+//
+// A response that encoding/json cannot decode into the EapiCommand, e.g. a
+// float for an int field, falls back to the default decoding. The
+// DecodeMetadata of responses decoded directly only holds CommandIndex,
+// Command and Exec. Direct decoding is not used by strict handles (see
+// SetStrict), and connections that do not send their requests over HTTP,
+// such as test doubles, always use the default decoding.
+func (handle *EapiReqHandle) SetDirectDecode(direct bool) {
+	if handle != nil {
+		handle.direct = direct
+	}
+}
+
+// decodeRawEapiResponse decodes resp like decodeEapiResponse, but keeps
+// the raw results of the commands for direct decoding instead of decoding
+// them into maps. The entries of Result are nil.
+func decodeRawEapiResponse(resp *http.Response) (*JSONRPCResponse, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	var raw RawJSONRPCResponse
+	if err := dec.Decode(&raw); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	v := &JSONRPCResponse{
+		Jsonrpc: raw.Jsonrpc,
+		ID:      raw.ID,
+		Result:  make([]map[string]interface{}, len(raw.Result)),
+		raw:     raw.Result,
+	}
+	if raw.Error != nil {
+		v.Error = &RespError{}
+		if err := mapstructure.Decode(raw.Error, v.Error); err != nil {
+			return nil, err
+		}
+		return v, newCommandError(v.Error)
+	}
+	extractRawMeta(v)
+	return v, nil
+}
+
+// metaKey is looked up in raw results before decoding their "_meta" member
+var metaKey = []byte(`"_meta"`)
+
+// extractRawMeta is the counterpart of extractMeta for raw results. The
+// "_meta" member is left in the results, where it is ignored by the
+// non-strict decoding of direct handles.
+func extractRawMeta(resp *JSONRPCResponse) {
+	for index, raw := range resp.raw {
+		if !bytes.Contains(raw, metaKey) {
+			continue
+		}
+		var result struct {
+			Meta *CommandMeta `json:"_meta"`
+		}
+		if json.Unmarshal(raw, &result) != nil || result.Meta == nil {
+			continue
+		}
+		if resp.Meta == nil {
+			resp.Meta = make([]CommandMeta, len(resp.raw))
+		}
+		resp.Meta[index] = *result.Meta
+	}
+}
+
+// decodeDirect decodes raw, the response to the command at index, into
+// the EapiCommand of cmd with encoding/json. The response is decoded into
+// a copy of the EapiCommand, which is only stored back on success, so
+// that if encoding/json fails raw is decoded the default way, into a map
+// then with decode, over the untouched EapiCommand.
+func (handle *EapiReqHandle) decodeDirect(index int, cmd commandBlock,
+	raw json.RawMessage) (DecodeMetadata, error) {
+	target := reflect.ValueOf(cmd.EapiCommand)
+	if target.Kind() == reflect.Ptr && !target.IsNil() {
+		fresh := reflect.New(target.Elem().Type())
+		fresh.Elem().Set(target.Elem())
+		if err := json.Unmarshal(raw, fresh.Interface()); err == nil {
+			target.Elem().Set(fresh.Elem())
+			return DecodeMetadata{CommandIndex: index,
+				Command: commandString(cmd.command)}, nil
+		}
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return DecodeMetadata{CommandIndex: index,
+			Command: commandString(cmd.command)}, err
+	}
+	delete(result, "_meta")
+	return handle.decode(index, cmd, result)
+}
+
+// resultsOf returns the results of responses, decoding the raw results
+// kept for direct decoding into maps. An error is returned if a raw result
+// is not a JSON object.
+func resultsOf(responses []*JSONRPCResponse) ([]map[string]interface{}, error) {
+	var results []map[string]interface{}
+	for _, resp := range responses {
+		if resp.raw == nil {
+			results = append(results, resp.Result...)
+			continue
+		}
+		for _, raw := range resp.raw {
+			var result map[string]interface{}
+			if err := json.Unmarshal(raw, &result); err != nil {
+				return nil, fmt.Errorf("Invalid result %d: %s", len(results), err)
+			}
+			delete(result, "_meta")
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,187 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type directTestCounters struct {
+	InOctets  int64       `json:"inOctets"`
+	OutOctets uint64      `json:"outOctets"`
+	Mtu       int         `json:"mtu"`
+	Extra     interface{} `json:"extra"`
+}
+
+func (c *directTestCounters) GetCmd() string {
+	return "show direct test"
+}
+
+var directTestResult = map[string]interface{}{
+	"inOctets":  json.Number("9007199254740993"),
+	"outOctets": json.Number("18446744073709551615"),
+	"mtu":       1500,
+	"extra":     json.Number("12"),
+}
+
+func TestDirectDecode_UnitTest(t *testing.T) {
+	node := newDecodeTestNode(t, directTestResult)
+	handle, _ := node.GetHandle("json")
+	handle.SetDirectDecode(true)
+	var counters directTestCounters
+	handle.AddCommand(&counters)
+	if err := handle.Call(); err != nil {
+		t.Fatalf("Call: %s", err)
+	}
+	want := directTestCounters{InOctets: 9007199254740993,
+		OutOctets: 18446744073709551615, Mtu: 1500, Extra: float64(12)}
+	if !reflect.DeepEqual(counters, want) {
+		t.Fatalf("Expected %#v, got %#v", want, counters)
+	}
+	meta := handle.DecodeMetadata()
+	if len(meta) != 1 || meta[0].CommandIndex != 0 ||
+		meta[0].Command != "show direct test" {
+		t.Fatalf("Unexpected metadata %#v", meta)
+	}
+
+	// a strict handle always uses the default decoding, which converts
+	// the counters to float64
+	handle.SetStrict(true)
+	counters = directTestCounters{}
+	handle.AddCommand(&counters)
+	if err := handle.Call(); err != nil {
+		t.Fatalf("Call: %s", err)
+	}
+	if counters.InOctets != 9007199254740992 {
+		t.Fatalf("Expected default decoding, got %#v", counters)
+	}
+}
+
+func TestDirectDecodeFallback_UnitTest(t *testing.T) {
+	node := newDecodeTestNode(t, map[string]interface{}{
+		"inOctets": 10, "mtu": json.Number("1500.0")})
+	handle, _ := node.GetHandle("json")
+	handle.SetDirectDecode(true)
+	var counters directTestCounters
+	handle.AddCommand(&counters)
+	if err := handle.Call(); err != nil {
+		t.Fatalf("Call: %s", err)
+	}
+	if counters.InOctets != 10 || counters.Mtu != 1500 {
+		t.Fatalf("Expected fallback decoding, got %#v", counters)
+	}
+
+	// fields set by encoding/json before it fails must not leak into the
+	// fallback, which does not promote the fields of embedded structs
+	node = newDecodeTestNode(t, map[string]interface{}{
+		"name": "direct", "mtu": json.Number("1500.0")})
+	handle, _ = node.GetHandle("json")
+	handle.SetDirectDecode(true)
+	var named directTestNamed
+	handle.AddCommand(&named)
+	if err := handle.Call(); err != nil {
+		t.Fatalf("Call: %s", err)
+	}
+	if named.Name != "" || named.Mtu != 1500 {
+		t.Fatalf("Expected a clean fallback decoding, got %#v", named)
+	}
+}
+
+type directTestName struct {
+	Name string `json:"name"`
+}
+
+type directTestNamed struct {
+	directTestName
+	Mtu int `json:"mtu"`
+}
+
+func (n *directTestNamed) GetCmd() string {
+	return "show direct test"
+}
+
+func TestDecodeRawEapiResponse_UnitTest(t *testing.T) {
+	body := `{"jsonrpc": "2.0", "id": "1", "result": [{},
+		{"output": "x", "_meta": {"execStartTime": 1.5, "execDuration": 0.25}}]}`
+	resp, err := decodeRawEapiResponse(&http.Response{StatusCode: http.StatusOK,
+		Body: io.NopCloser(bytes.NewBufferString(body))})
+	if err != nil {
+		t.Fatalf("decodeRawEapiResponse: %s", err)
+	}
+	if len(resp.Result) != 2 || len(resp.raw) != 2 || resp.ID != "1" {
+		t.Fatalf("Unexpected response %#v", resp)
+	}
+	want := []CommandMeta{{}, {ExecStartTime: 1.5, ExecDuration: 0.25}}
+	if !reflect.DeepEqual(resp.Meta, want) {
+		t.Fatalf("Expected meta %#v, got %#v", want, resp.Meta)
+	}
+	results, err := resultsOf([]*JSONRPCResponse{resp})
+	if err != nil || len(results) != 2 || results[1]["output"] != "x" || results[1]["_meta"] != nil {
+		t.Fatalf("Unexpected results %#v", results)
+	}
+
+	resp.raw[1] = json.RawMessage(`["x"]`)
+	if _, err := resultsOf([]*JSONRPCResponse{resp}); err == nil {
+		t.Fatal("Expected an error for a result that is not an object")
+	}
+	err = chunkError(&CommandError{CommandIndex: 0}, []interface{}{"enable", "show clock"},
+		1, chunk{start: 2, end: 3}, []*JSONRPCResponse{resp})
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.CommandIndex != -1 ||
+		!strings.Contains(err.Error(), "Invalid result 1") {
+		t.Fatalf("Expected joined errors, got %v", err)
+	}
+
+	body = `{"jsonrpc": "2.0", "id": "1", "error": {"code": 1002,
+		"message": "CLI command 2 of 2 'vlan 5000' failed: invalid command",
+		"data": [{}, {"errors": ["Invalid input"]}]}}`
+	_, err = decodeRawEapiResponse(&http.Response{StatusCode: http.StatusOK,
+		Body: io.NopCloser(bytes.NewBufferString(body))})
+	cmdErr, ok := err.(*CommandError)
+	if !ok || cmdErr.Code != 1002 || cmdErr.CommandIndex != 1 {
+		t.Fatalf("Expected *CommandError, got %#v", err)
+	}
+
+	_, err = decodeRawEapiResponse(&http.Response{StatusCode: http.StatusNotFound,
+		Status: "404 Not Found", Body: io.NopCloser(bytes.NewBufferString(""))})
+	if _, ok := err.(*HTTPError); !ok {
+		t.Fatalf("Expected *HTTPError, got %#v", err)
+	}
+}
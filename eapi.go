@@ -38,12 +38,13 @@ package goeapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
-
-	"github.com/mitchellh/mapstructure"
+	"regexp"
+	"strconv"
 )
 
 // Request ...
@@ -75,6 +76,12 @@ type JSONRPCResponse struct {
 	Result  []map[string]interface{} `json:"result"`
 	ID      string                   `json:"id"`
 	Error   *RespError               `json:"error"`
+	// Meta holds the execution metadata of each result when timestamps
+	// were requested, and is nil otherwise.
+	Meta []CommandMeta `json:"-"`
+	// raw holds the undecoded results when they were kept for direct
+	// decoding, in which case the entries of Result are nil.
+	raw []json.RawMessage
 }
 
 // RespError message format breakout
@@ -84,19 +91,180 @@ type RespError struct {
 	Data    interface{}
 }
 
+// CommandError is returned when eAPI rejects a runCmds request because one
+// of its commands failed. It carries the per-command details found in the
+// error data of the response so callers can inspect them with errors.As.
+type CommandError struct {
+	// Code is the JSON-RPC error code returned by eAPI.
+	Code int
+	// Message is the error message returned by eAPI.
+	Message string
+	// CommandIndex is the index of the failing command within the commands
+	// supplied by the caller, or -1 if it could not be determined or the
+	// failure occurred in a command added internally (e.g. enable).
+	CommandIndex int
+	// Command is the text of the failing command.
+	Command string
+	// Errors lists the errors reported for the failing command.
+	Errors []string
+	// Messages lists the messages reported for the failing command.
+	Messages []string
+	// Results holds the results of the commands preceding the failing one.
+	Results []map[string]interface{}
+}
+
+// Error returns the string representation of the CommandError
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("JSON Error(%d): %s", e.Code, e.Message)
+}
+
+// Busy returns true if eAPI rejected the request because it was
+// temporarily unable to handle it, rather than because a command failed.
+// Such requests may succeed if sent again.
+func (e *CommandError) Busy() bool {
+	for _, code := range busyCodes {
+		if e.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPError is returned when eAPI answers a request with an HTTP status
+// other than 200 OK.
+type HTTPError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Status is the HTTP status line of the response, e.g. "503 Service
+	// Unavailable".
+	Status string
+}
+
+// Error returns the string representation of the HTTPError
+func (e *HTTPError) Error() string {
+	return "Http error: " + e.Status
+}
+
+var cliCommandRegex = regexp.MustCompile(`CLI command (\d+) of \d+`)
+
+// newCommandError builds a CommandError from the error member of a
+// JSONRPCResponse. The error data holds one entry per command that was
+// run; the first entry carrying an "errors" member marks the failing
+// command and the entries before it are the partial results.
+func newCommandError(respErr *RespError) *CommandError {
+	cmdErr := &CommandError{
+		Code:         respErr.Code,
+		Message:      respErr.Message,
+		CommandIndex: -1,
+	}
+	data, _ := respErr.Data.([]interface{})
+	for idx, entry := range data {
+		result, _ := entry.(map[string]interface{})
+		if errs, found := result["errors"]; found {
+			cmdErr.CommandIndex = idx
+			cmdErr.Errors = toStringSlice(errs)
+			cmdErr.Messages = toStringSlice(result["messages"])
+			break
+		}
+		cmdErr.Results = append(cmdErr.Results, result)
+	}
+	if cmdErr.CommandIndex == -1 {
+		// Fall back to the 1-based command number in the message
+		if match := cliCommandRegex.FindStringSubmatch(respErr.Message); match != nil {
+			num, _ := strconv.Atoi(match[1])
+			cmdErr.CommandIndex = num - 1
+			if len(cmdErr.Results) > cmdErr.CommandIndex {
+				cmdErr.Results = cmdErr.Results[:cmdErr.CommandIndex]
+			}
+		}
+	}
+	return cmdErr
+}
+
+// rebase adjusts the CommandError for a request that had offset commands
+// prepended to those supplied by the caller so that CommandIndex and
+// Results refer to the caller's commands. commands is the full list of
+// commands that was sent and is used to fill in Command.
+func (e *CommandError) rebase(commands []interface{}, offset int) {
+	if e.CommandIndex >= 0 && e.CommandIndex < len(commands) && e.Command == "" {
+		e.Command = commandString(commands[e.CommandIndex])
+	}
+	if e.CommandIndex >= 0 {
+		e.CommandIndex -= offset
+		if e.CommandIndex < 0 {
+			e.CommandIndex = -1
+		}
+	}
+	if len(e.Results) > offset {
+		e.Results = e.Results[offset:]
+	} else {
+		e.Results = nil
+	}
+}
+
+// rebaseCommandError rebases err if it is a *CommandError. See
+// CommandError.rebase. The CommandIndex of a *DecodeError is adjusted
+// likewise.
+func rebaseCommandError(err error, commands []interface{}, offset int) error {
+	if cmdErr, ok := err.(*CommandError); ok {
+		cmdErr.rebase(commands, offset)
+	}
+	if decErr, ok := err.(*DecodeError); ok {
+		decErr.CommandIndex -= offset
+	}
+	return err
+}
+
+// commandString returns the command text of a runCmds command entry, which
+// is either a plain string or a map holding the command under "cmd".
+func commandString(cmd interface{}) string {
+	switch v := cmd.(type) {
+	case string:
+		return v
+	case map[string]string:
+		return v["cmd"]
+	case map[string]interface{}:
+		str, _ := v["cmd"].(string)
+		return str
+	}
+	return fmt.Sprintf("%v", cmd)
+}
+
+// toStringSlice converts a decoded JSON array to a []string.
+func toStringSlice(v interface{}) []string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	strs := make([]string, 0, len(list))
+	for _, entry := range list {
+		strs = append(strs, fmt.Sprintf("%v", entry))
+	}
+	return strs
+}
+
 // EapiCommand interface is implemented by any pre-defined response structure
 // associated with a command issue toward a node.
 type EapiCommand interface {
 	GetCmd() string
 }
 
+// EapiCommandRevision is implemented by an EapiCommand written against a
+// specific revision of the JSON model of its command. AddCommand requests
+// that revision from the node, so the response keeps the shape the
+// EapiCommand expects across EOS upgrades.
+type EapiCommandRevision interface {
+	EapiCommand
+	GetRevision() int
+}
+
 // commandBlock used to map command to an EapiCommand
 type commandBlock struct {
 	command interface{}
 	EapiCommand
 }
 
-// Max number of commands to take
+// Default max number of commands sent in a single request
 const (
 	maxCmdBuflen = 64
 )
@@ -107,6 +275,11 @@ type EapiReqHandle struct {
 	encoding     string
 	eapiCommands []commandBlock
 	err          error
+	strict       bool
+	metadata     []DecodeMetadata
+	opts         RequestOptions
+	chunkSize    int
+	direct       bool
 }
 
 // debugJSON prints out []byte JSON data Indented
@@ -142,22 +315,33 @@ func (handle *EapiReqHandle) getNode() (*Node, error) {
 }
 
 // AddCommandStr adds a command string with specified EapiCommand type to the
-// command block list for this EapiReqHandle.
+// command block list for this EapiReqHandle. If v implements
+// EapiCommandRevision, the revision it returns is requested.
 func AddCommandStr(handle *EapiReqHandle, command string, v EapiCommand) error {
+	return addCommand(handle, Command{Cmd: command}, v)
+}
+
+// addCommand adds command with specified EapiCommand type to the command
+// block list for this EapiReqHandle.
+func addCommand(handle *EapiReqHandle, command Command, v EapiCommand) error {
 	if err := handle.checkHandle(); err != nil {
 		return err
 	}
-	if command == "" {
+	if command.Cmd == "" {
 		handle.err = fmt.Errorf("Invalid null Command string")
 		return handle.err
 	}
-
-	if len(handle.eapiCommands) == maxCmdBuflen {
-		handle.err = fmt.Errorf("Limit of %d commands reached for AddCommand",
-			maxCmdBuflen)
-		return handle.err
+	cmd := commandBlock{command: command.entry(), EapiCommand: v}
+	if rev, ok := v.(EapiCommandRevision); ok && rev.GetRevision() > 0 {
+		entry := map[string]interface{}{
+			"cmd":      command.Cmd,
+			"revision": rev.GetRevision(),
+		}
+		if command.Input != "" {
+			entry["input"] = command.Input
+		}
+		cmd.command = entry
 	}
-	cmd := commandBlock{command: command, EapiCommand: v}
 	handle.eapiCommands = append(handle.eapiCommands, cmd)
 	return nil
 }
@@ -168,6 +352,24 @@ func (handle *EapiReqHandle) AddCommandStr(command string, v EapiCommand) error
 	return AddCommandStr(handle, command, v)
 }
 
+// AddCommandWithInput adds a command string, along with the input it
+// reads, with specified EapiCommand type to the command block list for
+// this EapiReqHandle. Input answers the prompts of commands such as "copy"
+// or "reload". v may be nil if the command has no output to decode.
+func AddCommandWithInput(handle *EapiReqHandle, command string, input string,
+	v EapiCommand) error {
+	return addCommand(handle, Command{Cmd: command, Input: input}, v)
+}
+
+// AddCommandWithInput adds a command string, along with the input it
+// reads, with specified EapiCommand type to the command block list for
+// this EapiReqHandle. Input answers the prompts of commands such as "copy"
+// or "reload". v may be nil if the command has no output to decode.
+func (handle *EapiReqHandle) AddCommandWithInput(command string, input string,
+	v EapiCommand) error {
+	return AddCommandWithInput(handle, command, input, v)
+}
+
 // AddCommand adds a pre-defined EapiCommand type to the command
 // block list for this EapiReqHandle.
 func AddCommand(handle *EapiReqHandle, v EapiCommand) error {
@@ -222,12 +424,31 @@ func (handle *EapiReqHandle) clearCommands() {
 // using AddCommand().
 //
 // Responses from issued commands are stored in the EapiCommand associated
-// with that commands response.
+// with that commands response. Commands beyond the chunk size (see
+// SetChunkSize) are sent in further requests, each restoring the
+// configuration mode the previous one left the session in. Execution stops
+// at the first failing request.
 //
 // Returns:
 //  error if handle is invalid, or problem encountered during sending or
-//  receiveing.
+//  receiveing. If a command is rejected by the node, the error is a
+//  *CommandError whose CommandIndex refers to the commands added to the
+//  handle.
 func (handle *EapiReqHandle) Call() error {
+	return handle.CallContext(context.Background())
+}
+
+// CallContext executes the commands previously added to the command block
+// using AddCommand(), binding the request to ctx.
+//
+// Responses from issued commands are stored in the EapiCommand associated
+// with that commands response. If ctx is cancelled or its deadline passes
+// before the node responds, the request is aborted and ctx.Err() is returned.
+//
+// Returns:
+//  error if handle is invalid, or problem encountered during sending or
+//  receiveing.
+func (handle *EapiReqHandle) CallContext(ctx context.Context) error {
 	if err := handle.checkHandle(); err != nil {
 		return err
 	}
@@ -235,30 +456,80 @@ func (handle *EapiReqHandle) Call() error {
 		return handle.err
 	}
 
-	var cmd interface{}
+	var enable interface{}
 	if handle.node.enablePasswd != "" {
-		cmd = map[string]string{
+		enable = map[string]string{
 			"cmd":   "enable",
 			"input": handle.node.enablePasswd,
 		}
 	} else {
-		cmd = "enable"
+		enable = "enable"
 	}
-	tmpSlice := []commandBlock{{command: cmd,
-		EapiCommand: nil}}
-	handle.eapiCommands = append(tmpSlice, handle.eapiCommands...)
 
-	commands := handle.getAllCommands()
+	blocks := handle.eapiCommands
+	chunkSize := handle.chunkSize
+	if chunkSize < 1 {
+		chunkSize = handle.node.chunkSize
+	}
+	handle.metadata = nil
+	defer handle.clearCommands()
+	send := execute
+	if handle.direct && !handle.strict {
+		send = executeRaw
+	}
 
-	jsonrsp, err := handle.node.conn.Execute(commands, handle.encoding)
-        
-	if err != nil {
-		return err
+	var done []*JSONRPCResponse
+	for _, c := range splitCommands(handle.getAllCommands(), chunkSize) {
+		// each request enters exec mode and the configuration mode left
+		// by the previous chunk before running the commands of the chunk
+		reqBlocks := []commandBlock{{command: enable}}
+		for _, entry := range c.context {
+			reqBlocks = append(reqBlocks, commandBlock{command: entry})
+		}
+		reqBlocks = append(reqBlocks, blocks[c.start:c.end]...)
+		offset := len(reqBlocks) - (c.end - c.start)
+
+		var commands []interface{}
+		for _, block := range reqBlocks {
+			commands = append(commands, block.command)
+		}
+		jsonrsp, err := send(ctx, handle.node.conn, commands,
+			handle.encoding, handle.opts)
+		if err != nil {
+			return chunkError(err, commands, offset, c, done)
+		}
+
+		mark := len(handle.metadata)
+		err = handle.parseResponse(jsonrsp, reqBlocks)
+		for i := mark; i < len(handle.metadata); i++ {
+			handle.metadata[i].CommandIndex += c.start - offset
+		}
+		if err != nil {
+			return chunkError(err, commands, offset, c, done)
+		}
+		jsonrsp.Result = jsonrsp.Result[offset:]
+		if jsonrsp.raw != nil {
+			jsonrsp.raw = jsonrsp.raw[offset:]
+		}
+		done = append(done, jsonrsp)
+	}
+	return nil
+}
+
+// SetChunkSize sets the maximum number of commands Call sends to the node
+// in a single request, overriding the chunk size of the Node (see
+// Node.SetChunkSize). Values below 1 select the chunk size of the Node.
+func (handle *EapiReqHandle) SetChunkSize(size int) {
+	if handle != nil {
+		handle.chunkSize = size
 	}
+}
 
-	err = handle.parseResponse(jsonrsp)
-	handle.clearCommands()
-	return err
+// SetOptions sets the optional runCmds parameters sent by Call.
+func (handle *EapiReqHandle) SetOptions(opts RequestOptions) {
+	if handle != nil {
+		handle.opts = opts
+	}
 }
 
 // Enable takes an EapiCommand type to issue toward the Node.
@@ -293,39 +564,42 @@ func Close(handle *EapiReqHandle) error {
 
 // parseResponse is a speciallized function to parse a JSON response for
 // one (or many) command(s) and store the result in the command block
-// associated with matching command request.
-func (handle *EapiReqHandle) parseResponse(resp *JSONRPCResponse) error {
+// associated with matching command request in blocks.
+func (handle *EapiReqHandle) parseResponse(resp *JSONRPCResponse,
+	blocks []commandBlock) error {
 	var err error
 
 	// check for errors in the JSON response
 	if resp.Error != nil {
-		err := fmt.Errorf("JSON Error(%d): %s", resp.Error.Code,
-			resp.Error.Message)
-		return err
+		return newCommandError(resp.Error)
 	}
 
-	if len(resp.Result) != len(handle.eapiCommands) {
+	if len(resp.Result) != len(blocks) {
 		err := fmt.Errorf("Number of Result entries(%d) does not match"+
 			"commands sent(%d)",
-			len(resp.Result), len(handle.eapiCommands))
+			len(resp.Result), len(blocks))
 		return err
 	}
 
 	for index, result := range resp.Result {
-		cmd := handle.eapiCommands[index]
+		cmd := blocks[index]
 		if cmd.EapiCommand == nil {
 			continue
 		}
 
-                d, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{ TagName: "json", Result: cmd.EapiCommand })
-                if err != nil {
-			return err
-                } 
-
-                err = d.Decode(result)
+		var meta DecodeMetadata
+		if resp.raw != nil {
+			meta, err = handle.decodeDirect(index, cmd, resp.raw[index])
+		} else {
+			meta, err = handle.decode(index, cmd, result)
+		}
 		if err != nil {
 			return err
 		}
+		if index < len(resp.Meta) {
+			meta.Exec = &resp.Meta[index]
+		}
+		handle.metadata = append(handle.metadata, meta)
 	}
 	return err
 }
@@ -334,7 +608,7 @@ func (handle *EapiReqHandle) parseResponse(resp *JSONRPCResponse) error {
 // structure format defined by type JSONRPCResponse
 func decodeEapiResponse(resp *http.Response) (*JSONRPCResponse, error) {
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Http error: %s", resp.Status)
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
 	dec := json.NewDecoder(resp.Body)
@@ -345,8 +619,8 @@ func decodeEapiResponse(resp *http.Response) (*JSONRPCResponse, error) {
 	}
 
 	if v.Error != nil {
-		err := fmt.Errorf("JSON Error(%d): %s", v.Error.Code, v.Error.Message)
-		return &v, err
+		return &v, newCommandError(v.Error)
 	}
+	extractMeta(&v)
 	return &v, nil
 }
@@ -32,9 +32,13 @@
 package goeapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"reflect"
 	"regexp"
 	"testing"
+	"time"
 )
 
 type ShowRunning struct {
@@ -163,7 +167,7 @@ func TestEapiRespHandlerAddCommand_UnitTest(t *testing.T) {
 		{20, 35},
 		{28, 63},
 		{1, 64},
-		{1, 64},
+		{1, 65},
 	}
 	for _, tt := range tests {
 		for i := 0; i < tt.in; i++ {
@@ -175,6 +179,59 @@ func TestEapiRespHandlerAddCommand_UnitTest(t *testing.T) {
 	}
 }
 
+type MyShowRevision struct {
+	MyShow
+	Revision int
+}
+
+func (s *MyShowRevision) GetRevision() int {
+	return s.Revision
+}
+
+func TestEapiRespHandlerAddCommandRevision_UnitTest(t *testing.T) {
+	h, _ := dummyNode.GetHandle("json")
+	h.AddCommand(&MyShowRevision{Revision: 2})
+	h.AddCommandStr("show version detail", &MyShowRevision{Revision: 3})
+	h.AddCommand(&MyShowRevision{})
+	h.AddCommand(new(MyShow))
+	if err := h.Call(); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	want := []interface{}{
+		"enable",
+		map[string]interface{}{"cmd": "show version", "revision": 2},
+		map[string]interface{}{"cmd": "show version detail", "revision": 3},
+		"show version",
+		"show version",
+	}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+}
+
+func TestEapiRespHandlerAddCommandWithInput_UnitTest(t *testing.T) {
+	h, _ := dummyNode.GetHandle("json")
+	h.AddCommandWithInput("reload now", "y\n", nil)
+	AddCommandWithInput(h, "show version", "", new(MyShow))
+	h.AddCommandWithInput("show version", "x", &MyShowRevision{Revision: 2})
+	if err := h.AddCommandWithInput("", "y\n", nil); err == nil {
+		t.Fatal("Expected an error for an empty command")
+	}
+	if err := AddCommandWithInput(nil, "reload now", "y\n", nil); err == nil {
+		t.Fatal("Expected an error for an invalid handle")
+	}
+
+	want := []interface{}{
+		map[string]interface{}{"cmd": "reload now", "input": "y\n"},
+		"show version",
+		map[string]interface{}{"cmd": "show version", "input": "x", "revision": 2},
+	}
+	if got := h.getAllCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+}
+
 func TestEapiRespHandlerGetAllCommandsChecks_UnitTest(t *testing.T) {
 	showdummy := new(MyShow)
 	node := &Node{}
@@ -218,7 +275,7 @@ func TestEapiRespHandlerClearCommands_UnitTest(t *testing.T) {
 		{10, 10},
 		{35, 35},
 		{64, 64},
-		{120, 64},
+		{120, 120},
 	}
 	for _, tt := range tests {
 		for i := 0; i < tt.in; i++ {
@@ -258,7 +315,7 @@ func TestEapiRespHandlerCloseClearCommands_UnitTest(t *testing.T) {
 		{10, 10},
 		{35, 35},
 		{64, 64},
-		{120, 64},
+		{120, 120},
 	}
 	for _, tt := range tests {
 		h, _ := node.GetHandle("json")
@@ -322,6 +379,19 @@ func TestEapiRespHandlerCallEnablePasswd_UnitTest(t *testing.T) {
 	h.Close()
 }
 
+func TestEapiRespHandlerCallContextCanceled_UnitTest(t *testing.T) {
+	showdummy := new(MyShow)
+	h, _ := dummyNode.GetHandle("json")
+	h.AddCommand(showdummy)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := h.CallContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	h.Close()
+}
+
 func TestEapiRespHandlerNilHandleClose_UnitTest(t *testing.T) {
 	node := &Node{}
 
@@ -361,19 +431,37 @@ func TestEapiRespHandlerEnable_UnitTest(t *testing.T) {
 	h.Close()
 }
 
-func TestEapiRespHandlerEnableAddError_UnitTest(t *testing.T) {
+func TestEapiRespHandlerEnableChunked_UnitTest(t *testing.T) {
 	show := new(ShowRunning)
 	h, _ := dummyNode.GetHandle("text")
 	for i := 0; i < maxCmdBuflen+1; i++ {
 		h.AddCommand(show)
 	}
-	if err := h.Enable(show); err == nil {
-		t.Fatal("Should return error on adding to full command list")
+	if err := h.Enable(show); err != nil {
+		t.Fatalf("Should send commands beyond the chunk size, got %s", err)
+	}
+	want := []interface{}{"enable", show.GetCmd(), show.GetCmd()}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected last chunk %#v, got %#v", want, got)
+	}
+	if h.getCmdLen() != 0 {
+		t.Fatal("Commands should be cleared after Call")
 	}
 	h.Close()
 	h = nil
 }
 
+func TestEapiConnectionSetTransportConfig_UnitTest(t *testing.T) {
+	conn := NewHTTPEapiConnection("http", "localhost", "admin", "", 80)
+	node := &Node{}
+	node.SetConnection(conn)
+	config := TransportConfig{MaxIdleConnsPerHost: 4, IdleConnTimeout: time.Minute}
+	node.GetConnection().(TransportConfigurer).SetTransportConfig(config)
+	if got := conn.(*HTTPEapiConnection).pool.config; got != config {
+		t.Fatalf("Expected %#v, got %#v", config, got)
+	}
+}
+
 func TestDebugJSON_UnitTest(t *testing.T) {
 	p := Parameters{1, cmdsToInterface([]string{"show version", "show interface"}), "json"}
 	req := Request{"2.0", "runCmds", p, "255"}
@@ -429,3 +517,51 @@ func TestEapiEnable_SystemTest(t *testing.T) {
 		h = nil
 	}
 }
+
+func TestEapiNewCommandError_UnitTest(t *testing.T) {
+	var resp JSONRPCResponse
+	data := `{"jsonrpc": "2.0", "id": "1", "error": {"code": 1002,
+		"message": "CLI command 3 of 3 'show bogus' failed: invalid command",
+		"data": [{}, {"version": "4.20.1F"},
+			{"errors": ["Invalid input (at token 1: 'bogus')"],
+			 "messages": ["% Invalid input"]}]}}`
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatal(err)
+	}
+	cmdErr := newCommandError(resp.Error)
+	if cmdErr.Code != 1002 || cmdErr.CommandIndex != 2 {
+		t.Fatalf("Unexpected code/index: %d/%d", cmdErr.Code, cmdErr.CommandIndex)
+	}
+	if len(cmdErr.Errors) != 1 || len(cmdErr.Messages) != 1 {
+		t.Fatalf("Unexpected errors/messages: %#v/%#v", cmdErr.Errors, cmdErr.Messages)
+	}
+	if len(cmdErr.Results) != 2 || cmdErr.Results[1]["version"] != "4.20.1F" {
+		t.Fatalf("Unexpected partial results: %#v", cmdErr.Results)
+	}
+	if cmdErr.Error() != "JSON Error(1002): "+resp.Error.Message {
+		t.Fatalf("Unexpected error string: %s", cmdErr.Error())
+	}
+
+	commands := []interface{}{"enable", "show version", "show bogus"}
+	cmdErr.rebase(commands, 1)
+	if cmdErr.CommandIndex != 1 || cmdErr.Command != "show bogus" {
+		t.Fatalf("Unexpected rebase index/command: %d/%s", cmdErr.CommandIndex,
+			cmdErr.Command)
+	}
+	if len(cmdErr.Results) != 1 || cmdErr.Results[0]["version"] != "4.20.1F" {
+		t.Fatalf("Unexpected rebased results: %#v", cmdErr.Results)
+	}
+}
+
+func TestEapiNewCommandErrorFromMessage_UnitTest(t *testing.T) {
+	respErr := &RespError{Code: 1002,
+		Message: "CLI command 2 of 2 'vlan 5000' failed: invalid command"}
+	cmdErr := newCommandError(respErr)
+	if cmdErr.CommandIndex != 1 {
+		t.Fatalf("Expected index 1, got %d", cmdErr.CommandIndex)
+	}
+	cmdErr = newCommandError(&RespError{Code: 1, Message: "Unknown"})
+	if cmdErr.CommandIndex != -1 {
+		t.Fatalf("Expected index -1, got %d", cmdErr.CommandIndex)
+	}
+}
@@ -34,16 +34,19 @@ package goeapi
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -56,6 +59,49 @@ type EapiConnectionEntity interface {
 	Error() error
 }
 
+// ContextExecutor is implemented by connections able to bind a request to
+// a context, so that it is aborted once the context is done. All the
+// connections of this package implement it.
+type ContextExecutor interface {
+	ExecuteContext(ctx context.Context, commands []interface{},
+		encoding string) (*JSONRPCResponse, error)
+}
+
+// OptionsExecutor is implemented by connections able to send the optional
+// runCmds parameters of RequestOptions. All the connections of this
+// package implement it.
+type OptionsExecutor interface {
+	ExecuteWithOptions(ctx context.Context, commands []interface{},
+		encoding string, opts RequestOptions) (*JSONRPCResponse, error)
+}
+
+// TransportConfigurer is implemented by connections whose pooled HTTP
+// transport can be tuned with a TransportConfig. All the connections of
+// this package implement it:
+//
+//	if c, ok := node.GetConnection().(goeapi.TransportConfigurer); ok {
+//		c.SetTransportConfig(goeapi.TransportConfig{MaxIdleConnsPerHost: 4})
+//	}
+type TransportConfigurer interface {
+	SetTransportConfig(config TransportConfig)
+}
+
+// RetryConfigurer is implemented by connections that resend requests
+// failing with a transient error according to a RetryPolicy. All the
+// connections of this package implement it.
+type RetryConfigurer interface {
+	SetRetryPolicy(policy RetryPolicy)
+}
+
+// closeConnection closes conn if it implements io.Closer, releasing its
+// pooled transport.
+func closeConnection(conn EapiConnectionEntity) error {
+	if closer, ok := conn.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
 // EapiConnection represents the base object for implementing an EapiConnection
 // type. This clase should not be instantiated directly
 type EapiConnection struct {
@@ -68,6 +114,89 @@ type EapiConnection struct {
 	auth             *url.Userinfo
 	timeOut          uint32
 	disableKeepAlive bool
+	pool             *transportPool
+	retryPolicy      *RetryPolicy
+}
+
+// TransportConfig holds the settings applied to the http.Transport that a
+// connection keeps for its lifetime. The transport is shared by every
+// request sent over the connection, so keep-alive connections and TLS
+// sessions are reused between calls.
+type TransportConfig struct {
+	// MaxIdleConns controls the maximum number of idle (keep-alive)
+	// connections. Zero means no limit.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost controls the maximum idle (keep-alive)
+	// connections to keep to the node. Zero uses
+	// http.DefaultMaxIdleConnsPerHost.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is the maximum amount of time an idle connection
+	// remains open before closing itself. Zero means no limit.
+	IdleConnTimeout time.Duration
+	// TLSSessionCacheSize is the number of TLS sessions cached for
+	// resumption. Zero disables the session cache.
+	TLSSessionCacheSize int
+}
+
+// DefaultTransportConfig returns the TransportConfig used by newly
+// created connections.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:        8,
+		MaxIdleConnsPerHost: 8,
+		IdleConnTimeout:     90 * time.Second,
+		TLSSessionCacheSize: 16,
+	}
+}
+
+// transportPool owns the long-lived http.Transport of a connection. The
+// transport is built lazily on the first request and torn down by Close or
+// when a setting affecting it changes.
+type transportPool struct {
+	mu        sync.Mutex
+	config    TransportConfig
+	transport *http.Transport
+}
+
+// newTransportPool returns a transportPool using DefaultTransportConfig.
+func newTransportPool() *transportPool {
+	return &transportPool{config: DefaultTransportConfig()}
+}
+
+// get returns the pooled transport, building it with newTransport if one
+// does not yet exist.
+func (p *transportPool) get(disableKeepAlive bool,
+	newTransport func() (*http.Transport, error)) (*http.Transport, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.transport != nil {
+		return p.transport, nil
+	}
+	tr, err := newTransport()
+	if err != nil {
+		return nil, err
+	}
+	tr.MaxIdleConns = p.config.MaxIdleConns
+	tr.MaxIdleConnsPerHost = p.config.MaxIdleConnsPerHost
+	tr.IdleConnTimeout = p.config.IdleConnTimeout
+	tr.DisableKeepAlives = disableKeepAlive
+	if tr.TLSClientConfig != nil && p.config.TLSSessionCacheSize > 0 {
+		tr.TLSClientConfig.ClientSessionCache =
+			tls.NewLRUClientSessionCache(p.config.TLSSessionCacheSize)
+	}
+	p.transport = tr
+	return tr, nil
+}
+
+// reset closes any idle connections held by the pooled transport and drops
+// it so that the next request builds a new one.
+func (p *transportPool) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.transport != nil {
+		p.transport.CloseIdleConnections()
+		p.transport = nil
+	}
 }
 
 // Execute the list of commands on the destination node. In the case of
@@ -92,6 +221,42 @@ func (conn *EapiConnection) Execute(commands []interface{},
 	return &JSONRPCResponse{}, fmt.Errorf("Not Currently Implemented")
 }
 
+// ExecuteContext the list of commands on the destination node using ctx. In
+// the case of EapiConnection, this serves as a base model and is not fully
+// implemented.
+//
+// Args:
+//
+//	ctx (context.Context): context controlling cancellation of the request
+//	commands ([]interface): list of commands to execute on remote node
+//	encoding (string): The encoding to send along with the request
+//	                    message to the destination node.  Valid values include
+//	                    'json' or 'text'.  This argument will influence the
+//	                    response encoding
+//
+// Returns:
+//
+//	pointer to JSONRPCResponse or error on failure
+func (conn *EapiConnection) ExecuteContext(ctx context.Context,
+	commands []interface{}, encoding string) (*JSONRPCResponse, error) {
+	if conn == nil {
+		return &JSONRPCResponse{}, fmt.Errorf("No connection")
+	}
+	return &JSONRPCResponse{}, fmt.Errorf("Not Currently Implemented")
+}
+
+// ExecuteWithOptions behaves like ExecuteContext, additionally sending the
+// runCmds parameters set in opts. In the case of EapiConnection, this
+// serves as a base model and is not fully implemented.
+func (conn *EapiConnection) ExecuteWithOptions(ctx context.Context,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
+	if conn == nil {
+		return &JSONRPCResponse{}, fmt.Errorf("No connection")
+	}
+	return &JSONRPCResponse{}, fmt.Errorf("Not Currently Implemented")
+}
+
 // Authentication Configures the user authentication for eAPI. This method
 // configures the username and password combination to use for authenticating
 // to eAPI.
@@ -172,28 +337,137 @@ func (conn *EapiConnection) SetTimeout(timeOut uint32) {
 
 // SetDisableKeepAlive sets disablekeepalive value for Connection
 func (conn *EapiConnection) SetDisableKeepAlive(disableKeepAlive bool) {
+	if conn == nil {
+		return
+	}
 	conn.disableKeepAlive = disableKeepAlive
+	conn.getPool().reset()
+}
+
+// SetTransportConfig sets the TransportConfig for Connection. Any pooled
+// transport is released and rebuilt with the new settings on the next
+// request.
+func (conn *EapiConnection) SetTransportConfig(config TransportConfig) {
+	if conn == nil {
+		return
+	}
+	pool := conn.getPool()
+	pool.reset()
+	pool.mu.Lock()
+	pool.config = config
+	pool.mu.Unlock()
+}
+
+// Close releases the pooled transport held by Connection, closing any idle
+// keep-alive connections. The Connection remains usable; a new transport is
+// created on the next request.
+func (conn *EapiConnection) Close() error {
+	if conn == nil || conn.pool == nil {
+		return nil
+	}
+	conn.pool.reset()
+	return nil
+}
+
+// getPool returns the transportPool for Connection, allocating one if the
+// Connection was not created by one of the New*EapiConnection functions.
+func (conn *EapiConnection) getPool() *transportPool {
+	if conn.pool == nil {
+		conn.pool = newTransportPool()
+	}
+	return conn.pool
+}
+
+// client returns an http.Client for a single request. The client wraps the
+// pooled transport of Connection, creating it with newTransport on first
+// use, and carries the currently configured timeout.
+func (conn *EapiConnection) client(
+	newTransport func() (*http.Transport, error)) (*http.Client, error) {
+	tr, err := conn.getPool().get(conn.disableKeepAlive, newTransport)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Timeout:   time.Duration(conn.timeOut) * time.Second,
+		Transport: tr,
+	}, nil
 }
 
 // buildJSONRequest builds a JSON request given a list of commands, encoding
-// type of either json or text, and request id. The command list input is made
-// up of a list of interface{} types. This is so associative entries and list
-// entries both can be used. Returns []byte of the built JSON request.
+// type of either json or text, request id and the optional runCmds
+// parameters in opts. The command list input is made up of a list of
+// interface{} types. This is so associative entries and list entries both
+// can be used. Returns []byte of the built JSON request.
 // Successful call returns err == nil.
 func buildJSONRequest(commands []interface{},
-	encoding string, reqid string) ([]byte, error) {
-	p := Parameters{1, commands, encoding}
+	encoding string, reqid string, opts RequestOptions) ([]byte, error) {
+	version, err := opts.version()
+	if err != nil {
+		return nil, err
+	}
+	p := requestParameters{
+		Version:       version,
+		Cmds:          commands,
+		Format:        encoding,
+		AutoComplete:  opts.AutoComplete,
+		ExpandAliases: opts.ExpandAliases,
+		Timestamps:    opts.Timestamps,
+		Streaming:     opts.Streaming,
+	}
 
-	req := Request{"2.0", "runCmds", p, reqid}
+	req := request{"2.0", "runCmds", p, reqid}
 	data, err := json.Marshal(req)
 	//debugJSON(data)
 	return data, err
 }
 
+// post sends data to url using client and decodes the eAPI response with
+// decode.
+// The request is bound to ctx, so cancelling ctx (or reaching its deadline)
+// aborts the round trip; in that case ctx.Err() is returned rather than the
+// underlying transport error.
+func (conn *EapiConnection) post(ctx context.Context, client *http.Client,
+	url string, data []byte, decode responseDecoder) (*JSONRPCResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url,
+		bytes.NewReader(data))
+	if err != nil {
+		conn.SetError(err)
+		return &JSONRPCResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		conn.SetError(err)
+		return &JSONRPCResponse{}, err
+	}
+
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = cerr
+			conn.SetError(err)
+		}
+	}()
+
+	jsonRsp, err := decode(resp)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		conn.SetError(err)
+		return jsonRsp, err
+	}
+	return jsonRsp, nil
+}
+
 // SocketEapiConnection represents the EapiConnection for handling Socket
 // level transactions
 type SocketEapiConnection struct {
 	EapiConnection
+	socketPath string
 }
 
 const defaultUnixSocket = "/var/run/command-api.sock"
@@ -215,8 +489,20 @@ const defaultUnixSocket = "/var/run/command-api.sock"
 //	Newly created SocketEapiConnection
 func NewSocketEapiConnection(transport string, host string, username string,
 	password string, port int) EapiConnectionEntity {
-	conn := EapiConnection{transport: transport, host: host, port: port, timeOut: 60}
-	return &SocketEapiConnection{conn}
+	conn := EapiConnection{transport: transport, host: host, port: port, timeOut: 60,
+		pool: newTransportPool()}
+	return &SocketEapiConnection{EapiConnection: conn, socketPath: defaultUnixSocket}
+}
+
+// SetSocketPath sets the path of the unix domain socket used by the
+// connection. The default is /var/run/command-api.sock. Any pooled
+// transport is released so the next request dials the new path.
+func (conn *SocketEapiConnection) SetSocketPath(path string) {
+	if conn == nil {
+		return
+	}
+	conn.socketPath = path
+	conn.getPool().reset()
 }
 
 // send the eAPI request to the destination node
@@ -226,49 +512,35 @@ func NewSocketEapiConnection(transport string, host string, username string,
 // object.  eAPI responds to request messages with either a success
 // message or failure message. On successful decode of the Response,
 // a JSONRPCResponse type is returned. Otherwise err is returned.
-func (conn *SocketEapiConnection) send(data []byte) (*JSONRPCResponse, error) {
+func (conn *SocketEapiConnection) send(ctx context.Context, data []byte,
+	decode responseDecoder) (*JSONRPCResponse, error) {
 	if conn == nil {
 		return &JSONRPCResponse{}, fmt.Errorf("No Connection")
 	}
 
-	timeOut := time.Duration(time.Duration(conn.timeOut) * time.Second)
-
-	// We create our fake URL. Post() will be checking the format, but it ignores
-	// the fqhn. Also we replace the Dial func with our own fakeDial() to create
-	// the socket connection. By doing this, we can leverage the
-	// client.Post/Get methods to compose our headers, etc..
+	// We create our fake URL. Do() will be checking the format, but the
+	// transport ignores the fqhn and dials the unix socket instead (see
+	// newTransport). By doing this, we can leverage the client.Do method to
+	// compose our headers, etc..
 	//
 	fakeURL := "http://localhost/command-api"
-	var fakeDial = func(proto, addr string) (conn net.Conn, err error) {
-		return net.Dial("unix", defaultUnixSocket)
-	}
-
-	client := &http.Client{
-		Timeout: timeOut,
-		Transport: &http.Transport{
-			Dial: fakeDial,
-		},
-	}
-
-	resp, err := client.Post(fakeURL, "application/json", bytes.NewReader(data))
+	client, err := conn.client(conn.newTransport)
 	if err != nil {
 		conn.SetError(err)
 		return &JSONRPCResponse{}, err
 	}
+	return conn.post(ctx, client, fakeURL, data, decode)
+}
 
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			err = cerr
-			conn.SetError(err)
-		}
-	}()
-
-	jsonRsp, err := decodeEapiResponse(resp)
-	if err != nil {
-		conn.SetError(err)
-		return jsonRsp, err
+// newTransport builds the http.Transport for SocketEapiConnection. The Dial
+// func is replaced with our own fakeDial() to create the socket connection.
+func (conn *SocketEapiConnection) newTransport() (*http.Transport, error) {
+	socketPath := conn.socketPath
+	var fakeDial = func(ctx context.Context, proto, addr string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", socketPath)
 	}
-	return jsonRsp, nil
+	return &http.Transport{DialContext: fakeDial}, nil
 }
 
 // Execute the list of commands on the destination node
@@ -292,16 +564,69 @@ func (conn *SocketEapiConnection) send(data []byte) (*JSONRPCResponse, error) {
 //	pointer to JSONRPCResponse or error on failure
 func (conn *SocketEapiConnection) Execute(commands []interface{},
 	encoding string) (*JSONRPCResponse, error) {
+	return conn.ExecuteContext(context.Background(), commands, encoding)
+}
+
+// ExecuteContext the list of commands on the destination node
+//
+// This method takes a list of commands and sends them to the
+// destination node, returning the results. It is assumed that the
+// list of commands (type []interface{}) has been properly built and
+// enable mode passwd is set if needed. On success, a reference
+// to JSONRPCResponse is returned...otherwise err is set. If ctx is
+// cancelled or its deadline passes before the response is received, the
+// request is aborted and ctx.Err() is returned.
+//
+// Args:
+//
+//	ctx (context.Context): context controlling cancellation of the request
+//	commands ([]interface): list of commands to execute on remote node
+//	encoding (string): The encoding to send along with the request
+//	                    message to the destination node.  Valid values include
+//	                    'json' or 'text'.  This argument will influence the
+//	                    response encoding
+//
+// Returns:
+//
+//	pointer to JSONRPCResponse or error on failure
+func (conn *SocketEapiConnection) ExecuteContext(ctx context.Context,
+	commands []interface{}, encoding string) (*JSONRPCResponse, error) {
+	return conn.ExecuteWithOptions(ctx, commands, encoding, RequestOptions{})
+}
+
+// ExecuteWithOptions behaves like ExecuteContext, additionally sending the
+// runCmds parameters set in opts.
+func (conn *SocketEapiConnection) ExecuteWithOptions(ctx context.Context,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
+	return conn.executeWith(ctx, commands, encoding, opts, decodeEapiResponse)
+}
+
+// executeRaw behaves like ExecuteWithOptions, keeping the raw results of
+// the response for direct decoding.
+func (conn *SocketEapiConnection) executeRaw(ctx context.Context,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
+	return conn.executeWith(ctx, commands, encoding, opts, decodeRawEapiResponse)
+}
+
+// executeWith sends the request for commands and decodes the response
+// with decode.
+func (conn *SocketEapiConnection) executeWith(ctx context.Context,
+	commands []interface{}, encoding string, opts RequestOptions,
+	decode responseDecoder) (*JSONRPCResponse, error) {
 	if conn == nil {
 		return &JSONRPCResponse{}, fmt.Errorf("No connection")
 	}
 	conn.ClearError()
-	data, err := buildJSONRequest(commands, encoding, strconv.Itoa(os.Getpid()))
+	data, err := buildJSONRequest(commands, encoding, strconv.Itoa(os.Getpid()), opts)
 	if err != nil {
 		conn.SetError(err)
 		return &JSONRPCResponse{}, err
 	}
-	return conn.send(data)
+	return conn.retry(ctx, commands, func() (*JSONRPCResponse, error) {
+		return conn.send(ctx, data, decode)
+	})
 }
 
 // HTTPLocalEapiConnection is an EapiConnection suited for local HTTP connection
@@ -336,7 +661,8 @@ func NewHTTPLocalEapiConnection(transport string, host string, username string,
 	if port == UseDefaultPortNum {
 		port = DefaultHTTPLocalPort
 	}
-	conn := EapiConnection{transport: transport, host: host, port: port, timeOut: 60}
+	conn := EapiConnection{transport: transport, host: host, port: port, timeOut: 60,
+		pool: newTransportPool()}
 	return &HTTPLocalEapiConnection{conn}
 }
 
@@ -350,15 +676,19 @@ func NewHTTPLocalEapiConnection(transport string, host string, username string,
 //
 // Args:
 //
+//	ctx (context.Context): context controlling cancellation of the request
 //	data ([]byte): data to be sent
 //
 // Returns:
 //
 //	ptr to JSONRPCResponse on success. Otherwise error will be returned.
-func (conn *HTTPLocalEapiConnection) send(data []byte) (*JSONRPCResponse, error) {
+func (conn *HTTPLocalEapiConnection) send(ctx context.Context, data []byte) (*JSONRPCResponse, error) {
 	if conn == nil {
 		return &JSONRPCResponse{}, fmt.Errorf("No Connection")
 	}
+	if err := ctx.Err(); err != nil {
+		return &JSONRPCResponse{}, err
+	}
 	return &JSONRPCResponse{}, fmt.Errorf("Not Currently Implemented")
 }
 
@@ -383,16 +713,51 @@ func (conn *HTTPLocalEapiConnection) send(data []byte) (*JSONRPCResponse, error)
 //	pointer to JSONRPCResponse or error on failure
 func (conn *HTTPLocalEapiConnection) Execute(commands []interface{},
 	encoding string) (*JSONRPCResponse, error) {
+	return conn.ExecuteContext(context.Background(), commands, encoding)
+}
+
+// ExecuteContext the list of commands
+//
+// This method takes a list of commands and sends them to the
+// destination node, returning the results. It is assumed that the
+// list of commands (type []interface{}) has been properly built and
+// enable mode passwd is set if needed. On success, a reference
+// to JSONRPCResponse is returned...otherwise err is set. If ctx is
+// cancelled or its deadline passes before the response is received, the
+// request is aborted and ctx.Err() is returned.
+//
+// Args:
+//
+//	ctx (context.Context): context controlling cancellation of the request
+//	commands ([]interface): list of commands to execute on remote node
+//	encoding (string): The encoding to send along with the request
+//	                    message to the destination node.  Valid values include
+//	                    'json' or 'text'.  This argument will influence the
+//	                    response encoding
+//
+// Returns:
+//
+//	pointer to JSONRPCResponse or error on failure
+func (conn *HTTPLocalEapiConnection) ExecuteContext(ctx context.Context,
+	commands []interface{}, encoding string) (*JSONRPCResponse, error) {
+	return conn.ExecuteWithOptions(ctx, commands, encoding, RequestOptions{})
+}
+
+// ExecuteWithOptions behaves like ExecuteContext, additionally sending the
+// runCmds parameters set in opts.
+func (conn *HTTPLocalEapiConnection) ExecuteWithOptions(ctx context.Context,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
 	if conn == nil {
 		return &JSONRPCResponse{}, fmt.Errorf("No connection")
 	}
 	conn.ClearError()
-	data, err := buildJSONRequest(commands, encoding, strconv.Itoa(os.Getpid()))
+	data, err := buildJSONRequest(commands, encoding, strconv.Itoa(os.Getpid()), opts)
 	if err != nil {
 		conn.SetError(err)
 		return &JSONRPCResponse{}, err
 	}
-	return conn.send(data)
+	return conn.send(ctx, data)
 }
 
 // HTTPEapiConnection is an EapiConnection suited for HTTP connection
@@ -423,7 +788,8 @@ func NewHTTPEapiConnection(transport string, host string, username string,
 	if port == UseDefaultPortNum {
 		port = DefaultHTTPPort
 	}
-	conn := EapiConnection{transport: transport, host: host, port: port, timeOut: 60, disableKeepAlive: false}
+	conn := EapiConnection{transport: transport, host: host, port: port, timeOut: 60,
+		disableKeepAlive: false, pool: newTransportPool()}
 	conn.Authentication(username, password)
 	return &HTTPEapiConnection{conn}
 }
@@ -438,45 +804,30 @@ func NewHTTPEapiConnection(transport string, host string, username string,
 //
 // Args:
 //
+//	ctx (context.Context): context controlling cancellation of the request
 //	data ([]byte): data to be sent
 //
 // Returns:
 //
 //	ptr to JSONRPCResponse on success. Otherwise error will be returned.
-func (conn *HTTPEapiConnection) send(data []byte) (*JSONRPCResponse, error) {
+func (conn *HTTPEapiConnection) send(ctx context.Context, data []byte,
+	decode responseDecoder) (*JSONRPCResponse, error) {
 	if conn == nil {
 		return &JSONRPCResponse{}, fmt.Errorf("No Connection")
 	}
 
-	tr := &http.Transport{
-		DisableKeepAlives: conn.disableKeepAlive,
-	}
-
-	timeOut := time.Duration(time.Duration(conn.timeOut) * time.Second)
-	client := &http.Client{
-		Timeout:   timeOut,
-		Transport: tr,
-	}
-	url := conn.getURL()
-	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	client, err := conn.client(conn.newTransport)
 	if err != nil {
 		conn.SetError(err)
 		return &JSONRPCResponse{}, err
 	}
+	url := conn.getURL()
+	return conn.post(ctx, client, url, data, decode)
+}
 
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			err = cerr
-			conn.SetError(err)
-		}
-	}()
-
-	jsonRsp, err := decodeEapiResponse(resp)
-	if err != nil {
-		conn.SetError(err)
-		return jsonRsp, err
-	}
-	return jsonRsp, nil
+// newTransport builds the http.Transport for HTTPEapiConnection.
+func (conn *HTTPEapiConnection) newTransport() (*http.Transport, error) {
+	return &http.Transport{}, nil
 }
 
 // Execute the list of commands on the destination node
@@ -500,16 +851,69 @@ func (conn *HTTPEapiConnection) send(data []byte) (*JSONRPCResponse, error) {
 //	pointer to JSONRPCResponse or error on failure
 func (conn *HTTPEapiConnection) Execute(commands []interface{},
 	encoding string) (*JSONRPCResponse, error) {
+	return conn.ExecuteContext(context.Background(), commands, encoding)
+}
+
+// ExecuteContext the list of commands on the destination node
+//
+// This method takes a list of commands and sends them to the
+// destination node, returning the results. It is assumed that the
+// list of commands (type []interface{}) has been properly built and
+// enable mode passwd is set if needed. On success, a reference
+// to JSONRPCResponse is returned...otherwise err is set. If ctx is
+// cancelled or its deadline passes before the response is received, the
+// request is aborted and ctx.Err() is returned.
+//
+// Args:
+//
+//	ctx (context.Context): context controlling cancellation of the request
+//	commands ([]interface): list of commands to execute on remote node
+//	encoding (string): The encoding to send along with the request
+//	                    message to the destination node.  Valid values include
+//	                    'json' or 'text'.  This argument will influence the
+//	                    response encoding
+//
+// Returns:
+//
+//	pointer to JSONRPCResponse or error on failure
+func (conn *HTTPEapiConnection) ExecuteContext(ctx context.Context,
+	commands []interface{}, encoding string) (*JSONRPCResponse, error) {
+	return conn.ExecuteWithOptions(ctx, commands, encoding, RequestOptions{})
+}
+
+// ExecuteWithOptions behaves like ExecuteContext, additionally sending the
+// runCmds parameters set in opts.
+func (conn *HTTPEapiConnection) ExecuteWithOptions(ctx context.Context,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
+	return conn.executeWith(ctx, commands, encoding, opts, decodeEapiResponse)
+}
+
+// executeRaw behaves like ExecuteWithOptions, keeping the raw results of
+// the response for direct decoding.
+func (conn *HTTPEapiConnection) executeRaw(ctx context.Context,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
+	return conn.executeWith(ctx, commands, encoding, opts, decodeRawEapiResponse)
+}
+
+// executeWith sends the request for commands and decodes the response
+// with decode.
+func (conn *HTTPEapiConnection) executeWith(ctx context.Context,
+	commands []interface{}, encoding string, opts RequestOptions,
+	decode responseDecoder) (*JSONRPCResponse, error) {
 	if conn == nil {
 		return &JSONRPCResponse{}, fmt.Errorf("No connection")
 	}
 	conn.ClearError()
-	data, err := buildJSONRequest(commands, encoding, strconv.Itoa(os.Getpid()))
+	data, err := buildJSONRequest(commands, encoding, strconv.Itoa(os.Getpid()), opts)
 	if err != nil {
 		conn.SetError(err)
 		return &JSONRPCResponse{}, err
 	}
-	return conn.send(data)
+	return conn.retry(ctx, commands, func() (*JSONRPCResponse, error) {
+		return conn.send(ctx, data, decode)
+	})
 }
 
 // HTTPSEapiConnection is an EapiConnection suited for HTTP connection
@@ -547,7 +951,8 @@ func NewHTTPSEapiConnection(transport string, host string, username string,
 	}
 	path := DefaultHTTPSPath
 
-	conn := EapiConnection{transport: transport, host: host, port: port, timeOut: 60, disableKeepAlive: false}
+	conn := EapiConnection{transport: transport, host: host, port: port, timeOut: 60,
+		disableKeepAlive: false, pool: newTransportPool()}
 
 	conn.Authentication(username, password)
 	return &HTTPSEapiConnection{path: path, EapiConnection: conn}
@@ -563,17 +968,28 @@ func NewHTTPSEapiConnection(transport string, host string, username string,
 //
 // Args:
 //
+//	ctx (context.Context): context controlling cancellation of the request
 //	data ([]byte): data to be sent
 //
 // Returns:
 //
 //	ptr to JSONRPCResponse on success. Otherwise error will be returned.
-func (conn *HTTPSEapiConnection) send(data []byte) (*JSONRPCResponse, error) {
+func (conn *HTTPSEapiConnection) send(ctx context.Context, data []byte,
+	decode responseDecoder) (*JSONRPCResponse, error) {
 	if conn == nil {
 		return &JSONRPCResponse{}, fmt.Errorf("No Connection")
 	}
-	timeOut := time.Duration(time.Duration(conn.timeOut) * time.Second)
+	client, err := conn.client(conn.newTransport)
+	if err != nil {
+		conn.SetError(err)
+		return &JSONRPCResponse{}, err
+	}
 	url := conn.getURL()
+	return conn.post(ctx, client, url, data, decode)
+}
+
+// newTransport builds the http.Transport for HTTPSEapiConnection.
+func (conn *HTTPSEapiConnection) newTransport() (*http.Transport, error) {
 	suites := []uint16{}
 	for _, suite := range tls.CipherSuites() {
 		suites = append(suites, suite.ID)
@@ -581,37 +997,12 @@ func (conn *HTTPSEapiConnection) send(data []byte) (*JSONRPCResponse, error) {
 	for _, suite := range tls.InsecureCipherSuites() {
 		suites = append(suites, suite.ID)
 	}
-	tr := &http.Transport{
+	return &http.Transport{
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: true,
 			CipherSuites:       suites,
 		},
-		DisableKeepAlives: conn.disableKeepAlive,
-	}
-	client := &http.Client{
-		Timeout:   timeOut,
-		Transport: tr,
-	}
-
-	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
-	if err != nil {
-		conn.SetError(err)
-		return &JSONRPCResponse{}, err
-	}
-
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			err = cerr
-			conn.SetError(err)
-		}
-	}()
-
-	jsonRsp, err := decodeEapiResponse(resp)
-	if err != nil {
-		conn.SetError(err)
-		return jsonRsp, err
-	}
-	return jsonRsp, nil
+	}, nil
 }
 
 // Execute the list of commands on the destination node
@@ -635,16 +1026,69 @@ func (conn *HTTPSEapiConnection) send(data []byte) (*JSONRPCResponse, error) {
 //	pointer to JSONRPCResponse or error on failure
 func (conn *HTTPSEapiConnection) Execute(commands []interface{},
 	encoding string) (*JSONRPCResponse, error) {
+	return conn.ExecuteContext(context.Background(), commands, encoding)
+}
+
+// ExecuteContext the list of commands on the destination node
+//
+// This method takes a list of commands and sends them to the
+// destination node, returning the results. It is assumed that the
+// list of commands (type []interface{}) has been properly built and
+// enable mode passwd is set if needed. On success, a reference
+// to JSONRPCResponse is returned...otherwise err is set. If ctx is
+// cancelled or its deadline passes before the response is received, the
+// request is aborted and ctx.Err() is returned.
+//
+// Args:
+//
+//	ctx (context.Context): context controlling cancellation of the request
+//	commands ([]interface): list of commands to execute on remote node
+//	encoding (string): The encoding to send along with the request
+//	                    message to the destination node.  Valid values include
+//	                    'json' or 'text'.  This argument will influence the
+//	                    response encoding
+//
+// Returns:
+//
+//	pointer to JSONRPCResponse or error on failure
+func (conn *HTTPSEapiConnection) ExecuteContext(ctx context.Context,
+	commands []interface{}, encoding string) (*JSONRPCResponse, error) {
+	return conn.ExecuteWithOptions(ctx, commands, encoding, RequestOptions{})
+}
+
+// ExecuteWithOptions behaves like ExecuteContext, additionally sending the
+// runCmds parameters set in opts.
+func (conn *HTTPSEapiConnection) ExecuteWithOptions(ctx context.Context,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
+	return conn.executeWith(ctx, commands, encoding, opts, decodeEapiResponse)
+}
+
+// executeRaw behaves like ExecuteWithOptions, keeping the raw results of
+// the response for direct decoding.
+func (conn *HTTPSEapiConnection) executeRaw(ctx context.Context,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
+	return conn.executeWith(ctx, commands, encoding, opts, decodeRawEapiResponse)
+}
+
+// executeWith sends the request for commands and decodes the response
+// with decode.
+func (conn *HTTPSEapiConnection) executeWith(ctx context.Context,
+	commands []interface{}, encoding string, opts RequestOptions,
+	decode responseDecoder) (*JSONRPCResponse, error) {
 	if conn == nil {
 		return &JSONRPCResponse{}, fmt.Errorf("No connection")
 	}
 	conn.ClearError()
-	data, err := buildJSONRequest(commands, encoding, strconv.Itoa(os.Getpid()))
+	data, err := buildJSONRequest(commands, encoding, strconv.Itoa(os.Getpid()), opts)
 	if err != nil {
 		conn.SetError(err)
 		return &JSONRPCResponse{}, err
 	}
-	return conn.send(data)
+	return conn.retry(ctx, commands, func() (*JSONRPCResponse, error) {
+		return conn.send(ctx, data, decode)
+	})
 }
 
 // disableCertificateVerification disables https verification
@@ -689,7 +1133,8 @@ func NewHTTPSCertsEapiConnection(transport, host, keyFile, certFile, caCertFile
 	}
 	path := DefaultHTTPSPath
 
-	conn := EapiConnection{transport: transport, host: host, port: port, timeOut: 60, disableKeepAlive: false}
+	conn := EapiConnection{transport: transport, host: host, port: port, timeOut: 60,
+		disableKeepAlive: false, pool: newTransportPool()}
 
 	return &HTTPSCertsEapiConnection{path: path, EapiConnection: conn, keyFile: keyFile, certFile: certFile, caCertFile: caCertFile}
 }
@@ -704,21 +1149,32 @@ func NewHTTPSCertsEapiConnection(transport, host, keyFile, certFile, caCertFile
 //
 // Args:
 //
+//	ctx (context.Context): context controlling cancellation of the request
 //	data ([]byte): data to be sent
 //
 // Returns:
 //
 //	ptr to JSONRPCResponse on success. Otherwise error will be returned.
-func (conn *HTTPSCertsEapiConnection) send(data []byte) (*JSONRPCResponse, error) {
+func (conn *HTTPSCertsEapiConnection) send(ctx context.Context, data []byte,
+	decode responseDecoder) (*JSONRPCResponse, error) {
 	if conn == nil {
 		return &JSONRPCResponse{}, fmt.Errorf("No Connection")
 	}
-	timeOut := time.Duration(time.Duration(conn.timeOut) * time.Second)
+	client, err := conn.client(conn.newTransport)
+	if err != nil {
+		conn.SetError(err)
+		return nil, err
+	}
 	url := conn.getURL()
+	return conn.post(ctx, client, url, data, decode)
+}
 
+// newTransport builds the http.Transport for HTTPSCertsEapiConnection. The
+// client key pair and CA certificate are read from disk once, when the
+// transport is created.
+func (conn *HTTPSCertsEapiConnection) newTransport() (*http.Transport, error) {
 	cert, err := tls.LoadX509KeyPair(conn.certFile, conn.keyFile)
 	if err != nil {
-		conn.SetError(err)
 		return nil, err
 	}
 
@@ -732,43 +1188,14 @@ func (conn *HTTPSCertsEapiConnection) send(data []byte) (*JSONRPCResponse, error
 	if conn.caCertFile != "" {
 		caCert, err := os.ReadFile(conn.caCertFile)
 		if err != nil {
-			conn.SetError(err)
 			return nil, fmt.Errorf("failed to read CA certificate file: %v", err)
 		}
 		if ok := tlsConfig.RootCAs.AppendCertsFromPEM(caCert); !ok {
-			conn.SetError(fmt.Errorf("failed to append CA certificate to pool"))
 			return nil, fmt.Errorf("failed to append CA certificate to pool")
 		}
 	}
 
-	tr := &http.Transport{
-		TLSClientConfig:   tlsConfig,
-		DisableKeepAlives: conn.disableKeepAlive,
-	}
-	client := &http.Client{
-		Timeout:   timeOut,
-		Transport: tr,
-	}
-
-	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
-	if err != nil {
-		conn.SetError(err)
-		return &JSONRPCResponse{}, err
-	}
-
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			err = cerr
-			conn.SetError(err)
-		}
-	}()
-
-	jsonRsp, err := decodeEapiResponse(resp)
-	if err != nil {
-		conn.SetError(err)
-		return jsonRsp, err
-	}
-	return jsonRsp, nil
+	return &http.Transport{TLSClientConfig: tlsConfig}, nil
 }
 
 // Execute the list of commands on the destination node
@@ -792,14 +1219,67 @@ func (conn *HTTPSCertsEapiConnection) send(data []byte) (*JSONRPCResponse, error
 //	pointer to JSONRPCResponse or error on failure
 func (conn *HTTPSCertsEapiConnection) Execute(commands []interface{},
 	encoding string) (*JSONRPCResponse, error) {
+	return conn.ExecuteContext(context.Background(), commands, encoding)
+}
+
+// ExecuteContext the list of commands on the destination node
+//
+// This method takes a list of commands and sends them to the
+// destination node, returning the results. It is assumed that the
+// list of commands (type []interface{}) has been properly built and
+// enable mode passwd is set if needed. On success, a reference
+// to JSONRPCResponse is returned...otherwise err is set. If ctx is
+// cancelled or its deadline passes before the response is received, the
+// request is aborted and ctx.Err() is returned.
+//
+// Args:
+//
+//	ctx (context.Context): context controlling cancellation of the request
+//	commands ([]interface): list of commands to execute on remote node
+//	encoding (string): The encoding to send along with the request
+//	                    message to the destination node.  Valid values include
+//	                    'json' or 'text'.  This argument will influence the
+//	                    response encoding
+//
+// Returns:
+//
+//	pointer to JSONRPCResponse or error on failure
+func (conn *HTTPSCertsEapiConnection) ExecuteContext(ctx context.Context,
+	commands []interface{}, encoding string) (*JSONRPCResponse, error) {
+	return conn.ExecuteWithOptions(ctx, commands, encoding, RequestOptions{})
+}
+
+// ExecuteWithOptions behaves like ExecuteContext, additionally sending the
+// runCmds parameters set in opts.
+func (conn *HTTPSCertsEapiConnection) ExecuteWithOptions(ctx context.Context,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
+	return conn.executeWith(ctx, commands, encoding, opts, decodeEapiResponse)
+}
+
+// executeRaw behaves like ExecuteWithOptions, keeping the raw results of
+// the response for direct decoding.
+func (conn *HTTPSCertsEapiConnection) executeRaw(ctx context.Context,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
+	return conn.executeWith(ctx, commands, encoding, opts, decodeRawEapiResponse)
+}
+
+// executeWith sends the request for commands and decodes the response
+// with decode.
+func (conn *HTTPSCertsEapiConnection) executeWith(ctx context.Context,
+	commands []interface{}, encoding string, opts RequestOptions,
+	decode responseDecoder) (*JSONRPCResponse, error) {
 	if conn == nil {
 		return &JSONRPCResponse{}, fmt.Errorf("No connection")
 	}
 	conn.ClearError()
-	data, err := buildJSONRequest(commands, encoding, strconv.Itoa(os.Getpid()))
+	data, err := buildJSONRequest(commands, encoding, strconv.Itoa(os.Getpid()), opts)
 	if err != nil {
 		conn.SetError(err)
 		return &JSONRPCResponse{}, err
 	}
-	return conn.send(data)
+	return conn.retry(ctx, commands, func() (*JSONRPCResponse, error) {
+		return conn.send(ctx, data, decode)
+	})
 }
@@ -32,6 +32,7 @@
 package goeapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -85,6 +86,14 @@ func NewDummyEapiConnection(transport string, host string, username string,
 	return &DummyEapiConnection{EapiConnection: conn, retError: false}
 }
 
+func (conn *DummyEapiConnection) ExecuteContext(ctx context.Context,
+	commands []interface{}, encoding string) (*JSONRPCResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return &JSONRPCResponse{}, err
+	}
+	return conn.Execute(commands, encoding)
+}
+
 func (conn *DummyEapiConnection) Execute(commands []interface{},
 	encoding string) (*JSONRPCResponse, error) {
 	if conn.retError {
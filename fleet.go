@@ -0,0 +1,302 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultFleetWorkers is the default number of hosts a Fleet contacts
+// concurrently.
+const DefaultFleetWorkers = 16
+
+// FleetFunc is the function run by Fleet.Run against every host. name is
+// the connection name of the host and node its Node. ctx carries the
+// per-host timeout.
+type FleetFunc func(ctx context.Context, name string, node *Node) error
+
+// FleetResult holds the outcome of a Fleet operation on a single host.
+type FleetResult struct {
+	// Name is the connection name of the host.
+	Name string
+	// Response is the response returned by the host for RunCommands. It
+	// is nil for Run.
+	Response *JSONRPCResponse
+	// Err is the error encountered on the host, if any.
+	Err error
+	// Duration is the time spent on the host, including connecting.
+	Duration time.Duration
+}
+
+// FleetStats holds aggregate statistics for a Fleet operation.
+type FleetStats struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	Duration  time.Duration
+}
+
+// FleetResults holds the per-host results of a Fleet operation keyed by
+// connection name along with aggregate statistics.
+type FleetResults struct {
+	Results map[string]*FleetResult
+	Stats   FleetStats
+}
+
+// Errors returns the errors of the failed hosts keyed by connection name.
+func (r *FleetResults) Errors() map[string]error {
+	errs := make(map[string]error)
+	for name, result := range r.Results {
+		if result.Err != nil {
+			errs[name] = result.Err
+		}
+	}
+	return errs
+}
+
+// Fleet runs the same operation concurrently across a set of connections
+// from an EapiConfig.
+//
+// Nodes are opened on first use with the settings of their connection
+// profile and kept for subsequent operations until Close is called. The
+// number of hosts contacted at once is bounded by the number of workers
+// and every host gets its own timeout.
+type Fleet struct {
+	config  *EapiConfig
+	names   []string
+	workers int
+	timeout time.Duration
+
+	mu    sync.Mutex
+	nodes map[string]*Node
+}
+
+// NewFleet creates a Fleet for the named connections of the global config.
+func NewFleet(names ...string) *Fleet {
+	return configGlobal.NewFleet(names...)
+}
+
+// NewFleetByTag creates a Fleet for the connections of the global config
+// carrying any of the given tags.
+func NewFleetByTag(tags ...string) *Fleet {
+	return configGlobal.NewFleetByTag(tags...)
+}
+
+// NewFleet creates a Fleet for the named connections of this EapiConfig.
+// A name given more than once is only included once.
+func (e *EapiConfig) NewFleet(names ...string) *Fleet {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return &Fleet{
+		config:  e,
+		names:   unique,
+		workers: DefaultFleetWorkers,
+		nodes:   make(map[string]*Node),
+	}
+}
+
+// NewFleetByTag creates a Fleet for the connections of this EapiConfig
+// carrying any of the given tags.
+func (e *EapiConfig) NewFleetByTag(tags ...string) *Fleet {
+	return e.NewFleet(e.ConnectionsByTag(tags...)...)
+}
+
+// NewFleetMatching creates a Fleet for the connections of this EapiConfig
+// whose tags are matched by selector.
+func (e *EapiConfig) NewFleetMatching(selector TagSelector) *Fleet {
+	return e.NewFleet(e.ConnectionsMatching(selector)...)
+}
+
+// Names returns the connection names of the Fleet
+func (f *Fleet) Names() []string {
+	return f.names
+}
+
+// SetWorkers sets the maximum number of hosts contacted concurrently.
+// Values lower than 1 reset to DefaultFleetWorkers.
+func (f *Fleet) SetWorkers(workers int) {
+	if workers < 1 {
+		workers = DefaultFleetWorkers
+	}
+	f.workers = workers
+}
+
+// SetTimeout sets the time allowed for each host to complete an
+// operation, including connecting. Zero means no per-host timeout.
+func (f *Fleet) SetTimeout(timeout time.Duration) {
+	f.timeout = timeout
+}
+
+// node returns the Node for the named connection, opening it if needed.
+func (f *Fleet) node(ctx context.Context, name string) (*Node, error) {
+	f.mu.Lock()
+	node, found := f.nodes[name]
+	f.mu.Unlock()
+	if found {
+		return node, nil
+	}
+	node, err := f.config.connectTo(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.nodes[name] = node
+	f.mu.Unlock()
+	return node, nil
+}
+
+// Run runs fn concurrently against every host of the Fleet.
+//
+// Args:
+//
+//	ctx (context.Context): context for the whole operation
+//	fn (FleetFunc): function to run against each host
+//
+// Returns:
+//
+//	Pointer to FleetResults with one entry per host
+func (f *Fleet) Run(ctx context.Context, fn FleetFunc) *FleetResults {
+	return f.run(ctx, func(ctx context.Context, name string,
+		node *Node) (*JSONRPCResponse, error) {
+		return nil, fn(ctx, name, node)
+	})
+}
+
+// RunCommands sends the commands concurrently to every host of the Fleet.
+// See Node.RunCommands.
+//
+// Args:
+//
+//	ctx (context.Context): context for the whole operation
+//	commands (array): The ordered list of commands to send to each host
+//	encoding (string): The encoding method to use for the request and
+//	                excpected response. ('json' or 'text')
+//
+// Returns:
+//
+//	Pointer to FleetResults with one entry per host
+func (f *Fleet) RunCommands(ctx context.Context, commands []string,
+	encoding string) *FleetResults {
+	return f.run(ctx, func(ctx context.Context, name string,
+		node *Node) (*JSONRPCResponse, error) {
+		return node.RunCommandsContext(ctx, commands, encoding)
+	})
+}
+
+// run dispatches fn to every host through a bounded pool of workers and
+// gathers the results.
+func (f *Fleet) run(ctx context.Context, fn func(ctx context.Context,
+	name string, node *Node) (*JSONRPCResponse, error)) *FleetResults {
+	start := time.Now()
+	results := &FleetResults{Results: make(map[string]*FleetResult)}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	names := make(chan string)
+	workers := f.workers
+	if workers > len(f.names) {
+		workers = len(f.names)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				result := f.runHost(ctx, name, fn)
+				mu.Lock()
+				results.Results[name] = result
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, name := range f.names {
+		names <- name
+	}
+	close(names)
+	wg.Wait()
+
+	for _, result := range results.Results {
+		results.Stats.Total++
+		if result.Err != nil {
+			results.Stats.Failed++
+		} else {
+			results.Stats.Succeeded++
+		}
+	}
+	results.Stats.Duration = time.Since(start)
+	return results
+}
+
+// runHost runs fn against a single host within the per-host timeout.
+func (f *Fleet) runHost(ctx context.Context, name string,
+	fn func(ctx context.Context, name string,
+		node *Node) (*JSONRPCResponse, error)) *FleetResult {
+	start := time.Now()
+	result := &FleetResult{Name: name}
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.timeout)
+		defer cancel()
+	}
+	node, err := f.node(ctx, name)
+	if err == nil {
+		result.Response, result.Err = fn(ctx, name, node)
+	} else {
+		result.Err = fmt.Errorf("%s: %w", name, err)
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// Close closes the connections of every Node opened by the Fleet.
+func (f *Fleet) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var err error
+	for name, node := range f.nodes {
+		if cerr := closeConnection(node.GetConnection()); cerr != nil && err == nil {
+			err = cerr
+		}
+		delete(f.nodes, name)
+	}
+	return err
+}
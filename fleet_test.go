@@ -0,0 +1,193 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/vaughan0/go-ini"
+)
+
+// newFleetTestServer returns a server answering every runCmds request
+// with hostname as the result of each command. If delay is set, the
+// server waits that long (or until the request is cancelled) first.
+func newFleetTestServer(hostname string, delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		var req Request
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := JSONRPCResponse{Jsonrpc: "2.0", ID: req.ID}
+		for range req.Params.Cmds {
+			resp.Result = append(resp.Result,
+				map[string]interface{}{"hostname": hostname})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+// addFleetTestConnection adds a connection profile pointing at srv
+func addFleetTestConnection(config *EapiConfig, name string,
+	srv *httptest.Server, tags string) {
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	section := config.AddConnection(name)
+	section["host"] = addr.IP.String()
+	section["port"] = strconv.Itoa(addr.Port)
+	section["transport"] = "http"
+	section["tags"] = tags
+}
+
+func TestFleetRunCommands_UnitTest(t *testing.T) {
+	config := &EapiConfig{File: make(ini.File)}
+	for _, name := range []string{"leaf1", "leaf2", "spine1"} {
+		srv := newFleetTestServer(name, 0)
+		defer srv.Close()
+		addFleetTestConnection(config, name, srv, "")
+	}
+	slow := newFleetTestServer("slow", 500*time.Millisecond)
+	defer slow.Close()
+	addFleetTestConnection(config, "slow", slow, "")
+
+	fleet := config.NewFleet("leaf1", "leaf2", "spine1", "slow", "bogus")
+	defer fleet.Close()
+	fleet.SetWorkers(2)
+	fleet.SetTimeout(200 * time.Millisecond)
+
+	results := fleet.RunCommands(context.Background(),
+		[]string{"show hostname"}, "json")
+	want := FleetStats{Total: 5, Succeeded: 3, Failed: 2,
+		Duration: results.Stats.Duration}
+	if results.Stats != want {
+		t.Fatalf("Expected stats %#v, got %#v", want, results.Stats)
+	}
+	for _, name := range []string{"leaf1", "leaf2", "spine1"} {
+		result := results.Results[name]
+		if result.Err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, result.Err)
+		}
+		if got := result.Response.Result[0]["hostname"]; got != name {
+			t.Fatalf("%s: expected hostname %s, got %v", name, name, got)
+		}
+	}
+	errs := results.Errors()
+	if len(errs) != 2 || errs["slow"] == nil || errs["bogus"] == nil {
+		t.Fatalf("Unexpected errors: %#v", errs)
+	}
+	if errors.Unwrap(errs["bogus"]) == nil {
+		t.Fatalf("Expected a wrapped connection error, got %v", errs["bogus"])
+	}
+}
+
+func TestFleetRun_UnitTest(t *testing.T) {
+	config := &EapiConfig{File: make(ini.File)}
+	srv := newFleetTestServer("leaf1", 0)
+	defer srv.Close()
+	addFleetTestConnection(config, "leaf1", srv, "")
+
+	fleet := config.NewFleet("leaf1")
+	defer fleet.Close()
+	show := &showHostname{}
+	results := fleet.Run(context.Background(),
+		func(ctx context.Context, name string, node *Node) error {
+			handle, _ := node.GetHandle("json")
+			handle.AddCommand(show)
+			return handle.CallContext(ctx)
+		})
+	if results.Stats.Succeeded != 1 || show.Hostname != "leaf1" {
+		t.Fatalf("Unexpected result: %#v %s", results.Stats, show.Hostname)
+	}
+}
+
+func TestFleetDuplicateNames_UnitTest(t *testing.T) {
+	config := &EapiConfig{File: make(ini.File)}
+	srv := newFleetTestServer("leaf1", 0)
+	defer srv.Close()
+	addFleetTestConnection(config, "leaf1", srv, "")
+
+	fleet := config.NewFleet("leaf1", "leaf1")
+	defer fleet.Close()
+	if got := fleet.Names(); !reflect.DeepEqual(got, []string{"leaf1"}) {
+		t.Fatalf("Expected a single leaf1, got %v", got)
+	}
+	results := fleet.RunCommands(context.Background(),
+		[]string{"show hostname"}, "json")
+	if results.Stats.Total != 1 || results.Stats.Succeeded != 1 {
+		t.Fatalf("Expected a single run, got %#v", results.Stats)
+	}
+}
+
+type showHostname struct {
+	Hostname string `json:"hostname"`
+}
+
+func (s *showHostname) GetCmd() string {
+	return "show hostname"
+}
+
+func TestFleetByTag_UnitTest(t *testing.T) {
+	config := &EapiConfig{File: make(ini.File)}
+	srv := newFleetTestServer("dut", 0)
+	defer srv.Close()
+	addFleetTestConnection(config, "leaf1", srv, "leaf, pod1")
+	addFleetTestConnection(config, "leaf2", srv, "leaf,pod2")
+	addFleetTestConnection(config, "spine1", srv, "spine, pod1")
+
+	tests := [...]struct {
+		tags []string
+		want []string
+	}{
+		{[]string{"leaf"}, []string{"leaf1", "leaf2"}},
+		{[]string{"pod1"}, []string{"leaf1", "spine1"}},
+		{[]string{"spine", "pod2"}, []string{"leaf2", "spine1"}},
+		{[]string{"bogus"}, nil},
+	}
+	for idx, tt := range tests {
+		if got := config.NewFleetByTag(tt.tags...).Names(); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("Test[%d] expected %#v, got %#v", idx, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,435 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapitest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	aclRegex        = regexp.MustCompile(`^ip access-list (?:standard )?\S+$`)
+	persistentRegex = regexp.MustCompile(`^interface (?:Ethernet|Management)|^mlag configuration$`)
+)
+
+// sectionKind describes a command entering a configuration mode. Sections
+// without a parent are opened from the root of the config, which EOS
+// returns to from any mode when such a command is given.
+type sectionKind struct {
+	re     *regexp.Regexp
+	parent *regexp.Regexp
+}
+
+var sectionKinds = []sectionKind{
+	{re: regexp.MustCompile(`^vlan \S+$`)},
+	{re: regexp.MustCompile(`^interface \S+$`)},
+	{re: regexp.MustCompile(`^router \S+(?: \S+)?$`)},
+	{re: regexp.MustCompile(`^mlag configuration$`)},
+	{re: aclRegex},
+	{re: regexp.MustCompile(`^ip access-list \S+ \S+$`)},
+	{re: regexp.MustCompile(`^management \S+(?: \S+)?$`)},
+	{re: regexp.MustCompile(`^spanning-tree mst configuration$`)},
+	{re: regexp.MustCompile(`^vrf \S+$`),
+		parent: regexp.MustCompile(`^router bgp `)},
+	{re: regexp.MustCompile(`^address-family \S+(?: \S+)?$`),
+		parent: regexp.MustCompile(`^router bgp |^vrf `)},
+}
+
+// rootSettings lists the settings EOS returns to the root of the config
+// for when given from another mode.
+var rootSettings = []string{
+	"hostname", "username", "ip routing", "ip domain-name",
+	"spanning-tree mode", "ptp mode", "ptp source", "ptp ttl",
+	"ptp priority1", "ptp priority2", "ptp domain",
+}
+
+// Default settings of the sections shown by show running-config all
+var (
+	rootDefaults = []string{
+		"hostname localhost",
+		"no ip routing",
+		"spanning-tree mode mstp",
+	}
+	interfaceDefaults = []string{
+		"no description",
+		"no shutdown",
+	}
+	routedDefaults = []string{
+		"no ip address",
+		"mtu 1500",
+	}
+	switchportDefaults = []string{
+		"switchport access vlan 1",
+		"switchport trunk native vlan 1",
+		"switchport trunk allowed vlan 1-4094",
+		"switchport mode access",
+		"switchport",
+	}
+	spanningTreeDefaults = []string{
+		"no spanning-tree portfast",
+		"spanning-tree portfast auto",
+		"no spanning-tree bpduguard",
+	}
+	ethernetDefaults = concat(interfaceDefaults, []string{
+		"no flowcontrol send",
+		"no flowcontrol receive",
+	}, switchportDefaults, []string{
+		"no channel-group",
+		"no ptp enable",
+		"sflow enable",
+	}, spanningTreeDefaults)
+	portChannelDefaults = concat(interfaceDefaults, switchportDefaults,
+		[]string{
+			"no port-channel min-links",
+			"no port-channel lacp fallback",
+			"port-channel lacp fallback timeout 90",
+			"no mlag",
+		}, spanningTreeDefaults)
+	vxlanDefaults = concat(interfaceDefaults, []string{
+		"no vxlan source-interface",
+		"no vxlan multicast-group",
+		"vxlan udp-port 4789",
+	})
+	bgpDefaults = []string{
+		"no shutdown",
+		"no router-id",
+		"maximum-paths 1 ecmp 128",
+	}
+	mlagDefaults = []string{
+		"no domain-id",
+		"no local-interface",
+		"no peer-address",
+		"no peer-link",
+		"no shutdown",
+	}
+)
+
+// concat returns the concatenation of lists
+func concat(lists ...[]string) []string {
+	var result []string
+	for _, l := range lists {
+		result = append(result, l...)
+	}
+	return result
+}
+
+// sectionTemplate returns the default settings of the section opened by
+// line. The root of the config is the section with an empty line.
+func sectionTemplate(line string) []string {
+	words := strings.Fields(line)
+	switch {
+	case line == "":
+		return rootDefaults
+	case len(words) == 2 && words[0] == "vlan":
+		vid, _ := strconv.Atoi(words[1])
+		return []string{fmt.Sprintf("name VLAN%04d", vid), "state active"}
+	case line == "mlag configuration":
+		return mlagDefaults
+	case len(words) == 3 && words[0] == "router" && words[1] == "bgp":
+		return bgpDefaults
+	case len(words) != 2 || words[0] != "interface":
+		return nil
+	case strings.HasPrefix(words[1], "Ethernet"):
+		return ethernetDefaults
+	case strings.HasPrefix(words[1], "Port-Channel"):
+		return portChannelDefaults
+	case strings.HasPrefix(words[1], "Vxlan"):
+		return vxlanDefaults
+	case strings.HasPrefix(words[1], "Loopback"):
+		return concat(interfaceDefaults, routedDefaults[:1])
+	}
+	return concat(interfaceDefaults, routedDefaults)
+}
+
+// opensSection returns true if line enters a configuration mode from
+// the given section.
+func opensSection(line string, parent *configNode) bool {
+	for _, kind := range sectionKinds {
+		if !kind.re.MatchString(line) {
+			continue
+		}
+		if kind.parent == nil && parent.line == "" {
+			return true
+		}
+		if kind.parent != nil && kind.parent.MatchString(parent.line) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRootSetting returns true if words is a setting of the root of the
+// config.
+func isRootSetting(words []string) bool {
+	line := strings.Join(words, " ")
+	for _, setting := range rootSettings {
+		if hasWordPrefix(line, setting) {
+			return true
+		}
+	}
+	return false
+}
+
+var interfaceTypes = []string{
+	"Ethernet", "Port-Channel", "Loopback", "Management", "Vlan", "Vxlan",
+	"Tunnel",
+}
+
+// interfaceName expands an abbreviated interface name such as Et1 or
+// po10 to its full name.
+func interfaceName(name string) string {
+	idx := strings.IndexAny(name, "0123456789")
+	if idx <= 0 {
+		return name
+	}
+	prefix := strings.ToLower(name[:idx])
+	for _, t := range interfaceTypes {
+		if strings.HasPrefix(strings.ToLower(t), prefix) {
+			return t + name[idx:]
+		}
+	}
+	return name
+}
+
+// sectionLines returns the sections entered by line. A vlan command can
+// enter several vlans at once.
+func sectionLines(line string) ([]string, error) {
+	words := strings.Fields(line)
+	switch words[0] {
+	case "vlan":
+		vlans, err := parseVlans(words[1])
+		if err != nil || len(vlans) == 0 {
+			return nil, fmt.Errorf("Invalid input (at token 1: '%s')", words[1])
+		}
+		var lines []string
+		for _, vid := range sortedVlans(vlans) {
+			lines = append(lines, fmt.Sprintf("vlan %d", vid))
+		}
+		return lines, nil
+	case "interface":
+		return []string{"interface " + interfaceName(words[1])}, nil
+	}
+	return []string{line}, nil
+}
+
+// cliState holds the CLI mode of a runCmds request. Like eAPI, each
+// request starts in exec mode.
+type cliState struct {
+	session string
+	stack   [][]*configNode
+}
+
+// exec runs cmd against the simulated configuration
+func (s *Server) exec(cli *cliState, cmd, format string) (map[string]interface{}, []string) {
+	words := strings.Fields(cmd)
+	if len(words) == 0 {
+		return emptyResult(format), nil
+	}
+	if words[0] == "show" && len(words) > 1 {
+		return s.show(cli, words, format)
+	}
+
+	var err error
+	switch {
+	case words[0] == "configure":
+		err = s.configure(cli, words)
+	case cmd == "copy running-config startup-config" || cmd == "write" ||
+		cmd == "write memory":
+		s.startup = s.running.clone()
+	case cli.stack != nil:
+		err = s.configCommand(cli, cmd)
+	}
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	return emptyResult(format), nil
+}
+
+// configure enters configuration mode, either directly or through a
+// config session, or commits or aborts a config session.
+func (s *Server) configure(cli *cliState, words []string) error {
+	if len(words) == 1 || (len(words) == 2 && words[1] == "terminal") {
+		cli.session = ""
+		cli.stack = [][]*configNode{{s.running}}
+		return nil
+	}
+	if words[1] != "session" || len(words) > 4 {
+		return fmt.Errorf("Invalid input (at token 1: '%s')", words[1])
+	}
+	if len(words) == 2 {
+		return fmt.Errorf("Incomplete command")
+	}
+	name := words[2]
+	if len(words) == 4 {
+		if _, found := s.sessions[name]; !found {
+			return fmt.Errorf("Session %s does not exist", name)
+		}
+		switch words[3] {
+		case "commit":
+			s.running = s.sessions[name]
+		case "abort":
+		default:
+			return fmt.Errorf("Invalid input (at token 3: '%s')", words[3])
+		}
+		delete(s.sessions, name)
+		return nil
+	}
+	if _, found := s.sessions[name]; !found {
+		s.sessions[name] = s.running.clone()
+	}
+	cli.session = name
+	cli.stack = [][]*configNode{{s.sessions[name]}}
+	return nil
+}
+
+// configCommand runs a command in configuration mode
+func (s *Server) configCommand(cli *cliState, cmd string) error {
+	neg, words := splitCommand(cmd)
+	switch words[0] {
+	case "end":
+		cli.stack = nil
+		return nil
+	case "exit":
+		if cli.stack = cli.stack[:len(cli.stack)-1]; len(cli.stack) == 0 {
+			cli.stack = nil
+		}
+		return nil
+	case "commit", "abort":
+		if cli.session == "" {
+			return fmt.Errorf("Not in a configuration session")
+		}
+		if words[0] == "commit" && len(words) > 1 {
+			// A commit timer keeps the session until it is confirmed
+			s.running = s.sessions[cli.session].clone()
+		} else {
+			if words[0] == "commit" {
+				s.running = s.sessions[cli.session]
+			}
+			delete(s.sessions, cli.session)
+		}
+		cli.stack, cli.session = nil, ""
+		return nil
+	}
+
+	body := strings.Join(words, " ")
+	root := cli.stack[0][0]
+	contexts := cli.stack[len(cli.stack)-1]
+	opens := opensSection(body, contexts[0])
+	if !opens && len(cli.stack) > 1 &&
+		(opensSection(body, root) || isRootSetting(words)) {
+		cli.stack = cli.stack[:1]
+		contexts = cli.stack[0]
+		opens = opensSection(body, root)
+	}
+	if opens {
+		return enterSection(cli, contexts, neg, body)
+	}
+
+	for _, n := range contexts {
+		var err error
+		if n == root && words[0] == "username" {
+			err = n.applyUsername(neg, words)
+		} else {
+			err = n.apply(cmd)
+		}
+		if err != nil {
+			return err
+		}
+		// Adding an interface to a channel-group creates the port-channel
+		if neg == "" && words[0] == "channel-group" && len(words) > 1 {
+			line := "interface Port-Channel" + words[1]
+			if root.child(line) == nil {
+				root.addSection(newSection(line))
+			}
+		}
+	}
+	return nil
+}
+
+// enterSection enters the sections opened by line within contexts. The
+// no and default forms remove the sections instead, or restore the
+// defaults of sections that can't be removed, such as physical
+// interfaces.
+func enterSection(cli *cliState, contexts []*configNode, neg,
+	line string) error {
+	lines, err := sectionLines(line)
+	if err != nil {
+		return err
+	}
+	if neg != "" {
+		for _, parent := range contexts {
+			for _, l := range lines {
+				if c := parent.child(l); c != nil && persistentRegex.MatchString(l) {
+					c.reset()
+				} else if c != nil {
+					parent.removeChild(l)
+				}
+			}
+		}
+		return nil
+	}
+
+	var sections []*configNode
+	for _, parent := range contexts {
+		for _, l := range lines {
+			c := parent.child(l)
+			if c == nil {
+				if err := checkSection(parent, l); err != nil {
+					return err
+				}
+				c = newSection(l)
+				parent.addSection(c)
+			}
+			sections = append(sections, c)
+		}
+	}
+	cli.stack = append(cli.stack, sections)
+	return nil
+}
+
+// checkSection returns an error if the section opened by line can't be
+// added to parent.
+func checkSection(parent *configNode, line string) error {
+	if !strings.HasPrefix(line, "router bgp ") {
+		return nil
+	}
+	for _, c := range parent.children {
+		if c.section && strings.HasPrefix(c.line, "router bgp ") {
+			return fmt.Errorf("BGP is already running with AS number %s",
+				strings.TrimPrefix(c.line, "router bgp "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,740 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapitest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var chunkRegex = regexp.MustCompile(`\d+|\D+`)
+
+// listKind identifies settings whose value is a list that can be updated
+// with the add and remove keywords.
+type listKind int
+
+const (
+	listNone listKind = iota
+	listVlans
+	listWords
+)
+
+// linePattern describes the keywords identifying a setting within a
+// config section. Words equal to "*" are part of the key and match any
+// word, words equal to "_" match any word but are not part of the key.
+// Configuring a setting replaces the lines of its section sharing the
+// same key.
+type linePattern struct {
+	words []string
+	flag  bool
+	list  listKind
+}
+
+func valued(words string) linePattern {
+	return linePattern{words: strings.Fields(words)}
+}
+
+func flag(words string) linePattern {
+	return linePattern{words: strings.Fields(words), flag: true}
+}
+
+func list(words string, kind listKind) linePattern {
+	return linePattern{words: strings.Fields(words), list: kind}
+}
+
+// linePatterns lists the settings known to the config model. Commands not
+// matching any pattern are keyed by their full text, so they can be added
+// once and removed with their no form.
+var linePatterns = []linePattern{
+	// system
+	valued("hostname"),
+	valued("ip domain-name"),
+	valued("spanning-tree mode"),
+	valued("ptp mode"),
+	valued("ptp source ip"),
+	valued("ptp ttl"),
+	valued("ptp priority1"),
+	valued("ptp priority2"),
+	valued("ptp domain"),
+
+	// vlans
+	valued("name"),
+	valued("state"),
+	valued("trunk group *"),
+
+	// interfaces
+	valued("description"),
+	valued("mtu"),
+	valued("speed"),
+	valued("load-interval"),
+	valued("ip address"),
+	valued("flowcontrol send"),
+	valued("flowcontrol receive"),
+	valued("channel-group"),
+	valued("lacp rate"),
+	valued("lacp port-priority"),
+	valued("port-channel min-links"),
+	valued("port-channel lacp fallback"),
+	valued("port-channel lacp fallback timeout"),
+	valued("mlag"),
+	flag("switchport"),
+	valued("switchport mode"),
+	valued("switchport access vlan"),
+	valued("switchport trunk native vlan"),
+	list("switchport trunk allowed vlan", listVlans),
+	valued("switchport trunk group *"),
+	valued("spanning-tree portfast"),
+	flag("spanning-tree portfast auto"),
+	valued("spanning-tree bpduguard"),
+	valued("spanning-tree cost"),
+	valued("spanning-tree port-priority"),
+	valued("ptp transport"),
+	valued("ptp role"),
+	valued("vxlan source-interface"),
+	valued("vxlan multicast-group"),
+	valued("vxlan udp-port"),
+	list("vxlan flood vtep", listWords),
+	valued("vxlan vlan * vni"),
+	list("vxlan vlan * flood vtep", listWords),
+
+	// mlag configuration
+	valued("domain-id"),
+	valued("local-interface"),
+	valued("peer-address"),
+	valued("peer-link"),
+	valued("reload-delay"),
+
+	// router bgp
+	valued("router-id"),
+	valued("maximum-paths"),
+	valued("network *"),
+	valued("neighbor * remote-as"),
+	valued("neighbor * peer-group"),
+	valued("neighbor * description"),
+	valued("neighbor * maximum-routes"),
+	valued("neighbor * update-source"),
+	valued("neighbor * route-map _ in"),
+	valued("neighbor * route-map _ out"),
+	flag("neighbor * shutdown"),
+	flag("neighbor * send-community"),
+	flag("neighbor * next-hop-self"),
+}
+
+// match returns the key of words if they match the pattern
+func (p *linePattern) match(words []string) (string, bool) {
+	if len(words) < len(p.words) || (p.flag && len(words) != len(p.words)) {
+		return "", false
+	}
+	key := make([]string, 0, len(p.words))
+	for idx, word := range p.words {
+		switch word {
+		case "*":
+			key = append(key, words[idx])
+		case "_":
+		default:
+			if words[idx] != word {
+				return "", false
+			}
+			key = append(key, word)
+		}
+	}
+	return strings.Join(key, " "), true
+}
+
+// splitCommand splits a command into its no or default keyword, if any,
+// and the words of the setting it refers to.
+func splitCommand(cmd string) (string, []string) {
+	words := strings.Fields(cmd)
+	if len(words) > 1 && (words[0] == "no" || words[0] == "default") {
+		return words[0], words[1:]
+	}
+	return "", words
+}
+
+// lineKey returns the key of the setting words refers to and the longest
+// pattern it matches, if any.
+func lineKey(words []string) (string, *linePattern) {
+	var best *linePattern
+	var bestKey string
+	for idx := range linePatterns {
+		p := &linePatterns[idx]
+		if best != nil && len(p.words) <= len(best.words) {
+			continue
+		}
+		if key, ok := p.match(words); ok {
+			best, bestKey = p, key
+		}
+	}
+	if best == nil {
+		return strings.Join(words, " "), nil
+	}
+	return bestKey, best
+}
+
+// settingKey returns the key of a config line
+func settingKey(line string) string {
+	_, words := splitCommand(line)
+	key, _ := lineKey(words)
+	return key
+}
+
+// hasWordPrefix returns true if key starts with the words of prefix
+func hasWordPrefix(key, prefix string) bool {
+	return key == prefix || strings.HasPrefix(key, prefix+" ")
+}
+
+// configNode is a line of a running-config together with the lines of
+// the section it opens, if any.
+type configNode struct {
+	line     string
+	children []*configNode
+	section  bool
+	entries  bool
+	template []string
+}
+
+// newSection returns the section opened by line holding its default
+// settings. The root of the config is the section with an empty line.
+func newSection(line string) *configNode {
+	n := &configNode{
+		line:     line,
+		section:  true,
+		entries:  aclRegex.MatchString(line),
+		template: sectionTemplate(line),
+	}
+	n.reset()
+	return n
+}
+
+// reset restores the default settings of a section
+func (n *configNode) reset() {
+	n.children = nil
+	for _, line := range n.template {
+		n.children = append(n.children, &configNode{line: line})
+	}
+}
+
+// clone returns a deep copy of the node
+func (n *configNode) clone() *configNode {
+	c := *n
+	c.children = make([]*configNode, len(n.children))
+	for idx, child := range n.children {
+		c.children[idx] = child.clone()
+	}
+	return &c
+}
+
+// child returns the child with the given line or nil if none exists
+func (n *configNode) child(line string) *configNode {
+	for _, c := range n.children {
+		if c.line == line {
+			return c
+		}
+	}
+	return nil
+}
+
+// removeChild removes the child with the given line
+func (n *configNode) removeChild(line string) {
+	kept := n.children[:0]
+	for _, c := range n.children {
+		if c.line != line {
+			kept = append(kept, c)
+		}
+	}
+	n.children = kept
+}
+
+// addSection adds a child section, keeping sections opened by the same
+// keyword together and in natural order.
+func (n *configNode) addSection(section *configNode) {
+	keyword := strings.Fields(section.line)[0]
+	pos := len(n.children)
+	for idx, c := range n.children {
+		if !c.section || strings.Fields(c.line)[0] != keyword {
+			continue
+		}
+		if naturalLess(section.line, c.line) {
+			pos = idx
+			break
+		}
+		pos = idx + 1
+	}
+	n.insert(pos, section)
+}
+
+// insert inserts nodes at pos, or at the end if pos is negative
+func (n *configNode) insert(pos int, nodes ...*configNode) {
+	if pos < 0 || pos > len(n.children) {
+		pos = len(n.children)
+	}
+	children := append([]*configNode{}, n.children[:pos]...)
+	children = append(children, nodes...)
+	n.children = append(children, n.children[pos:]...)
+}
+
+// insertLine inserts a setting at pos. If pos is negative the setting is
+// added after the other settings, ahead of any nested section.
+func (n *configNode) insertLine(pos int, line string) {
+	if pos < 0 {
+		pos = n.settingsEnd()
+	}
+	n.insert(pos, &configNode{line: line})
+}
+
+// settingsEnd returns the position of the first nested section
+func (n *configNode) settingsEnd() int {
+	for idx, c := range n.children {
+		if c.section {
+			return idx
+		}
+	}
+	return len(n.children)
+}
+
+// removeLines removes the settings whose key satisfies match and returns
+// the position of the first one removed, or -1 if none was found.
+func (n *configNode) removeLines(match func(string) bool) int {
+	pos := -1
+	kept := n.children[:0]
+	for _, c := range n.children {
+		if !c.section && match(settingKey(c.line)) {
+			if pos < 0 {
+				pos = len(kept)
+			}
+			continue
+		}
+		kept = append(kept, c)
+	}
+	n.children = kept
+	return pos
+}
+
+// restore inserts at pos the default settings whose key satisfies match
+func (n *configNode) restore(pos int, match func(string) bool) {
+	for _, line := range n.template {
+		if match(settingKey(line)) {
+			n.insertLine(pos, line)
+			if pos >= 0 {
+				pos++
+			}
+		}
+	}
+}
+
+// value returns the words following the key of the setting with the
+// given key, or nil if the setting is not configured.
+func (n *configNode) value(key string, pat *linePattern) []string {
+	for _, c := range n.children {
+		neg, words := splitCommand(c.line)
+		if c.section || neg != "" || settingKey(c.line) != key {
+			continue
+		}
+		return words[len(pat.words):]
+	}
+	return nil
+}
+
+// hasKeys reports whether the section holds a setting with key and
+// whether it holds settings whose key extends key.
+func (n *configNode) hasKeys(key string) (bool, bool) {
+	var found, extended bool
+	for _, c := range n.children {
+		if c.section {
+			continue
+		}
+		k := settingKey(c.line)
+		found = found || k == key
+		extended = extended || (k != key && hasWordPrefix(k, key))
+	}
+	return found, extended
+}
+
+// isDefault returns true if line is a default setting of the section
+func (n *configNode) isDefault(line string) bool {
+	for _, l := range n.template {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+// apply applies a configuration command to the settings of the section
+func (n *configNode) apply(cmd string) error {
+	neg, words := splitCommand(cmd)
+	if len(words) == 0 {
+		return nil
+	}
+	if n.entries && isEntry(words) {
+		n.applyEntry(neg, words)
+		return nil
+	}
+	body := strings.Join(words, " ")
+	key, pat := lineKey(words)
+	byKey := func(k string) bool { return k == key }
+
+	switch found, extended := n.hasKeys(key); {
+	case neg == "" && pat != nil && pat.list != listNone:
+		return n.applyList(key, pat, words[len(pat.words):])
+	case neg == "":
+		n.insertLine(n.removeLines(byKey), body)
+	case pat != nil && !pat.flag:
+		n.restore(n.removeLines(byKey), byKey)
+	case neg == "no" && (pat != nil || found || !extended):
+		n.insertLine(n.removeLines(byKey), "no "+body)
+	default:
+		match := func(k string) bool { return hasWordPrefix(k, key) }
+		n.restore(n.removeLines(match), match)
+	}
+	return nil
+}
+
+// applyList applies the value of a list setting. The add and remove
+// keywords update the current list instead of replacing it.
+func (n *configNode) applyList(key string, pat *linePattern,
+	value []string) error {
+	op := ""
+	if len(value) > 0 && (value[0] == "add" || value[0] == "remove") {
+		op, value = value[0], value[1:]
+	}
+	current := n.value(key, pat)
+	byKey := func(k string) bool { return k == key }
+
+	if pat.list == listVlans {
+		vlans, err := parseVlans(strings.Join(value, ","))
+		if err != nil {
+			return err
+		}
+		if op != "" {
+			set, _ := parseVlans(strings.Join(current, ","))
+			for vid := range vlans {
+				if op == "add" {
+					set[vid] = true
+				} else {
+					delete(set, vid)
+				}
+			}
+			vlans = set
+		}
+		n.insertLine(n.removeLines(byKey), key+" "+formatVlans(vlans))
+		return nil
+	}
+
+	if op != "" {
+		items := append([]string{}, current...)
+		for _, item := range value {
+			idx := indexOf(items, item)
+			if op == "add" && idx < 0 {
+				items = append(items, item)
+			} else if op == "remove" && idx >= 0 {
+				items = append(items[:idx], items[idx+1:]...)
+			}
+		}
+		value = items
+	}
+	pos := n.removeLines(byKey)
+	if len(value) == 0 {
+		n.restore(pos, byKey)
+		return nil
+	}
+	n.insertLine(pos, key+" "+strings.Join(value, " "))
+	return nil
+}
+
+// isEntry returns true if words is a sequenced entry of an access list
+func isEntry(words []string) bool {
+	if _, err := strconv.Atoi(words[0]); err == nil {
+		return true
+	}
+	return words[0] == "permit" || words[0] == "deny" || words[0] == "remark"
+}
+
+// applyEntry applies a command to a section of sequenced entries. Entries
+// given without a sequence number are numbered after the last one, in
+// steps of 10.
+func (n *configNode) applyEntry(neg string, words []string) {
+	seq, err := strconv.Atoi(words[0])
+	if neg != "" {
+		body := strings.Join(words, " ")
+		kept := n.children[:0]
+		for _, c := range n.children {
+			fields := strings.Fields(c.line)
+			s, _ := strconv.Atoi(fields[0])
+			if (err == nil && s == seq) ||
+				(err != nil && strings.Join(fields[1:], " ") == body) {
+				continue
+			}
+			kept = append(kept, c)
+		}
+		n.children = kept
+		return
+	}
+	if err != nil {
+		last := 0
+		for _, c := range n.children {
+			if s, err := strconv.Atoi(strings.Fields(c.line)[0]); err == nil &&
+				s > last {
+				last = s
+			}
+		}
+		seq = (last/10 + 1) * 10
+		words = append([]string{strconv.Itoa(seq)}, words...)
+	}
+	pos := len(n.children)
+	kept := n.children[:0]
+	for _, c := range n.children {
+		s, err := strconv.Atoi(strings.Fields(c.line)[0])
+		if err == nil && s == seq {
+			continue
+		}
+		if err == nil && s > seq && pos > len(kept) {
+			pos = len(kept)
+		}
+		kept = append(kept, c)
+	}
+	n.children = kept
+	n.insertLine(pos, strings.Join(words, " "))
+}
+
+// user holds the attributes of a local user
+type user struct {
+	name       string
+	privilege  string
+	role       string
+	secret     string
+	nopassword bool
+}
+
+// parseUser parses the username line of the running-config
+func parseUser(line string) user {
+	words := strings.Fields(line)
+	u := user{name: words[1], privilege: "1"}
+	u.update(words[2:])
+	return u
+}
+
+// update updates the user with the attributes given in words
+func (u *user) update(words []string) {
+	for idx := 0; idx < len(words); idx++ {
+		switch words[idx] {
+		case "nopassword":
+			u.nopassword, u.secret = true, ""
+		case "secret":
+			u.nopassword = false
+			u.secret = strings.Join(words[idx+1:], " ")
+			return
+		case "privilege", "role":
+			if idx+1 == len(words) {
+				return
+			}
+			if words[idx] == "role" {
+				u.role = words[idx+1]
+			} else {
+				u.privilege = words[idx+1]
+			}
+			idx++
+		}
+	}
+}
+
+// String returns the username line of the running-config
+func (u user) String() string {
+	line := "username " + u.name + " privilege " + u.privilege
+	if u.role != "" {
+		line += " role " + u.role
+	}
+	if u.nopassword {
+		return line + " nopassword"
+	}
+	return line + " secret " + u.secret
+}
+
+// applyUsername applies a username command to the root of the config.
+// The attributes of a user are merged into a single line, followed by
+// the ssh-key of the user if one is configured.
+func (n *configNode) applyUsername(neg string, words []string) error {
+	if len(words) < 2 {
+		return fmt.Errorf("Incomplete command")
+	}
+	name := words[1]
+	userPos, keyPos := -1, -1
+	for idx, c := range n.children {
+		fields := strings.Fields(c.line)
+		if c.section || len(fields) < 3 || fields[0] != "username" ||
+			fields[1] != name {
+			continue
+		}
+		if fields[2] == "ssh-key" || fields[2] == "sshkey" {
+			keyPos = idx
+		} else {
+			userPos = idx
+		}
+	}
+	removeKey := func() {
+		if keyPos >= 0 {
+			n.children = append(n.children[:keyPos], n.children[keyPos+1:]...)
+		}
+	}
+
+	u := user{name: name, privilege: "1", nopassword: true}
+	if userPos >= 0 {
+		u = parseUser(n.children[userPos].line)
+	}
+	switch {
+	case neg != "" && len(words) == 2:
+		removeKey()
+		if userPos >= 0 {
+			n.children = append(n.children[:userPos], n.children[userPos+1:]...)
+		}
+		return nil
+	case len(words) > 2 && (words[2] == "ssh-key" || words[2] == "sshkey"):
+		removeKey()
+		if neg != "" {
+			return nil
+		}
+		if userPos < 0 {
+			userPos = n.settingsEnd()
+			n.insertLine(userPos, u.String())
+		} else if keyPos >= 0 && keyPos < userPos {
+			userPos--
+		}
+		n.insertLine(userPos+1, strings.Join(words, " "))
+		return nil
+	case neg != "":
+		switch words[2] {
+		case "role":
+			u.role = ""
+		case "privilege":
+			u.privilege = "1"
+		}
+	default:
+		u.update(words[2:])
+	}
+
+	if userPos < 0 {
+		n.insertLine(-1, u.String())
+	} else {
+		n.children[userPos].line = u.String()
+	}
+	return nil
+}
+
+// parseVlans parses a list of vlan ranges such as 1,10-20 into a set
+func parseVlans(value string) (map[int]bool, error) {
+	vlans := make(map[int]bool)
+	switch value {
+	case "", "none":
+		return vlans, nil
+	case "all":
+		value = "1-4094"
+	}
+	for _, item := range strings.Split(value, ",") {
+		bounds := strings.SplitN(item, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		last := first
+		if err == nil && len(bounds) == 2 {
+			last, err = strconv.Atoi(bounds[1])
+		}
+		if err != nil || first < 1 || last > 4094 || first > last {
+			return nil, fmt.Errorf("Invalid input (at token 0: '%s')", item)
+		}
+		for vid := first; vid <= last; vid++ {
+			vlans[vid] = true
+		}
+	}
+	return vlans, nil
+}
+
+// formatVlans formats a set of vlans as a list of ranges
+func formatVlans(vlans map[int]bool) string {
+	if len(vlans) == 0 {
+		return "none"
+	}
+	ids := sortedVlans(vlans)
+	var ranges []string
+	for start := 0; start < len(ids); {
+		end := start
+		for end+1 < len(ids) && ids[end+1] == ids[end]+1 {
+			end++
+		}
+		if end == start {
+			ranges = append(ranges, strconv.Itoa(ids[start]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", ids[start], ids[end]))
+		}
+		start = end + 1
+	}
+	return strings.Join(ranges, ",")
+}
+
+// sortedVlans returns the ids of a set of vlans in ascending order
+func sortedVlans(vlans map[int]bool) []int {
+	ids := make([]int, 0, len(vlans))
+	for vid := range vlans {
+		ids = append(ids, vid)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// indexOf returns the index of item in items or -1 if not found
+func indexOf(items []string, item string) int {
+	for idx, i := range items {
+		if i == item {
+			return idx
+		}
+	}
+	return -1
+}
+
+// naturalLess compares two lines, ordering embedded numbers by value so
+// that Ethernet2 sorts before Ethernet10.
+func naturalLess(a, b string) bool {
+	ca, cb := chunkRegex.FindAllString(a, -1), chunkRegex.FindAllString(b, -1)
+	for idx := 0; idx < len(ca) && idx < len(cb); idx++ {
+		if ca[idx] == cb[idx] {
+			continue
+		}
+		na, errA := strconv.Atoi(ca[idx])
+		nb, errB := strconv.Atoi(cb[idx])
+		if errA == nil && errB == nil {
+			return na < nb
+		}
+		return ca[idx] < cb[idx]
+	}
+	return len(ca) < len(cb)
+}
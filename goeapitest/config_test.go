@@ -0,0 +1,232 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapitest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func lines(n *configNode) []string {
+	var result []string
+	for _, c := range n.children {
+		result = append(result, c.line)
+	}
+	return result
+}
+
+func hasLine(n *configNode, line string) bool {
+	return n.child(line) != nil
+}
+
+func TestConfigApply_UnitTest(t *testing.T) {
+	tests := []struct {
+		cmds    []string
+		present []string
+		absent  []string
+	}{
+		{[]string{"description uplink to core"},
+			[]string{"description uplink to core"}, []string{"no description"}},
+		{[]string{"description foo", "no description"},
+			[]string{"no description"}, []string{"description foo"}},
+		{[]string{"shutdown"}, []string{"shutdown"}, []string{"no shutdown"}},
+		{[]string{"shutdown", "default shutdown"},
+			[]string{"no shutdown"}, []string{"shutdown"}},
+		{[]string{"no switchport"},
+			[]string{"no switchport", "switchport mode access"}, []string{"switchport"}},
+		{[]string{"switchport trunk group a", "switchport trunk group b",
+			"no switchport trunk group a"},
+			[]string{"switchport trunk group b"}, []string{"switchport trunk group a"}},
+		{[]string{"switchport trunk group a", "default switchport trunk group"},
+			nil, []string{"switchport trunk group a"}},
+		{[]string{"switchport trunk allowed vlan 10-20",
+			"switchport trunk allowed vlan add 5,21",
+			"switchport trunk allowed vlan remove 15"},
+			[]string{"switchport trunk allowed vlan 5,10-14,16-21"}, nil},
+		{[]string{"switchport trunk allowed vlan none",
+			"default switchport trunk allowed vlan"},
+			[]string{"switchport trunk allowed vlan 1-4094"}, nil},
+		{[]string{"spanning-tree portfast network", "no spanning-tree portfast"},
+			[]string{"no spanning-tree portfast", "spanning-tree portfast auto"},
+			[]string{"spanning-tree portfast network"}},
+		{[]string{"ptp enable", "no ptp enable", "sflow enable"},
+			[]string{"no ptp enable", "sflow enable"}, []string{"ptp enable"}},
+		{[]string{"speed forced 10000full", "speed forced 1000full", "no speed"},
+			nil, []string{"speed forced 10000full", "speed forced 1000full"}},
+	}
+
+	for idx, tt := range tests {
+		section := newSection("interface Ethernet1")
+		for _, cmd := range tt.cmds {
+			if err := section.apply(cmd); err != nil {
+				t.Fatalf("%d: apply %q: %s", idx, cmd, err)
+			}
+		}
+		for _, line := range tt.present {
+			if !hasLine(section, line) {
+				t.Errorf("%d: %q not found in %q", idx, line, lines(section))
+			}
+		}
+		for _, line := range tt.absent {
+			if hasLine(section, line) {
+				t.Errorf("%d: %q found in %q", idx, line, lines(section))
+			}
+		}
+	}
+}
+
+func TestConfigApplyKeyed_UnitTest(t *testing.T) {
+	section := newSection("router bgp 65000")
+	for _, cmd := range []string{
+		"router-id 1.1.1.1",
+		"router-id 2.2.2.2",
+		"neighbor 10.0.0.1 remote-as 65001",
+		"neighbor 10.0.0.1 route-map RM-IN in",
+		"neighbor 10.0.0.1 route-map RM-OUT out",
+		"neighbor 10.0.0.1 route-map RM-IN2 in",
+		"neighbor 10.0.0.2 remote-as 65002",
+		"no neighbor 10.0.0.1",
+	} {
+		section.apply(cmd)
+	}
+	want := []string{
+		"no shutdown",
+		"router-id 2.2.2.2",
+		"maximum-paths 1 ecmp 128",
+		"neighbor 10.0.0.2 remote-as 65002",
+	}
+	if got := lines(section); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestConfigEntries_UnitTest(t *testing.T) {
+	section := newSection("ip access-list standard test")
+	for _, cmd := range []string{
+		"permit host 1.1.1.1",
+		"deny any",
+		"15 permit 10.0.0.0/8",
+		"20 deny any log",
+		"no 10",
+		"remark end",
+	} {
+		section.apply(cmd)
+	}
+	want := []string{"15 permit 10.0.0.0/8", "20 deny any log", "30 remark end"}
+	if got := lines(section); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestConfigUsername_UnitTest(t *testing.T) {
+	root := newSection("")
+	for _, cmd := range []string{
+		"username bob secret 0 pass",
+		"username bob privilege 3",
+		"username bob role network-operator",
+		"username bob ssh-key ssh-rsa AAAA bob@host",
+		"username alice nopassword",
+		"default username bob role",
+		"no username alice",
+	} {
+		neg, words := splitCommand(cmd)
+		if err := root.applyUsername(neg, words); err != nil {
+			t.Fatalf("applyUsername %q: %s", cmd, err)
+		}
+	}
+	want := append(append([]string{}, rootDefaults...),
+		"username bob privilege 3 secret 0 pass",
+		"username bob ssh-key ssh-rsa AAAA bob@host")
+	if got := lines(root); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestConfigVlans_UnitTest(t *testing.T) {
+	tests := []struct {
+		in, out string
+		valid   bool
+	}{
+		{"1", "1", true},
+		{"3,1-2,10-12,14", "1-3,10-12,14", true},
+		{"all", "1-4094", true},
+		{"none", "none", true},
+		{"0", "", false},
+		{"4095", "", false},
+		{"20-10", "", false},
+		{"a", "", false},
+	}
+	for _, tt := range tests {
+		vlans, err := parseVlans(tt.in)
+		if (err == nil) != tt.valid {
+			t.Errorf("parseVlans(%q): unexpected error %v", tt.in, err)
+			continue
+		}
+		if got := formatVlans(vlans); tt.valid && got != tt.out {
+			t.Errorf("parseVlans(%q): expected %q, got %q", tt.in, tt.out, got)
+		}
+	}
+}
+
+func TestParseConfig_UnitTest(t *testing.T) {
+	config, err := parseConfig(strings.Join([]string{
+		"hostname veos",
+		"!",
+		"interface Ethernet1",
+		"   description test",
+		"   mc-tx-queue 0",
+		"      priority strict",
+		"   !",
+		"!",
+		"interface Ethernet2",
+		"!",
+		"end",
+	}, "\n"))
+	if err != nil {
+		t.Fatalf("parseConfig: %s", err)
+	}
+	want := "hostname veos\ninterface Ethernet1\n   description test\n" +
+		"   mc-tx-queue 0\n      priority strict\n   !\n!\n" +
+		"interface Ethernet2\n!\n"
+	if got := config.render(false); got != want {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+	if !hasLine(config.child("interface Ethernet2"), "no description") {
+		t.Fatalf("Defaults not applied to Ethernet2")
+	}
+
+	if _, err := parseConfig("hostname veos\n      description test"); err == nil {
+		t.Fatalf("Expected indentation error")
+	}
+}
@@ -0,0 +1,154 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SetResponse registers the result returned for command when the json
+// encoding is requested.
+func (s *Server) SetResponse(command string, result map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jsonResponses[command] = result
+}
+
+// SetTextResponse registers the output returned for command when the
+// text encoding is requested.
+func (s *Server) SetTextResponse(command string, output string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.textResponses[command] = output
+}
+
+// SetCommandError makes command fail with the given errors. Calling it
+// without errors removes a previously registered failure.
+func (s *Server) SetCommandError(command string, errs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(errs) == 0 {
+		delete(s.cmdErrors, command)
+		return
+	}
+	s.cmdErrors[command] = errs
+}
+
+// fixture returns the registered result for command, if any
+func (s *Server) fixture(command, format string) (map[string]interface{}, bool) {
+	if format == "text" {
+		if output, found := s.textResponses[command]; found {
+			return map[string]interface{}{"output": output}, true
+		}
+		return nil, false
+	}
+	result, found := s.jsonResponses[command]
+	return result, found
+}
+
+// LoadFixture registers the content of filename as the response for
+// command. Files ending in .json hold the json result of the command;
+// either the result object itself or a complete runCmds response whose
+// last result is used. Any other file holds the text output of the
+// command, optionally wrapped in a {"output": ...} object.
+func (s *Server) LoadFixture(command, filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if filepath.Ext(filename) != ".json" {
+		var wrapped struct {
+			Output *string `json:"output"`
+		}
+		if json.Unmarshal(data, &wrapped) == nil && wrapped.Output != nil {
+			s.SetTextResponse(command, *wrapped.Output)
+		} else {
+			s.SetTextResponse(command, string(data))
+		}
+		return nil
+	}
+	result, err := decodeFixture(data)
+	if err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	s.SetResponse(command, result)
+	return nil
+}
+
+// LoadFixtures registers every .json and .text file found in dir as a
+// response. The command is derived from the file name by replacing
+// underscores with spaces, so show_ip_route.json answers 'show ip route'.
+func (s *Server) LoadFixtures(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".text") {
+			continue
+		}
+		command := strings.Replace(strings.TrimSuffix(entry.Name(), ext), "_", " ", -1)
+		if err := s.LoadFixture(command, filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeFixture decodes a json fixture into a command result
+func decodeFixture(data []byte) (map[string]interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if obj, ok := v.(map[string]interface{}); ok {
+		if _, found := obj["jsonrpc"]; !found {
+			return obj, nil
+		}
+		v = obj["result"]
+	}
+	list, ok := v.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("no result found")
+	}
+	result, ok := list[len(list)-1].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("result is not an object")
+	}
+	return result, nil
+}
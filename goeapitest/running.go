@@ -0,0 +1,346 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapitest
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// defaultConfig is the running-config a Server starts with
+const defaultConfig = `hostname localhost
+username admin privilege 1 role network-admin nopassword
+!
+vlan 1
+!
+interface Ethernet1
+!
+interface Ethernet2
+!
+interface Ethernet3
+!
+interface Ethernet4
+!
+interface Ethernet5
+!
+interface Ethernet6
+!
+interface Ethernet7
+!
+interface Ethernet8
+!
+interface Management1
+!
+mlag configuration
+!
+end
+`
+
+// SetRunningConfig replaces the running-config of the Server with config,
+// given in the format of show running-config. Settings missing from
+// config take their default value. The startup-config is set to the same
+// config.
+func (s *Server) SetRunningConfig(config string) error {
+	running, err := parseConfig(config)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = running
+	s.startup = running.clone()
+	s.sessions = make(map[string]*configNode)
+	return nil
+}
+
+// LoadRunningConfig replaces the running-config of the Server with the
+// content of filename. See SetRunningConfig.
+func (s *Server) LoadRunningConfig(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return s.SetRunningConfig(string(data))
+}
+
+// RunningConfig returns the running-config of the Server as shown by
+// show running-config all
+func (s *Server) RunningConfig() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running.render(true)
+}
+
+// show answers the show commands served from the simulated config:
+// show running-config, show startup-config and show session-config.
+// Other show commands return an empty result.
+func (s *Server) show(cli *cliState, words []string,
+	format string) (map[string]interface{}, []string) {
+	var config *configNode
+	args := words[2:]
+	switch words[1] {
+	case "running-config":
+		config = s.running
+	case "startup-config":
+		config = s.startup
+	case "session-config":
+		name := cli.session
+		if len(args) > 1 && args[0] == "named" {
+			name, args = args[1], args[2:]
+		}
+		if config = s.sessions[name]; config == nil {
+			return nil, []string{"Not in a configuration session"}
+		}
+		if len(args) == 1 && args[0] == "diffs" {
+			diff := diffConfig(s.running, config, name)
+			if format == "json" {
+				return map[string]interface{}{"diff": diff}, nil
+			}
+			return map[string]interface{}{"output": diff}, nil
+		}
+	default:
+		return emptyResult(format), nil
+	}
+
+	all := len(args) > 0 && args[0] == "all"
+	if all {
+		args = args[1:]
+	}
+	config, err := filterConfig(config, args, all)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	if format == "json" {
+		return map[string]interface{}{
+			"cmds":   config.cmds(all),
+			"header": []interface{}{},
+		}, nil
+	}
+	output := config.render(all)
+	if len(args) == 0 {
+		output = "! Command: " + strings.Join(words, " ") + "\n!\n" +
+			output + "end\n"
+	}
+	return map[string]interface{}{"output": output}, nil
+}
+
+// filterConfig returns the part of config selected by the arguments of
+// show running-config: section REGEX or interfaces NAME...
+func filterConfig(config *configNode, args []string,
+	all bool) (*configNode, error) {
+	if len(args) == 0 {
+		return config, nil
+	}
+	filtered := &configNode{section: true, template: config.template}
+	switch {
+	case len(args) < 2:
+		return nil, fmt.Errorf("Incomplete command")
+	case args[0] == "section":
+		re, err := regexp.Compile(strings.Join(args[1:], " "))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid regular expression")
+		}
+		for _, c := range config.children {
+			if config.shown(c, all) && c.matches(re, all) {
+				filtered.children = append(filtered.children, c)
+			}
+		}
+	case args[0] == "interfaces":
+		for _, name := range args[1:] {
+			if c := config.child("interface " + interfaceName(name)); c != nil {
+				filtered.children = append(filtered.children, c)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("Invalid input (at token 2: '%s')", args[0])
+	}
+	return filtered, nil
+}
+
+// matches returns true if the line or one of the lines of its section
+// matches re. Unless all is set, default settings are ignored.
+func (n *configNode) matches(re *regexp.Regexp, all bool) bool {
+	if re.MatchString(n.line) {
+		return true
+	}
+	for _, c := range n.children {
+		if n.shown(c, all) && c.matches(re, all) {
+			return true
+		}
+	}
+	return false
+}
+
+// shown returns true if the child c of the section is shown. Unless all
+// is set, default settings are omitted.
+func (n *configNode) shown(c *configNode, all bool) bool {
+	return all || c.section || !n.isDefault(c.line)
+}
+
+// render returns the lines of the section in the format of show
+// running-config. Unless all is set, default settings are omitted.
+func (n *configNode) render(all bool) string {
+	var b strings.Builder
+	n.write(&b, "", all)
+	return b.String()
+}
+
+func (n *configNode) write(b *strings.Builder, indent string, all bool) {
+	for _, c := range n.children {
+		if !n.shown(c, all) {
+			continue
+		}
+		b.WriteString(indent + c.line + "\n")
+		if c.section {
+			c.write(b, indent+"   ", all)
+			b.WriteString(indent + "!\n")
+		}
+	}
+}
+
+// cmds returns the lines of the section in the json format of show
+// running-config.
+func (n *configNode) cmds(all bool) map[string]interface{} {
+	cmds := make(map[string]interface{})
+	for _, c := range n.children {
+		switch {
+		case c.section:
+			cmds[c.line] = map[string]interface{}{"cmds": c.cmds(all)}
+		case n.shown(c, all):
+			cmds[c.line] = nil
+		}
+	}
+	return cmds
+}
+
+// rawLine is a line of a config being parsed
+type rawLine struct {
+	line     string
+	children []*rawLine
+}
+
+// parseConfig parses a config in the format of show running-config
+func parseConfig(text string) (*configNode, error) {
+	root := &rawLine{}
+	stack := []*rawLine{root}
+	banner := false
+	for num, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \r")
+		trimmed := strings.TrimSpace(line)
+		// Banners are not simulated, their text is skipped up to EOF
+		if banner || strings.HasPrefix(trimmed, "banner ") {
+			banner = trimmed != "EOF"
+			continue
+		}
+		if trimmed == "" || trimmed[0] == '!' || trimmed == "end" {
+			continue
+		}
+		depth := (len(line) - len(trimmed)) / 3
+		if depth >= len(stack) {
+			return nil, fmt.Errorf("line %d: unexpected indentation", num+1)
+		}
+		r := &rawLine{line: trimmed}
+		stack[depth].children = append(stack[depth].children, r)
+		stack = append(stack[:depth+1], r)
+	}
+	config := newSection("")
+	config.load(root.children)
+	return config, nil
+}
+
+// load applies parsed lines to the section
+func (n *configNode) load(lines []*rawLine) {
+	for _, r := range lines {
+		switch {
+		case len(r.children) > 0 || opensSection(r.line, n):
+			c := n.child(r.line)
+			if c == nil {
+				c = newSection(r.line)
+				n.addSection(c)
+			}
+			c.load(r.children)
+		case n.line == "" && strings.HasPrefix(r.line, "username "):
+			n.insertLine(-1, r.line)
+		default:
+			n.apply(r.line)
+		}
+	}
+}
+
+// diffConfig returns the differences between the running-config and the
+// config of a session in unified diff format.
+func diffConfig(running, session *configNode, name string) string {
+	a := strings.Split(strings.TrimSuffix(running.render(false), "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(session.render(false), "\n"), "\n")
+
+	// lcs[i][j] holds the length of the longest common subsequence of
+	// a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out strings.Builder
+	changed := false
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out.WriteString(" " + a[i] + "\n")
+			i, j = i+1, j+1
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			out.WriteString("+" + b[j] + "\n")
+			j, changed = j+1, true
+		default:
+			out.WriteString("-" + a[i] + "\n")
+			i, changed = i+1, true
+		}
+	}
+	if !changed {
+		return ""
+	}
+	return "--- system:/running-config\n+++ session:/" + name +
+		"-session-config\n" + out.String()
+}
@@ -0,0 +1,507 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapitest
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aristanetworks/goeapi"
+	"github.com/aristanetworks/goeapi/config"
+	"github.com/aristanetworks/goeapi/module"
+)
+
+func connect(t *testing.T) (*Server, *goeapi.Node) {
+	srv := NewServer()
+	node, err := srv.Connect()
+	if err != nil {
+		srv.Close()
+		t.Fatalf("Connect: %s", err)
+	}
+	return srv, node
+}
+
+func showText(t *testing.T, node *goeapi.Node, cmd string) string {
+	resp, err := node.RunCommands([]string{cmd}, "text")
+	if err != nil {
+		t.Fatalf("%s: %s", cmd, err)
+	}
+	return resp.Result[0]["output"].(string)
+}
+
+func TestRunningConfigVlans_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	vlans := module.Vlan(node)
+	if !vlans.Create("10") || !vlans.SetName("10", "servers") ||
+		!vlans.AddTrunkGroup("10", "tg1") {
+		t.Fatalf("Failed to configure vlan 10")
+	}
+	want := module.VlanConfig{"name": "servers", "state": "active",
+		"trunk_groups": "tg1"}
+	if got := vlans.Get("10"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	if got := vlans.GetAll(); len(got) != 2 || got["1"] == nil {
+		t.Fatalf("Expected vlans 1 and 10, got %v", got)
+	}
+	if !vlans.Delete("10") || vlans.Get("10") != nil {
+		t.Fatalf("Failed to delete vlan 10")
+	}
+
+	err := node.ConfigWithErr("vlan 5000")
+	if cmdErr, ok := err.(*goeapi.CommandError); !ok ||
+		!strings.HasPrefix(cmdErr.Errors[0], "Invalid input") {
+		t.Fatalf("Expected invalid input error, got %v", err)
+	}
+}
+
+func TestRunningConfigInterfaces_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	ports := module.SwitchPort(node)
+	if !ports.SetMode("Ethernet1", "trunk") ||
+		!ports.SetTrunkAllowedVlans("Ethernet1", "10,20-30") ||
+		!ports.AddTrunkGroup("Ethernet1", "tg1") {
+		t.Fatalf("Failed to configure Ethernet1")
+	}
+	want := module.SwitchPortConfig{"name": "Ethernet1", "mode": "trunk",
+		"access_vlan": "1", "trunk_native_vlan": "1",
+		"trunk_allowed_vlans": "10,20-30", "trunk_groups": "tg1"}
+	if got := ports.Get("Ethernet1"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+
+	intfs := module.Interface(node)
+	if !intfs.SetDescription("Et2", "uplink to core") ||
+		!intfs.SetShutdown("Et2", true) {
+		t.Fatalf("Failed to configure Ethernet2")
+	}
+	if got := intfs.Get("Ethernet2"); got["description"] != "uplink to core" ||
+		got["shutdown"] != "true" {
+		t.Fatalf("Unexpected Ethernet2 config %v", got)
+	}
+
+	ip := module.IPInterface(node)
+	if !ip.Create("Ethernet3") || !ip.SetAddress("Ethernet3", "10.0.0.1/24") ||
+		!ip.SetMtu("Ethernet3", 9000) {
+		t.Fatalf("Failed to configure Ethernet3")
+	}
+	got, err := ip.Get("Ethernet3")
+	if err != nil || got["address"] != "10.0.0.1/24" || got["mtu"] != "9000" {
+		t.Fatalf("Unexpected Ethernet3 config %v: %v", got, err)
+	}
+
+	// Physical interfaces are reset rather than removed
+	if !intfs.Delete("Ethernet2") || intfs.Get("Ethernet2")["shutdown"] != "false" {
+		t.Fatalf("Ethernet2 was not reset")
+	}
+}
+
+func TestRunningConfigSystem_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	users := module.User(node)
+	if ok, err := users.Create("bob", false, "$1$hash", "md5"); !ok {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if ok, err := users.SetPrivilege("bob", 3); !ok {
+		t.Fatalf("Failed to set privilege: %v", err)
+	}
+	if !users.SetRole("bob", "network-operator") {
+		t.Fatalf("Failed to set role")
+	}
+	user := users.Get("bob")
+	if user["privilege"] != "3" || user["role"] != "network-operator" ||
+		user["format"] != "5" || user["secret"] != "$1$hash" {
+		t.Fatalf("Unexpected user config %v", user)
+	}
+
+	system := module.System(node)
+	if !system.SetHostname("veos") || !system.SetIPRouting("", true) {
+		t.Fatalf("Failed to configure system")
+	}
+	want := module.SystemConfig{"hostname": "veos", "iprouting": "true"}
+	if got := system.Get(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+
+	acls := module.Acl(node)
+	if !acls.Create("mgmt") ||
+		!acls.AddEntry("mgmt", "permit", "10.0.0.0", "8", true) ||
+		!acls.AddEntry("mgmt", "deny", "0.0.0.0", "0", false) {
+		t.Fatalf("Failed to configure ACL")
+	}
+	acl, err := acls.Get("mgmt")
+	entries := acl.Entries()
+	if err != nil || len(entries) != 2 ||
+		entries["10"]["srcaddr"] != "10.0.0.0" ||
+		entries["20"]["action"] != "deny" {
+		t.Fatalf("Unexpected ACL %v: %v", acl, err)
+	}
+
+	mlag := module.Mlag(node)
+	if !mlag.SetDomainID("mlag1") || !mlag.SetPeerLink("Port-Channel10") ||
+		!mlag.SetMlagID("Port-Channel10", "10") {
+		t.Fatalf("Failed to configure mlag")
+	}
+	config := mlag.Get()
+	if config.DomainID() != "mlag1" || config.PeerLink() != "Port-Channel10" ||
+		config.InterfaceConfig("Port-Channel10") != "10" {
+		t.Fatalf("Unexpected mlag config %v", config)
+	}
+}
+
+func TestRunningConfigBgp_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	bgp := module.Bgp(node)
+	if !bgp.Create(65000) || !bgp.SetRouterID("1.1.1.1") {
+		t.Fatalf("Failed to configure bgp")
+	}
+	neighbors := bgp.Neighbors()
+	if !neighbors.SetRemoteAS("10.0.0.1", "65001") {
+		t.Fatalf("Failed to configure neighbor")
+	}
+	if got := neighbors.Get("10.0.0.1"); got["remote_as"] != "65001" {
+		t.Fatalf("Unexpected neighbor config %v", got)
+	}
+	if got := bgp.Get(); got.BgpAs() != "65000" || got.RouterID() != "1.1.1.1" {
+		t.Fatalf("Unexpected bgp config %v", got)
+	}
+
+	err := node.ConfigWithErr("router bgp 65001")
+	if cmdErr, ok := err.(*goeapi.CommandError); !ok ||
+		!strings.Contains(cmdErr.Errors[0], "65000") {
+		t.Fatalf("Expected BGP already running error, got %v", err)
+	}
+}
+
+func TestRunningConfigShow_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	if err := node.ConfigWithErr("vlan 10,20", "name test", "exit",
+		"interface Et1", "description test", "hostname veos"); err != nil {
+		t.Fatalf("ConfigWithErr: %s", err)
+	}
+
+	output := showText(t, node, "show running-config section name test")
+	want := "vlan 10\n   name test\n!\nvlan 20\n   name test\n!\n"
+	if output != want {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", want, output)
+	}
+	output = showText(t, node, "show running-config interfaces Ethernet1")
+	if output != "interface Ethernet1\n   description test\n!\n" {
+		t.Fatalf("Unexpected interface output:\n%s", output)
+	}
+	output = showText(t, node, "show running-config all interfaces Ethernet1")
+	if !strings.Contains(output, "   no shutdown\n") {
+		t.Fatalf("Defaults missing from all output:\n%s", output)
+	}
+	output = showText(t, node, "show running-config")
+	if !strings.HasPrefix(output, "! Command: show running-config\n") ||
+		!strings.Contains(output, "\nhostname veos\n") ||
+		!strings.HasSuffix(output, "\nend\n") {
+		t.Fatalf("Unexpected running-config:\n%s", output)
+	}
+
+	resp, err := node.GetConfig("running-config", "section name test", "json")
+	if err != nil {
+		t.Fatalf("GetConfig: %s", err)
+	}
+	cmds := resp["cmds"].(map[string]interface{})
+	vlan := cmds["vlan 10"].(map[string]interface{})["cmds"].(map[string]interface{})
+	if _, found := vlan["name test"]; !found || len(cmds) != 2 {
+		t.Fatalf("Unexpected json config %v", resp)
+	}
+}
+
+func TestRunningConfigSessions_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	session := node.NewSession("test")
+	if err := session.Add("hostname staged"); err != nil {
+		t.Fatalf("Add: %s", err)
+	}
+	if module.System(node).Get()["hostname"] != "localhost" {
+		t.Fatalf("Session config applied before commit")
+	}
+	diff, err := session.Diff()
+	if err != nil || !strings.Contains(diff, "\n+hostname staged\n") {
+		t.Fatalf("Unexpected diff %q: %v", diff, err)
+	}
+	if err := session.Commit(); err != nil {
+		t.Fatalf("Commit: %s", err)
+	}
+	if module.System(node).Get()["hostname"] != "staged" {
+		t.Fatalf("Session config not applied by commit")
+	}
+
+	session = node.NewSession("aborted")
+	session.Add("hostname aborted")
+	if err := session.Abort(); err != nil {
+		t.Fatalf("Abort: %s", err)
+	}
+	if module.System(node).Get()["hostname"] != "staged" {
+		t.Fatalf("Aborted session config applied")
+	}
+
+	if strings.Contains(showText(t, node, "show startup-config"), "staged") {
+		t.Fatalf("startup-config changed before copy")
+	}
+	node.RunCommands([]string{"copy running-config startup-config"}, "json")
+	if !strings.Contains(showText(t, node, "show startup-config"), "staged") {
+		t.Fatalf("startup-config not saved")
+	}
+}
+
+func TestLoadRunningConfig_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	err := srv.LoadRunningConfig(filepath.Join(fixturesDir, "running_config.text"))
+	if err != nil {
+		t.Fatalf("LoadRunningConfig: %s", err)
+	}
+	vlans := module.Vlan(node).GetAll()
+	if len(vlans) != 4 || vlans["100"]["name"] != "mytest" {
+		t.Fatalf("Unexpected vlans %v", vlans)
+	}
+	port := module.SwitchPort(node).Get("Ethernet1")
+	if port["trunk_groups"] != "foo,bar" {
+		t.Fatalf("Unexpected Ethernet1 config %v", port)
+	}
+}
+
+func TestRunningConfigCache_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	count := func(cmd string) int {
+		n := 0
+		for _, c := range srv.Commands() {
+			if c == cmd {
+				n++
+			}
+		}
+		return n
+	}
+
+	switchports := module.SwitchPort(node)
+	if ports := switchports.GetAll(); len(ports) != 8 {
+		t.Fatalf("Expected 8 switchports, got %d", len(ports))
+	}
+	if n := count("show running-config all"); n != 1 {
+		t.Fatalf("Expected 1 running-config fetch, got %d", n)
+	}
+
+	if !switchports.SetAccessVlan("Ethernet1", "10") {
+		t.Fatal("SetAccessVlan failed")
+	}
+	if got := switchports.Get("Ethernet1")["access_vlan"]; got != "10" {
+		t.Fatalf("Expected access_vlan 10 after Config, got %q", got)
+	}
+	if n := count("show running-config all"); n != 2 {
+		t.Fatalf("Expected 2 running-config fetches, got %d", n)
+	}
+}
+
+func TestRunningConfigDiff_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+	intendedSrv, intendedNode := connect(t)
+	defer intendedSrv.Close()
+
+	err := node.ConfigWithErr("vlan 10", "name ten", "vlan 20",
+		"interface Ethernet1", "description old", "switchport access vlan 10",
+		"switchport trunk group foo", "switchport trunk group bar",
+		"interface Ethernet2", "shutdown",
+		"router bgp 65000", "router-id 1.1.1.1", "vrf blue", "rd 1:1")
+	if err != nil {
+		t.Fatalf("Config: %s", err)
+	}
+	err = intendedNode.ConfigWithErr("vlan 10", "name TEN", "vlan 30",
+		"interface Ethernet1", "description new uplink",
+		"switchport access vlan 30", "switchport trunk group foo",
+		"router bgp 65001", "router-id 1.1.1.1", "vrf red", "rd 2:2")
+	if err != nil {
+		t.Fatalf("Config: %s", err)
+	}
+
+	running := config.Parse(showText(t, node, "show running-config"))
+	intended := config.Parse(showText(t, intendedNode, "show running-config"))
+	commands := config.Diff(running, intended)
+	if len(commands) == 0 {
+		t.Fatal("Expected commands")
+	}
+	if err := node.ConfigWithErr(commands...); err != nil {
+		t.Fatalf("Config %q: %s", commands, err)
+	}
+	running = config.Parse(showText(t, node, "show running-config"))
+	if diff := config.Diff(running, intended); len(diff) != 0 {
+		t.Fatalf("Config differs after applying %q:\n%s", commands, diff)
+	}
+}
+
+func TestRunningConfigReconcile_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	if err := node.ConfigWithErr("vlan 10", "vlan 99"); err != nil {
+		t.Fatalf("Config: %s", err)
+	}
+	desired := module.DesiredState{
+		Vlans: module.VlanConfigMap{
+			"10": module.VlanConfig{"name": "ten"},
+			"20": module.VlanConfig{"name": "twenty"},
+		},
+		SwitchPorts: module.SwitchPortConfigMap{
+			"Ethernet1": module.SwitchPortConfig{"mode": "trunk",
+				"trunk_allowed_vlans": "10,20", "trunk_groups": "foo"},
+		},
+		Interfaces: map[string]module.InterfaceConfig{
+			"Ethernet1": module.InterfaceConfig{"description": "uplink"},
+		},
+	}
+	opts := module.ReconcileOptions{Purge: true}
+	commands, err := module.Reconcile(node, desired, opts)
+	if err != nil {
+		t.Fatalf("Reconcile %q: %s", commands, err)
+	}
+	if len(commands) == 0 {
+		t.Fatal("Expected commands")
+	}
+	if vlans := module.Vlan(node).GetAll(); vlans["99"] != nil ||
+		vlans["20"]["name"] != "twenty" {
+		t.Fatalf("Unexpected vlans after reconcile: %#v", vlans)
+	}
+	if commands, err = module.Reconcile(node, desired, opts); err != nil ||
+		len(commands) != 0 {
+		t.Fatalf("Reconcile not idempotent: %q (%v)", commands, err)
+	}
+}
+
+func TestRunningConfigTrunkAllowedVlans_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	sp := module.SwitchPort(node)
+	if !sp.SetTrunkAllowedVlans("Ethernet1", "10-20") {
+		t.Fatal("SetTrunkAllowedVlans failed")
+	}
+	if !sp.AddTrunkAllowedVlans("Ethernet1", module.NewVlanSet(5, 21)) {
+		t.Fatal("AddTrunkAllowedVlans failed")
+	}
+	if !sp.RemoveTrunkAllowedVlans("Ethernet1", module.NewVlanSet(15)) {
+		t.Fatal("RemoveTrunkAllowedVlans failed")
+	}
+	want, _ := module.ParseVlanSet("5,10-14,16-21")
+	port, err := sp.GetTyped("Ethernet1")
+	if err != nil || port == nil || port.TrunkAllowedVlans.Set() != want {
+		t.Fatalf("Unexpected switchport: %#v", port)
+	}
+}
+
+func TestRunningConfigBatch_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	batch := module.Batch(node)
+	vlan := module.Vlan(batch.Node())
+	sp := module.SwitchPort(batch.Node())
+	vlan.Create("10")
+	vlan.SetName("10", "ten")
+	for _, intf := range []string{"Ethernet1", "Ethernet2", "Ethernet3"} {
+		sp.SetMode(intf, "trunk")
+		sp.SetTrunkAllowedVlans(intf, "10")
+	}
+	if got := module.Vlan(node).Get("10"); got != nil {
+		t.Fatalf("Batch applied before flush: %#v", got)
+	}
+	if err := batch.Flush(); err != nil {
+		t.Fatalf("Flush %q: %s", batch.Commands(), err)
+	}
+	if got := module.Vlan(node).Get("10"); got["name"] != "ten" {
+		t.Fatalf("Unexpected vlan after flush: %#v", got)
+	}
+	for name, port := range module.SwitchPort(node).GetAll() {
+		if name <= "Ethernet3" && (port["mode"] != "trunk" ||
+			port["trunk_allowed_vlans"] != "10") {
+			t.Fatalf("Unexpected switchport after flush: %#v", port)
+		}
+	}
+
+	batch.Add("vlan 20")
+	batch.Add("vlan 5000")
+	if err := batch.FlushSession(""); err == nil {
+		t.Fatal("FlushSession should fail")
+	}
+	if got := module.Vlan(node).Get("20"); got != nil {
+		t.Fatalf("Failed session applied: %#v", got)
+	}
+}
+
+func TestRunningConfigBatchSessionNode_UnitTest(t *testing.T) {
+	srv, node := connect(t)
+	defer srv.Close()
+
+	session := node.NewSession("batch")
+	batch := module.Batch(session.Node())
+	vlan := module.Vlan(batch.Node())
+	vlan.Create("30")
+	vlan.Create("31")
+	if err := batch.Flush(); err != nil {
+		t.Fatalf("Flush %q: %s", batch.Commands(), err)
+	}
+	for _, vid := range []string{"30", "31"} {
+		if got := module.Vlan(node).Get(vid); got != nil {
+			t.Fatalf("Vlan %s applied before commit: %#v", vid, got)
+		}
+	}
+	if err := session.Commit(); err != nil {
+		t.Fatalf("Commit: %s", err)
+	}
+	for _, vid := range []string{"30", "31"} {
+		if got := module.Vlan(node).Get(vid); got == nil {
+			t.Fatalf("Vlan %s not applied by commit", vid)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+// Package goeapitest provides an in-process eAPI server for testing code
+// built on goeapi without access to EOS devices.
+//
+// A Server answers JSON-RPC runCmds requests over HTTP, HTTPS or a unix
+// domain socket. Responses are served from a scriptable fixture store:
+// results can be registered per command for both the json and text
+// encodings, commands can be made to fail, the enable password can be
+// enforced and latency can be injected.
+//
+// Configuration commands are applied to a simulated running-config, from
+// which show running-config, show startup-config and show session-config
+// are answered, so that configuration done through the module package
+// can be read back. Registered fixtures take precedence over the
+// simulated config.
+//
+//	srv := goeapitest.NewServer()
+//	defer srv.Close()
+//	srv.LoadFixtures("testdata/fixtures")
+//	node, err := srv.Connect()
+package goeapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aristanetworks/goeapi"
+)
+
+// Error codes returned by the Server
+const (
+	// ErrCodeParse is returned for requests that are not valid JSON
+	ErrCodeParse = -32700
+	// ErrCodeMethodNotFound is returned for methods other than runCmds
+	ErrCodeMethodNotFound = -32601
+	// ErrCodeCommand is returned when a command fails
+	ErrCodeCommand = 1002
+)
+
+// Server is an in-process eAPI server
+type Server struct {
+	srv        *httptest.Server
+	transport  string
+	socketPath string
+
+	mu            sync.Mutex
+	jsonResponses map[string]map[string]interface{}
+	textResponses map[string]string
+	cmdErrors     map[string][]string
+	enablePasswd  string
+	latency       time.Duration
+	commands      []string
+	running       *configNode
+	startup       *configNode
+	sessions      map[string]*configNode
+}
+
+// newServer returns a Server that is not yet started
+func newServer(transport string) *Server {
+	s := &Server{
+		transport:     transport,
+		jsonResponses: make(map[string]map[string]interface{}),
+		textResponses: make(map[string]string),
+		cmdErrors:     make(map[string][]string),
+		sessions:      make(map[string]*configNode),
+	}
+	s.running, _ = parseConfig(defaultConfig)
+	s.startup = s.running.clone()
+	s.srv = httptest.NewUnstartedServer(s)
+	return s
+}
+
+// NewServer starts and returns a Server listening for HTTP requests on a
+// local port. The caller should call Close when finished.
+func NewServer() *Server {
+	s := newServer("http")
+	s.srv.Start()
+	return s
+}
+
+// NewTLSServer starts and returns a Server listening for HTTPS requests
+// on a local port. The caller should call Close when finished.
+func NewTLSServer() *Server {
+	s := newServer("https")
+	s.srv.StartTLS()
+	return s
+}
+
+// NewUnixServer starts and returns a Server listening for HTTP requests
+// on the unix domain socket at path. The caller should call Close when
+// finished.
+func NewUnixServer(path string) (*Server, error) {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	s := newServer("socket")
+	s.socketPath = path
+	s.srv.Listener.Close()
+	s.srv.Listener = listener
+	s.srv.Start()
+	return s, nil
+}
+
+// Close shuts down the Server
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Transport returns the goeapi transport name for the Server: http,
+// https or socket.
+func (s *Server) Transport() string {
+	return s.transport
+}
+
+// Host returns the IP address the Server listens on. It is empty for
+// unix socket servers.
+func (s *Server) Host() string {
+	if addr, ok := s.srv.Listener.Addr().(*net.TCPAddr); ok {
+		return addr.IP.String()
+	}
+	return ""
+}
+
+// Port returns the TCP port the Server listens on. It is zero for unix
+// socket servers.
+func (s *Server) Port() int {
+	if addr, ok := s.srv.Listener.Addr().(*net.TCPAddr); ok {
+		return addr.Port
+	}
+	return 0
+}
+
+// SocketPath returns the path of the unix domain socket the Server
+// listens on. It is empty for HTTP and HTTPS servers.
+func (s *Server) SocketPath() string {
+	return s.socketPath
+}
+
+// Connection returns a goeapi connection to the Server
+func (s *Server) Connection() (goeapi.EapiConnectionEntity, error) {
+	if s.transport == "socket" {
+		conn := goeapi.NewSocketEapiConnection(s.transport, "localhost", "",
+			"", goeapi.UseDefaultPortNum)
+		conn.(*goeapi.SocketEapiConnection).SetSocketPath(s.socketPath)
+		return conn, nil
+	}
+	return goeapi.Connection(s.transport, s.Host(), "admin", "", s.Port())
+}
+
+// Connect returns a goeapi Node connected to the Server
+func (s *Server) Connect() (*goeapi.Node, error) {
+	if s.transport == "socket" {
+		conn, err := s.Connection()
+		if err != nil {
+			return nil, err
+		}
+		node := &goeapi.Node{}
+		node.SetConnection(conn)
+		node.SetAutoRefresh(true)
+		node.SetConfigCacheTTL(goeapi.DefaultConfigCacheTTL)
+		return node, nil
+	}
+	return goeapi.Connect(s.transport, s.Host(), "admin", "", s.Port())
+}
+
+// SetEnablePassword sets the password the enable command must be given.
+// An empty password disables the check.
+func (s *Server) SetEnablePassword(passwd string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enablePasswd = passwd
+}
+
+// SetLatency sets the delay applied before answering each request
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// Commands returns the commands received by the Server, in order
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+// ClearCommands clears the list of commands received by the Server
+func (s *Server) ClearCommands() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commands = nil
+}
+
+// runCmdsRequest is a JSON-RPC runCmds request. Unlike goeapi.Request it
+// accepts the "latest" version and the optional runCmds parameters.
+type runCmdsRequest struct {
+	Jsonrpc string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  struct {
+		Version    interface{}   `json:"version"`
+		Cmds       []interface{} `json:"cmds"`
+		Format     string        `json:"format"`
+		Timestamps bool          `json:"timestamps"`
+	} `json:"params"`
+	ID string `json:"id"`
+}
+
+// ServeHTTP answers a JSON-RPC runCmds request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	latency := s.latency
+	s.mu.Unlock()
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	var req runCmdsRequest
+	resp := &goeapi.JSONRPCResponse{Jsonrpc: "2.0"}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.Error = &goeapi.RespError{Code: ErrCodeParse,
+			Message: "Parse error: " + err.Error()}
+	} else if resp.ID = req.ID; req.Method != "runCmds" {
+		resp.Error = &goeapi.RespError{Code: ErrCodeMethodNotFound,
+			Message: "Method not found: " + req.Method}
+	} else {
+		resp.Result, resp.Error = s.runCmds(req.Params.Cmds, req.Params.Format)
+		if req.Params.Timestamps {
+			addTimestamps(resp.Result)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// addTimestamps adds the execution metadata eAPI reports when timestamps
+// are requested to each result.
+func addTimestamps(results []map[string]interface{}) {
+	now := float64(time.Now().UnixNano()) / 1e9
+	for idx, result := range results {
+		// results may be shared with fixtures, so extend a copy
+		withMeta := make(map[string]interface{}, len(result)+1)
+		for k, v := range result {
+			withMeta[k] = v
+		}
+		withMeta["_meta"] = map[string]interface{}{
+			"execStartTime": now,
+			"execDuration":  0.0,
+		}
+		results[idx] = withMeta
+	}
+}
+
+// runCmds runs the commands of a request in order, stopping at the first
+// failing command.
+func (s *Server) runCmds(cmds []interface{},
+	format string) ([]map[string]interface{}, *goeapi.RespError) {
+	if format == "" {
+		format = "json"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cli := &cliState{}
+	results := make([]map[string]interface{}, 0, len(cmds))
+	for idx, entry := range cmds {
+		cmd, input := parseCmd(entry)
+		s.commands = append(s.commands, cmd)
+		result, errs := s.runCmd(cli, cmd, input, format)
+		if errs != nil {
+			data := make([]interface{}, 0, len(results)+1)
+			for _, r := range results {
+				data = append(data, r)
+			}
+			data = append(data, map[string]interface{}{"errors": errs})
+			return nil, &goeapi.RespError{
+				Code: ErrCodeCommand,
+				Message: fmt.Sprintf("CLI command %d of %d '%s' failed: "+
+					"invalid command", idx+1, len(cmds), cmd),
+				Data: data,
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// runCmd returns the result of a single command or the list of errors
+// if it fails.
+func (s *Server) runCmd(cli *cliState, cmd, input,
+	format string) (map[string]interface{}, []string) {
+	if cmd == "enable" {
+		if s.enablePasswd != "" && input != s.enablePasswd {
+			return nil, []string{"Bad secret"}
+		}
+		return emptyResult(format), nil
+	}
+	if errs, found := s.cmdErrors[cmd]; found {
+		return nil, errs
+	}
+	if result, found := s.fixture(cmd, format); found {
+		return result, nil
+	}
+	return s.exec(cli, cmd, format)
+}
+
+// parseCmd returns the command and input of a runCmds command entry
+func parseCmd(entry interface{}) (string, string) {
+	switch v := entry.(type) {
+	case string:
+		return v, ""
+	case map[string]interface{}:
+		cmd, _ := v["cmd"].(string)
+		input, _ := v["input"].(string)
+		return cmd, input
+	}
+	return fmt.Sprintf("%v", entry), ""
+}
+
+// emptyResult returns the result of a command producing no output
+func emptyResult(format string) map[string]interface{} {
+	if format == "text" {
+		return map[string]interface{}{"output": ""}
+	}
+	return map[string]interface{}{}
+}
+
+// String returns a description of the Server
+func (s *Server) String() string {
+	if s.transport == "socket" {
+		return "socket:" + s.socketPath
+	}
+	return s.transport + "://" + net.JoinHostPort(s.Host(), strconv.Itoa(s.Port()))
+}
@@ -0,0 +1,309 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapitest
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aristanetworks/goeapi"
+	"github.com/aristanetworks/goeapi/module"
+)
+
+const fixturesDir = "../testdata/fixtures"
+
+func TestServerFixtures_UnitTest(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	if err := srv.LoadFixtures(fixturesDir); err != nil {
+		t.Fatalf("LoadFixtures: %s", err)
+	}
+	node, err := srv.Connect()
+	if err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+
+	show := module.Show(node)
+	ver := show.ShowVersion()
+	if ver.ModelName == "" || ver.Version == "" {
+		t.Fatalf("ShowVersion returned empty result: %#v", ver)
+	}
+	arp, err := show.ShowARP()
+	if err != nil {
+		t.Fatalf("ShowARP: %s", err)
+	}
+	if len(arp.IPv4Neighbors) == 0 {
+		t.Fatalf("ShowARP returned no neighbors")
+	}
+
+	resp, err := node.RunCommands([]string{"show interfaces"}, "text")
+	if err != nil {
+		t.Fatalf("RunCommands: %s", err)
+	}
+	out := resp.Result[0]["output"].(string)
+	if !strings.HasPrefix(out, "Ethernet1 is up") {
+		t.Fatalf("Unexpected text output: %q", out)
+	}
+}
+
+func TestServerTransports_UnitTest(t *testing.T) {
+	unix, err := NewUnixServer(filepath.Join(t.TempDir(), "eapi.sock"))
+	if err != nil {
+		t.Fatalf("NewUnixServer: %s", err)
+	}
+	servers := []*Server{NewServer(), NewTLSServer(), unix}
+	for _, srv := range servers {
+		defer srv.Close()
+		srv.SetTextResponse("show hostname",
+			"Hostname: veos\nFQDN:     veos.example.com\n")
+		node, err := srv.Connect()
+		if err != nil {
+			t.Fatalf("%s: Connect: %s", srv, err)
+		}
+		resp, err := node.Enable([]string{"show hostname"})
+		if err != nil {
+			t.Fatalf("%s: Enable: %s", srv, err)
+		}
+		if !strings.Contains(resp[0]["result"], "veos.example.com") {
+			t.Fatalf("%s: Unexpected result %v", srv, resp)
+		}
+	}
+}
+
+func TestServerCommandError_UnitTest(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.SetCommandError("vlan 200", "VLAN 200 is reserved")
+	node, err := srv.Connect()
+	if err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+
+	err = node.ConfigWithErr("vlan 100", "vlan 200")
+	cmdErr, ok := err.(*goeapi.CommandError)
+	if !ok {
+		t.Fatalf("Expected *CommandError, got %T: %v", err, err)
+	}
+	if cmdErr.Code != ErrCodeCommand || cmdErr.Command != "vlan 200" ||
+		cmdErr.CommandIndex != 1 {
+		t.Fatalf("Unexpected error: %#v", cmdErr)
+	}
+	want := []string{"VLAN 200 is reserved"}
+	if !reflect.DeepEqual(cmdErr.Errors, want) {
+		t.Fatalf("Expected errors %v, got %v", want, cmdErr.Errors)
+	}
+
+	srv.SetCommandError("vlan 200")
+	if err := node.ConfigWithErr("vlan 200"); err != nil {
+		t.Fatalf("Expected error to be cleared, got %s", err)
+	}
+}
+
+func TestServerEnablePassword_UnitTest(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.SetEnablePassword("secret")
+	node, err := srv.Connect()
+	if err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+
+	_, err = node.Enable([]string{"show clock"})
+	cmdErr, ok := err.(*goeapi.CommandError)
+	if !ok || cmdErr.Command != "enable" ||
+		!reflect.DeepEqual(cmdErr.Errors, []string{"Bad secret"}) {
+		t.Fatalf("Expected Bad secret error, got %#v", err)
+	}
+	node.EnableAuthentication("secret")
+	if _, err := node.Enable([]string{"show clock"}); err != nil {
+		t.Fatalf("Expected success, got %s", err)
+	}
+}
+
+func TestServerLatency_UnitTest(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	node, err := srv.Connect()
+	if err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	srv.SetLatency(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := node.RunCommandsContext(ctx, []string{"show clock"}, "json"); err == nil {
+		t.Fatalf("Expected deadline error")
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("Request was not cancelled")
+	}
+}
+
+func TestServerCommands_UnitTest(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	node, err := srv.Connect()
+	if err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	srv.ClearCommands()
+
+	node.ConfigWithErr("hostname veos")
+	want := []string{"enable", "configure terminal", "hostname veos"}
+	if got := srv.Commands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestServerChunking_UnitTest(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.SetCommandError("vlan 30", "VLAN 30 is reserved")
+	node, err := srv.Connect()
+	if err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	node.SetChunkSize(3)
+	srv.ClearCommands()
+
+	err = node.ConfigWithErr("interface Ethernet1", "description a", "vlan 10",
+		"name ten", "vlan 20", "vlan 30", "vlan 40", "vlan 50", "vlan 60")
+	cmdErr, ok := err.(*goeapi.CommandError)
+	if !ok || cmdErr.Command != "vlan 30" || cmdErr.CommandIndex != 5 {
+		t.Fatalf("Expected vlan 30 to fail at index 5, got %#v", err)
+	}
+	want := []string{
+		"enable", "configure terminal", "interface Ethernet1", "description a",
+		"enable", "configure terminal", "interface Ethernet1", "vlan 10",
+		"name ten", "vlan 20",
+		"enable", "configure terminal", "vlan 20", "vlan 30",
+	}
+	if got := srv.Commands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	if len(cmdErr.Results) != 5 {
+		t.Fatalf("Expected results of the 5 preceding commands, got %v",
+			cmdErr.Results)
+	}
+
+	node.SetChunkSize(0)
+	resp, err := node.RunCommands([]string{"show clock", "show clock"}, "text")
+	if err != nil || len(resp.Result) != 2 {
+		t.Fatalf("Expected 2 results, got %#v %v", resp, err)
+	}
+}
+
+func TestServerRequestOptions_UnitTest(t *testing.T) {
+	unix, err := NewUnixServer(filepath.Join(t.TempDir(), "eapi.sock"))
+	if err != nil {
+		t.Fatalf("NewUnixServer: %s", err)
+	}
+	opts := goeapi.RequestOptions{Version: "latest", AutoComplete: true,
+		ExpandAliases: true, Timestamps: true, Streaming: true}
+	servers := []*Server{NewServer(), NewTLSServer(), unix}
+	for _, srv := range servers {
+		defer srv.Close()
+		srv.SetTextResponse("show hostname", "Hostname: veos\n")
+		node, err := srv.Connect()
+		if err != nil {
+			t.Fatalf("%s: Connect: %s", srv, err)
+		}
+		resp, err := node.RunCommandsWithOptions(context.Background(),
+			[]string{"show hostname"}, "text", opts)
+		if err != nil {
+			t.Fatalf("%s: RunCommandsWithOptions: %s", srv, err)
+		}
+		if len(resp.Result) != 1 || len(resp.Meta) != 1 {
+			t.Fatalf("%s: Expected one result and meta, got %#v", srv, resp)
+		}
+		if _, found := resp.Result[0]["_meta"]; found {
+			t.Fatalf("%s: _meta left in result %v", srv, resp.Result[0])
+		}
+		if resp.Meta[0].StartTime().IsZero() || resp.Result[0]["output"] != "Hostname: veos\n" {
+			t.Fatalf("%s: Unexpected response %#v", srv, resp)
+		}
+	}
+}
+
+func TestServerHandleOptions_UnitTest(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	if err := srv.LoadFixtures(fixturesDir); err != nil {
+		t.Fatalf("LoadFixtures: %s", err)
+	}
+	node, err := srv.Connect()
+	if err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+
+	handle, _ := node.GetHandle("json")
+	handle.SetOptions(goeapi.RequestOptions{Timestamps: true})
+	var ver module.ShowVersion
+	handle.AddCommand(&ver)
+	if err := handle.Call(); err != nil {
+		t.Fatalf("Call: %s", err)
+	}
+	meta := handle.DecodeMetadata()
+	if ver.Version == "" || len(meta) != 1 || meta[0].Exec == nil {
+		t.Fatalf("Unexpected response %#v, metadata %#v", ver, meta)
+	}
+	for _, key := range meta[0].Unused {
+		if key == "_meta" {
+			t.Fatal("_meta reported as an unused key")
+		}
+	}
+
+	handle.SetDirectDecode(true)
+	var direct module.ShowVersion
+	handle.AddCommand(&direct)
+	if err := handle.Call(); err != nil {
+		t.Fatalf("Call: %s", err)
+	}
+	if meta := handle.DecodeMetadata(); direct != ver || len(meta) != 1 ||
+		meta[0].Exec == nil {
+		t.Fatalf("Unexpected direct response %#v, metadata %#v", direct, meta)
+	}
+
+	resp, err := node.RunCommands([]string{"show version"}, "json")
+	if err != nil || resp.Meta != nil {
+		t.Fatalf("Expected no meta without timestamps, got %#v %v", resp, err)
+	}
+	if _, found := resp.Result[0]["_meta"]; found {
+		t.Fatal("Timestamps leaked into the fixture")
+	}
+}
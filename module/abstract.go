@@ -38,7 +38,13 @@
 
 package module
 
-import "github.com/aristanetworks/goeapi"
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aristanetworks/goeapi"
+	"github.com/aristanetworks/goeapi/config"
+)
 
 // AbstractBaseEntity object for all resources to derive from
 //
@@ -63,6 +69,18 @@ func (b *AbstractBaseEntity) Config() string {
 	return b.node.RunningConfig()
 }
 
+// ConfigTree returns the current running configuration parsed into a
+// config.Config. An empty config is returned on error.
+// Returns:
+//      *config.Config: running config
+func (b *AbstractBaseEntity) ConfigTree() *config.Config {
+	tree, err := b.node.ConfigTree(goeapi.RunningConfig)
+	if err != nil {
+		return config.Parse("")
+	}
+	return tree
+}
+
 // Version returns the current running version
 // Returns:
 //      String: version
@@ -77,7 +95,8 @@ func (b *AbstractBaseEntity) Error() error {
 	return b.node.GetConnection().Error()
 }
 
-// GetBlock scans the config and returns a block of code
+// GetBlock scans the config and returns a block of code. The config
+// is the copy cached by the node, see goeapi.Node.SetConfigCacheTTL.
 //
 // Args:
 //  parent (str): The parent string to search the config for and
@@ -88,8 +107,29 @@ func (b *AbstractBaseEntity) Error() error {
 //  the parent string is not found, then this method will
 //  return None.
 func (b *AbstractBaseEntity) GetBlock(parent string) (string, error) {
-	parent = `(?m)^` + parent + `$`
-	return b.node.GetSection(parent, "running-config")
+	section, err := b.getSection(parent)
+	if err != nil {
+		return "", err
+	}
+	return section.String(), nil
+}
+
+// getSection returns the section at the root of the running config whose
+// first line matches the parent regular expression.
+func (b *AbstractBaseEntity) getSection(parent string) (*config.Line, error) {
+	re, err := regexp.Compile(`^(?:` + parent + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid regexp")
+	}
+	tree, err := b.node.ConfigTree(goeapi.RunningConfig)
+	if err != nil {
+		return nil, err
+	}
+	section := tree.Match(re)
+	if section == nil {
+		return nil, fmt.Errorf("Config section not found: %s", parent)
+	}
+	return section, nil
 }
 
 // Configure sends the commands list to the node in config mode
@@ -105,7 +145,14 @@ func (b *AbstractBaseEntity) GetBlock(parent string) (string, error) {
 //  True if the commands are executed without exception otherwise
 //  False is returned
 func (b *AbstractBaseEntity) Configure(commands ...string) bool {
-	return b.node.Config(commands...)
+	return b.ConfigureE(commands...) == nil
+}
+
+// ConfigureE is the error-returning variant of Configure. If a command
+// is rejected by the node, the error is a *goeapi.CommandError whose
+// CommandIndex refers to commands.
+func (b *AbstractBaseEntity) ConfigureE(commands ...string) error {
+	return b.node.ConfigWithErr(commands...)
 }
 
 // CommandBuilder builds a command with keywords
@@ -141,7 +188,12 @@ func (b *AbstractBaseEntity) CommandBuilder(cmd string, value string, def bool,
 // Returns:
 //  True if the commands completed successfully
 func (b *AbstractBaseEntity) ConfigureInterface(name string, commands ...string) bool {
+	return b.ConfigureInterfaceE(name, commands...) == nil
+}
+
+// ConfigureInterfaceE is the error-returning variant of ConfigureInterface
+func (b *AbstractBaseEntity) ConfigureInterfaceE(name string, commands ...string) error {
 	var cmd = []string{"interface " + name}
 	commands = append(cmd, commands...)
-	return b.Configure(commands...)
+	return b.ConfigureE(commands...)
 }
@@ -159,13 +159,9 @@ func (a *AclEntity) Get(name string) (*AclConfig, error) {
 //  the nodes running configuration. If there are no ACLs configured,
 //  this method will return an empty hash.
 func (a *AclEntity) GetAll() map[string]*AclConfig {
-	config := a.Config()
-	re := regexp.MustCompile(`(?m)ip access-list standard ([^\s]+)`)
-	matches := re.FindAllStringSubmatch(config, -1)
 	aclConfigs := make(map[string]*AclConfig)
 
-	for _, acl := range matches {
-		name := acl[1]
+	for _, name := range a.ConfigTree().Values("ip access-list standard") {
 		aclConfigs[name], _ = a.Get(name)
 	}
 	return aclConfigs
@@ -236,12 +232,11 @@ func (a *AclEntity) parseEntries(config string) AclEntryMap {
 // Returns:
 //  Returns string representation of Acl config entry
 func (a *AclEntity) GetSection(name string) string {
-	parent := "ip access-list standard " + name
-	config, err := a.GetBlock(parent)
-	if err != nil {
+	section := a.ConfigTree().Child("ip access-list standard " + name)
+	if section == nil {
 		return ""
 	}
-	return config
+	return section.String()
 }
 
 // Create will create a new ACL resource in the nodes current
@@ -261,8 +256,13 @@ func (a *AclEntity) GetSection(name string) string {
 // Returns:
 //  returns true if the command completed successfully
 func (a *AclEntity) Create(name string) bool {
+	return a.CreateE(name) == nil
+}
+
+// CreateE is the error-returning variant of Create
+func (a *AclEntity) CreateE(name string) error {
 	var commands = []string{"ip access-list standard " + name}
-	return a.Configure(commands...)
+	return a.ConfigureE(commands...)
 }
 
 // Delete will delete an existing ACL resource from the nodes current
@@ -278,8 +278,13 @@ func (a *AclEntity) Create(name string) bool {
 // Returns:
 //  returns true if the command completed successfully
 func (a *AclEntity) Delete(name string) bool {
+	return a.DeleteE(name) == nil
+}
+
+// DeleteE is the error-returning variant of Delete
+func (a *AclEntity) DeleteE(name string) error {
 	var commands = []string{"no ip access-list standard " + name}
-	return a.Configure(commands...)
+	return a.ConfigureE(commands...)
 }
 
 // Default will configure the ACL using the default keyword.  This
@@ -296,8 +301,13 @@ func (a *AclEntity) Delete(name string) bool {
 // Returns:
 //  returns true if the command complete successfully
 func (a *AclEntity) Default(name string) bool {
+	return a.DefaultE(name) == nil
+}
+
+// DefaultE is the error-returning variant of Default
+func (a *AclEntity) DefaultE(name string) error {
 	var commands = []string{"default ip access-list standard " + name}
-	return a.Configure(commands...)
+	return a.ConfigureE(commands...)
 }
 
 // UpdateEntry will update an entry, identified by the seqno
@@ -319,7 +329,12 @@ func (a *AclEntity) Default(name string) bool {
 //  returns true if the command complete successfully
 func (a *AclEntity) UpdateEntry(name string, seqno string, action string, addr string,
 	prefixlen string, log bool) bool {
+	return a.UpdateEntryE(name, seqno, action, addr, prefixlen, log) == nil
+}
 
+// UpdateEntryE is the error-returning variant of UpdateEntry
+func (a *AclEntity) UpdateEntryE(name string, seqno string, action string,
+	addr string, prefixlen string, log bool) error {
 	commands := []string{"ip access-list standard " + name}
 	commands = append(commands, "no "+seqno)
 
@@ -329,7 +344,7 @@ func (a *AclEntity) UpdateEntry(name string, seqno string, action string, addr s
 	}
 	commands = append(commands, entry)
 	commands = append(commands, "exit")
-	return a.Configure(commands...)
+	return a.ConfigureE(commands...)
 }
 
 // AddEntry will add an entry to the specified ACL with the
@@ -350,7 +365,12 @@ func (a *AclEntity) UpdateEntry(name string, seqno string, action string, addr s
 //  returns true if the command complete successfully
 func (a *AclEntity) AddEntry(name string, action string, addr string,
 	prefixlen string, log bool) bool {
+	return a.AddEntryE(name, action, addr, prefixlen, log) == nil
+}
 
+// AddEntryE is the error-returning variant of AddEntry
+func (a *AclEntity) AddEntryE(name string, action string, addr string,
+	prefixlen string, log bool) error {
 	commands := []string{"ip access-list standard " + name}
 	entry := action + " " + addr + "/" + prefixlen
 	if log {
@@ -358,7 +378,7 @@ func (a *AclEntity) AddEntry(name string, action string, addr string,
 	}
 	commands = append(commands, entry)
 	commands = append(commands, "exit")
-	return a.Configure(commands...)
+	return a.ConfigureE(commands...)
 }
 
 // RemoveEntry will remove the entry specified by the seqno for
@@ -374,10 +394,15 @@ func (a *AclEntity) AddEntry(name string, action string, addr string,
 // Returns:
 //  returns true if the command complete successfully
 func (a *AclEntity) RemoveEntry(name string, seqno int) bool {
+	return a.RemoveEntryE(name, seqno) == nil
+}
+
+// RemoveEntryE is the error-returning variant of RemoveEntry
+func (a *AclEntity) RemoveEntryE(name string, seqno int) error {
 	var commands = []string{
 		"ip access-list standard " + name,
 		"no " + strconv.Itoa(seqno),
 		"exit",
 	}
-	return a.Configure(commands...)
+	return a.ConfigureE(commands...)
 }
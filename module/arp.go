@@ -32,6 +32,12 @@
 
 package module
 
+import (
+	"context"
+
+	"github.com/aristanetworks/goeapi"
+)
+
 type ShowARP struct {
 	DynamicEntries    int            `json:"dynamicEntries"`
 	IPv4Neighbors     []IPv4Neighbor `json:"ipV4Neighbors"`
@@ -52,22 +58,5 @@ func (a *ShowARP) GetCmd() string {
 }
 
 func (s *ShowEntity) ShowARP() (ShowARP, error) {
-	var showarp ShowARP
-
-	handle, err := s.node.GetHandle("json")
-	if err != nil {
-		return showarp, err
-	}
-
-	err = handle.AddCommand(&showarp)
-	if err != nil {
-		return showarp, err
-	}
-
-	if err := handle.Call(); err != nil {
-		return showarp, err
-	}
-
-	handle.Close()
-	return showarp, nil
+	return goeapi.Run[ShowARP](context.Background(), s.node)
 }
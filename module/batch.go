@@ -0,0 +1,179 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package module
+
+import (
+	"sync"
+
+	"github.com/aristanetworks/goeapi"
+)
+
+// BatchEntity collects the configuration commands produced by the setters
+// of any module entity and sends them to the node in a single request.
+//
+// Entities built on the Node returned by BatchEntity.Node record their
+// commands into the batch instead of sending them; their setters always
+// succeed. Reads (Get, GetAll, ...) still query the node, so they do not
+// reflect the commands pending in the batch.
+//
+// Example:
+//
+//	batch := module.Batch(node)
+//	sp := module.SwitchPort(batch.Node())
+//	for _, intf := range interfaces {
+//		sp.SetMode(intf, "trunk")
+//		sp.SetTrunkAllowedVlans(intf, "10-20")
+//	}
+//	err := batch.Flush()
+type BatchEntity struct {
+	node     *goeapi.Node
+	recorder *goeapi.Node
+	mu       sync.Mutex
+	groups   [][]string
+}
+
+// Batch factory function to initiallize a BatchEntity given a Node
+func Batch(node *goeapi.Node) *BatchEntity {
+	b := &BatchEntity{node: node}
+	b.recorder = node.RecordingNode(func(commands []string) {
+		b.Add(commands...)
+	})
+	return b
+}
+
+// Node returns the Node to build the entities recording into the batch
+func (b *BatchEntity) Node() *goeapi.Node {
+	return b.recorder
+}
+
+// Add appends commands to the batch. The commands are sent from global
+// config mode, as with goeapi.Node.Config.
+func (b *BatchEntity) Add(commands ...string) {
+	if len(commands) == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.groups = append(b.groups, append([]string(nil), commands...))
+}
+
+// Len returns the number of configuration calls recorded in the batch
+func (b *BatchEntity) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.groups)
+}
+
+// Commands returns the commands that Flush sends after entering config
+// mode. Each configuration call after the first one is preceded by "end"
+// and the configure command of the node (see
+// goeapi.Node.ConfigureCommand) so that it runs from global config mode,
+// as it would have if sent on its own.
+func (b *BatchEntity) Commands() []string {
+	return b.commands(b.node.ConfigureCommand())
+}
+
+// commands returns the commands of the batch, re-entering config mode
+// with configure between configuration calls.
+func (b *BatchEntity) commands(configure string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var commands []string
+	for idx, group := range b.groups {
+		if idx > 0 {
+			commands = append(commands, "end", configure)
+		}
+		commands = append(commands, group...)
+	}
+	return commands
+}
+
+// Reset discards the commands of the batch
+func (b *BatchEntity) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.groups = nil
+}
+
+// Flush sends the commands of the batch to the node in a single configure
+// request. The batch is emptied if the request succeeds.
+//
+// Returns:
+//
+//	error on failure. If a command is rejected by the node, the error is
+//	a *goeapi.CommandError whose CommandIndex refers to Commands().
+func (b *BatchEntity) Flush() error {
+	commands := b.Commands()
+	if len(commands) == 0 {
+		return nil
+	}
+	if err := b.node.ConfigWithErr(commands...); err != nil {
+		return err
+	}
+	b.Reset()
+	return nil
+}
+
+// FlushSession stages the commands of the batch into the configuration
+// session name in a single request and commits it, so that either all or
+// none of the commands are applied. The session is aborted on failure and
+// the batch is emptied on success.
+//
+// Args:
+//
+//	name (string): The name of the configuration session. If empty, a
+//	               unique name is generated.
+//
+// Returns:
+//
+//	error on failure. If a command is rejected by the node, the error is
+//	a *goeapi.CommandError whose CommandIndex refers to the commands of
+//	the batch, with "configure session <name>" in place of "configure
+//	terminal".
+func (b *BatchEntity) FlushSession(name string) error {
+	session := b.node.NewSession(name)
+	commands := b.commands("configure session " + session.Name())
+	if len(commands) == 0 {
+		return nil
+	}
+	if err := session.Add(commands...); err != nil {
+		session.Abort()
+		return err
+	}
+	if err := session.Commit(); err != nil {
+		session.Abort()
+		return err
+	}
+	b.Reset()
+	return nil
+}
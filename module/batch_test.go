@@ -0,0 +1,122 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package module
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBatchRecord_UnitTest(t *testing.T) {
+	batch := Batch(dummyNode)
+	dummyNode.Config("hostname before")
+
+	if !Vlan(batch.Node()).SetName("10", "foo") {
+		t.Fatal("SetName failed")
+	}
+	if !SwitchPort(batch.Node()).SetMode("Ethernet1", "trunk") {
+		t.Fatal("SetMode failed")
+	}
+	batch.Add("ip routing")
+
+	if got := dummyConnection.GetCommands(); got[len(got)-1] != "hostname before" {
+		t.Fatalf("Batched commands should not be sent, got %#v", got)
+	}
+	want := []string{
+		"vlan 10", "name foo",
+		"end", "configure terminal",
+		"interface Ethernet1", "switchport mode trunk",
+		"end", "configure terminal",
+		"ip routing",
+	}
+	if batch.Len() != 3 {
+		t.Fatalf("Expected 3 configuration calls, got %d", batch.Len())
+	}
+	if got := batch.Commands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+	batch.Reset()
+	if batch.Len() != 0 || batch.Commands() != nil {
+		t.Fatalf("Reset failed: %#v", batch.Commands())
+	}
+}
+
+func TestBatchFlush_UnitTest(t *testing.T) {
+	batch := Batch(dummyNode)
+	if err := batch.Flush(); err != nil {
+		t.Fatalf("Flush of empty batch failed: %s", err)
+	}
+	Vlan(batch.Node()).Create("20")
+	Interface(batch.Node()).SetDescription("Ethernet2", "uplink")
+	if err := batch.Flush(); err != nil {
+		t.Fatalf("Flush failed: %s", err)
+	}
+	want := []interface{}{"enable", "configure terminal", "vlan 20",
+		"end", "configure terminal", "interface Ethernet2",
+		"description uplink"}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+	if batch.Len() != 0 {
+		t.Fatal("Flush should empty the batch")
+	}
+}
+
+func TestBatchFlushError_UnitTest(t *testing.T) {
+	batch := Batch(dummyNode)
+	batch.Add("vlan 30")
+	conn := dummyNode.GetConnection().(*DummyEapiConnection)
+	conn.setReturnError(true)
+	if err := batch.Flush(); err == nil {
+		t.Fatal("Flush should fail")
+	}
+	if batch.Len() != 1 {
+		t.Fatal("Failed flush should keep the batch")
+	}
+}
+
+func TestBatchFlushSession_UnitTest(t *testing.T) {
+	batch := Batch(dummyNode)
+	batch.Add("vlan 40")
+	batch.Add("interface Ethernet3", "shutdown")
+	if err := batch.FlushSession("batch"); err != nil {
+		t.Fatalf("FlushSession failed: %s", err)
+	}
+	want := []interface{}{"enable", "configure session batch", "commit"}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+	if batch.Len() != 0 {
+		t.Fatal("FlushSession should empty the batch")
+	}
+}
@@ -33,6 +33,8 @@
 package module
 
 import (
+	"context"
+	"fmt"
 	"net"
 	"regexp"
 	"strconv"
@@ -243,135 +245,210 @@ func (b *BGPEntity) GetSection() string {
 // command. Returns true (bool) if the commands complete
 // successfully
 func (b *BGPEntity) ConfigureBgp(cmd string) bool {
+	return b.ConfigureBgpE(cmd) == nil
+}
+
+// ConfigureBgpE is the error-returning variant of ConfigureBgp
+func (b *BGPEntity) ConfigureBgpE(cmd string) error {
 	config := b.Get()
 	if config == nil {
-		return false
+		return fmt.Errorf("BGP is not configured")
 	}
 	commands := []string{
 		"router bgp " + config.BgpAs(),
 		cmd,
 	}
-	return b.Configure(commands...)
+	return b.ConfigureE(commands...)
 }
 
 // Create creates a BGP instance on the node using the given
 // AS value. Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) Create(bgpAS int) bool {
+	return b.CreateE(bgpAS) == nil
+}
+
+// CreateE is the error-returning variant of Create
+func (b *BGPEntity) CreateE(bgpAS int) error {
 	if !(0 < bgpAS && bgpAS < 65535) {
-		return false
+		return invalid("AS", bgpAS, "must be in the range 1 to 65534")
 	}
 	cmd := "router bgp " + strconv.Itoa(bgpAS)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // Delete deletes the BGP instance on the node.
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) Delete() bool {
+	return b.DeleteE() == nil
+}
+
+// DeleteE is the error-returning variant of Delete
+func (b *BGPEntity) DeleteE() error {
 	config := b.Get()
 	if config == nil {
-		return true
+		return nil
 	}
 	cmd := "no router bgp " + config.BgpAs()
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // Default sets the default config for BGP instance on the node.
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) Default() bool {
+	return b.DefaultE() == nil
+}
+
+// DefaultE is the error-returning variant of Default
+func (b *BGPEntity) DefaultE() error {
 	config := b.Get()
 	if config == nil {
-		return true
+		return nil
 	}
 	cmd := "default router bgp " + config.BgpAs()
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetRouterID configures the router-id using the provided value.
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) SetRouterID(value string) bool {
+	return b.SetRouterIDE(value) == nil
+}
+
+// SetRouterIDE is the error-returning variant of SetRouterID
+func (b *BGPEntity) SetRouterIDE(value string) error {
 	if value == "" {
-		return b.ConfigureBgp("no router-id")
+		return b.ConfigureBgpE("no router-id")
 	}
-	return b.ConfigureBgp("router-id " + value)
+	return b.ConfigureBgpE("router-id " + value)
 }
 
 // SetRouterIDDefault sets the default router-id value
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) SetRouterIDDefault() bool {
-	return b.ConfigureBgp("default router-id")
+	return b.SetRouterIDDefaultE() == nil
+}
+
+// SetRouterIDDefaultE is the error-returning variant of SetRouterIDDefault
+func (b *BGPEntity) SetRouterIDDefaultE() error {
+	return b.ConfigureBgpE("default router-id")
 }
 
 // SetMaximumPaths sets the BGP maximum path using the provided maxPath
 // value. Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) SetMaximumPaths(maxPath int) bool {
+	return b.SetMaximumPathsE(maxPath) == nil
+}
+
+// SetMaximumPathsE is the error-returning variant of SetMaximumPaths
+func (b *BGPEntity) SetMaximumPathsE(maxPath int) error {
 	command := "maximum-paths " + strconv.Itoa(maxPath)
-	return b.ConfigureBgp(command)
+	return b.ConfigureBgpE(command)
 }
 
 // SetMaximumPathsWithEcmp set the BGP maximum path / max Ecmp configuration
 // for this entity.
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) SetMaximumPathsWithEcmp(maxPath int, maxEcmp int) bool {
+	return b.SetMaximumPathsWithEcmpE(maxPath, maxEcmp) == nil
+}
+
+// SetMaximumPathsWithEcmpE is the error-returning variant of SetMaximumPathsWithEcmp
+func (b *BGPEntity) SetMaximumPathsWithEcmpE(maxPath int, maxEcmp int) error {
 	cmd := "maximum-paths " + strconv.Itoa(maxPath) + " ecmp " + strconv.Itoa(maxEcmp)
-	return b.ConfigureBgp(cmd)
+	return b.ConfigureBgpE(cmd)
 }
 
 // SetMaximumPathsDefault resets the maximum paths configuration to its
 // default values
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) SetMaximumPathsDefault() bool {
-	return b.ConfigureBgp("default maximum-paths")
+	return b.SetMaximumPathsDefaultE() == nil
+}
+
+// SetMaximumPathsDefaultE is the error-returning variant of SetMaximumPathsDefault
+func (b *BGPEntity) SetMaximumPathsDefaultE() error {
+	return b.ConfigureBgpE("default maximum-paths")
 }
 
 // SetShutdown configures this BGP entity to be 'shutdown' (true), or
 // 'no shutdown' (false)
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) SetShutdown(enable bool) bool {
+	return b.SetShutdownE(enable) == nil
+}
+
+// SetShutdownE is the error-returning variant of SetShutdown
+func (b *BGPEntity) SetShutdownE(enable bool) error {
 	cmd := b.CommandBuilder("shutdown", "", false, enable)
-	return b.ConfigureBgp(cmd)
+	return b.ConfigureBgpE(cmd)
 }
 
 // SetShutdownDefault configures the default shutdown configuration
 // for this BGPEntity
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) SetShutdownDefault() bool {
-	return b.ConfigureBgp("default shutdown")
+	return b.SetShutdownDefaultE() == nil
+}
+
+// SetShutdownDefaultE is the error-returning variant of SetShutdownDefault
+func (b *BGPEntity) SetShutdownDefaultE() error {
+	return b.ConfigureBgpE("default shutdown")
 }
 
 // AddNetworkWithRouteMap configures BGP network using supplied network
 // prefix, mask length, and route-map
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) AddNetworkWithRouteMap(prefix string, maskLen string, routeMap string) bool {
+	return b.AddNetworkWithRouteMapE(prefix, maskLen, routeMap) == nil
+}
+
+// AddNetworkWithRouteMapE is the error-returning variant of AddNetworkWithRouteMap
+func (b *BGPEntity) AddNetworkWithRouteMapE(prefix string, maskLen string, routeMap string) error {
 	command := "network " + prefix + "/" + maskLen
 	if routeMap != "" {
 		command = command + " route-map " + routeMap
 	}
-	return b.ConfigureBgp(command)
+	return b.ConfigureBgpE(command)
 }
 
 // AddNetwork configures BGP network using supplied network prefix and
 // mask length.
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) AddNetwork(prefix string, maskLen string) bool {
-	return b.AddNetworkWithRouteMap(prefix, maskLen, "")
+	return b.AddNetworkE(prefix, maskLen) == nil
+}
+
+// AddNetworkE is the error-returning variant of AddNetwork
+func (b *BGPEntity) AddNetworkE(prefix string, maskLen string) error {
+	return b.AddNetworkWithRouteMapE(prefix, maskLen, "")
 }
 
 // RemoveNetworkWithRouteMap removes the configured BGP network config
 // using the supplied network prefix, mask length, and route-map
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) RemoveNetworkWithRouteMap(prefix string, maskLen string, routeMap string) bool {
+	return b.RemoveNetworkWithRouteMapE(prefix, maskLen, routeMap) == nil
+}
+
+// RemoveNetworkWithRouteMapE is the error-returning variant of RemoveNetworkWithRouteMap
+func (b *BGPEntity) RemoveNetworkWithRouteMapE(prefix string, maskLen string, routeMap string) error {
 	command := "no network " + prefix + "/" + maskLen
 	if routeMap != "" {
 		command = command + " route-map " + routeMap
 	}
-	return b.ConfigureBgp(command)
+	return b.ConfigureBgpE(command)
 }
 
 // RemoveNetwork removes the configured BGP network config
 // using the supplied network prefix and mask length
 // Returns true(bool) if the commands complete successfully
 func (b *BGPEntity) RemoveNetwork(prefix string, maskLen string) bool {
-	return b.RemoveNetworkWithRouteMap(prefix, maskLen, "")
+	return b.RemoveNetworkE(prefix, maskLen) == nil
+}
+
+// RemoveNetworkE is the error-returning variant of RemoveNetwork
+func (b *BGPEntity) RemoveNetworkE(prefix string, maskLen string) error {
+	return b.RemoveNetworkWithRouteMapE(prefix, maskLen, "")
 }
 
 // BgpNeighborConfig represents the parsed Bgp neighbor config
@@ -546,34 +623,49 @@ func (b *BgpNeighborsEntity) parseRouteMapOut(config string, name string) string
 // Create creates a neighbor entry in the shutdown state.
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) Create(name string) bool {
-	return b.SetShutdown(name, true)
+	return b.CreateE(name) == nil
+}
+
+// CreateE is the error-returning variant of Create
+func (b *BgpNeighborsEntity) CreateE(name string) error {
+	return b.SetShutdownE(name, true)
 }
 
 // Delete removes the neighbor name(string) entry.
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) Delete(name string) bool {
-	resp := b.Configure("no neighbor " + name)
-	if !resp {
-		resp = b.Configure("no neighbor " + name + " peer-group")
+	return b.DeleteE(name) == nil
+}
+
+// DeleteE is the error-returning variant of Delete
+func (b *BgpNeighborsEntity) DeleteE(name string) error {
+	err := b.ConfigureE("no neighbor " + name)
+	if err != nil {
+		err = b.ConfigureE("no neighbor " + name + " peer-group")
 	}
-	return resp
+	return err
 }
 
 // Configure (redefined from base) Configures router bgp instance.
 // Returns true(bool) if the commands complete successfully, false if
 // configure fails or device BGP instance doesn't exsist.
 func (b *BgpNeighborsEntity) Configure(cmd string) bool {
+	return b.ConfigureE(cmd) == nil
+}
+
+// ConfigureE is the error-returning variant of Configure
+func (b *BgpNeighborsEntity) ConfigureE(cmd string) error {
 	config, _ := b.GetBlock(`^router bgp .*`)
 	re := regexp.MustCompile(`(?m)^router bgp (\d+)`)
 	match := re.FindStringSubmatch(config)
 	if match == nil {
-		return false
+		return fmt.Errorf("BGP is not configured")
 	}
 	commands := []string{
 		"router bgp " + match[1],
 		cmd,
 	}
-	return b.AbstractBaseEntity.Configure(commands...)
+	return b.AbstractBaseEntity.ConfigureE(commands...)
 }
 
 // CommandBuilder (redefined from base) Builds proper bgp neighbot
@@ -595,137 +687,217 @@ func (b *BgpNeighborsEntity) CommandBuilder(name string, cmd string,
 // SetPeerGroup sets the neighbor(string) peer-group value(string)
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetPeerGroup(name string, value string) bool {
+	return b.SetPeerGroupE(name, value) == nil
+}
+
+// SetPeerGroupE is the error-returning variant of SetPeerGroup
+func (b *BgpNeighborsEntity) SetPeerGroupE(name string, value string) error {
 	if net.ParseIP(name) == nil {
-		return false
+		return invalid("neighbor", name, "must be an IP address")
 	}
 	cmd := b.CommandBuilder(name, "peer-group", value, false, true)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetPeerGroupDefault sets the default configuration value for neighbor
 // peer-group configuration
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetPeerGroupDefault(name string) bool {
+	return b.SetPeerGroupDefaultE(name) == nil
+}
+
+// SetPeerGroupDefaultE is the error-returning variant of SetPeerGroupDefault
+func (b *BgpNeighborsEntity) SetPeerGroupDefaultE(name string) error {
 	if net.ParseIP(name) == nil {
-		return false
+		return invalid("neighbor", name, "must be an IP address")
 	}
 	cmd := b.CommandBuilder(name, "peer-group", "", true, false)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetRemoteAS sets the neighbor name(string) remote-as configuration to
 // value(string)
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetRemoteAS(name string, value string) bool {
+	return b.SetRemoteASE(name, value) == nil
+}
+
+// SetRemoteASE is the error-returning variant of SetRemoteAS
+func (b *BgpNeighborsEntity) SetRemoteASE(name string, value string) error {
 	cmd := b.CommandBuilder(name, "remote-as", value, false, true)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetRemoteASDefault sets the default configuration value for the neighbor
 // remote-as configuration
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetRemoteASDefault(name string) bool {
+	return b.SetRemoteASDefaultE(name) == nil
+}
+
+// SetRemoteASDefaultE is the error-returning variant of SetRemoteASDefault
+func (b *BgpNeighborsEntity) SetRemoteASDefaultE(name string) error {
 	cmd := b.CommandBuilder(name, "remote-as", "", true, false)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetShutdown set the neighbor name(string) shutdown state to
 //	shut(boo) - true:shutdown,    false:no shutdown
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetShutdown(name string, shut bool) bool {
+	return b.SetShutdownE(name, shut) == nil
+}
+
+// SetShutdownE is the error-returning variant of SetShutdown
+func (b *BgpNeighborsEntity) SetShutdownE(name string, shut bool) error {
 	cmd := b.CommandBuilder(name, "shutdown", "", false, shut)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetShutdownDefault sets the default configuration value for the neighbor
 // shutdown configuration
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetShutdownDefault(name string) bool {
+	return b.SetShutdownDefaultE(name) == nil
+}
+
+// SetShutdownDefaultE is the error-returning variant of SetShutdownDefault
+func (b *BgpNeighborsEntity) SetShutdownDefaultE(name string) error {
 	cmd := b.CommandBuilder(name, "shutdown", "", true, false)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetSendCommunity sets the neighbor name(string) send-community configuration to
 // value(string).
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetSendCommunity(name string, enable bool) bool {
+	return b.SetSendCommunityE(name, enable) == nil
+}
+
+// SetSendCommunityE is the error-returning variant of SetSendCommunity
+func (b *BgpNeighborsEntity) SetSendCommunityE(name string, enable bool) error {
 	cmd := b.CommandBuilder(name, "send-community", "", false, enable)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetSendCommunityDefault sets the default configuration value for the neighbor
 // send-community configuration
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetSendCommunityDefault(name string) bool {
+	return b.SetSendCommunityDefaultE(name) == nil
+}
+
+// SetSendCommunityDefaultE is the error-returning variant of SetSendCommunityDefault
+func (b *BgpNeighborsEntity) SetSendCommunityDefaultE(name string) error {
 	cmd := b.CommandBuilder(name, "send-community", "", true, false)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetNextHopSelf sets the neighbor name(string) next-hop-self to enabled(true)
 // or disabled(false)
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetNextHopSelf(name string, enabled bool) bool {
+	return b.SetNextHopSelfE(name, enabled) == nil
+}
+
+// SetNextHopSelfE is the error-returning variant of SetNextHopSelf
+func (b *BgpNeighborsEntity) SetNextHopSelfE(name string, enabled bool) error {
 	cmd := b.CommandBuilder(name, "next-hop-self", "", false, enabled)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetNextHopSelfDefault sets the default configuration value for the neighbor
 // next-hop-self configuration
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetNextHopSelfDefault(name string) bool {
+	return b.SetNextHopSelfDefaultE(name) == nil
+}
+
+// SetNextHopSelfDefaultE is the error-returning variant of SetNextHopSelfDefault
+func (b *BgpNeighborsEntity) SetNextHopSelfDefaultE(name string) error {
 	cmd := b.CommandBuilder(name, "next-hop-self", "", true, false)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetRouteMapIn sets the neighbor name(string) inbound route-map entry using
 // value(string)
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetRouteMapIn(name string, value string) bool {
+	return b.SetRouteMapInE(name, value) == nil
+}
+
+// SetRouteMapInE is the error-returning variant of SetRouteMapIn
+func (b *BgpNeighborsEntity) SetRouteMapInE(name string, value string) error {
 	cmd := b.CommandBuilder(name, "route-map", value, false, true)
 	cmd = cmd + " in"
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetRouteMapInDefault sets the default configuration value for the neighbor
 // inbound route-map configuration
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetRouteMapInDefault(name string) bool {
+	return b.SetRouteMapInDefaultE(name) == nil
+}
+
+// SetRouteMapInDefaultE is the error-returning variant of SetRouteMapInDefault
+func (b *BgpNeighborsEntity) SetRouteMapInDefaultE(name string) error {
 	cmd := b.CommandBuilder(name, "route-map", "", true, false)
 	cmd = cmd + " in"
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetRouteMapOut sets the neighbor name(string) outbound route-map entry using
 // value(string)
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetRouteMapOut(name string, value string) bool {
+	return b.SetRouteMapOutE(name, value) == nil
+}
+
+// SetRouteMapOutE is the error-returning variant of SetRouteMapOut
+func (b *BgpNeighborsEntity) SetRouteMapOutE(name string, value string) error {
 	cmd := b.CommandBuilder(name, "route-map", value, false, true)
 	cmd = cmd + " out"
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetRouteMapOutDefault sets the default configuration value for the neighbor
 // outbound route-map configuration
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetRouteMapOutDefault(name string) bool {
+	return b.SetRouteMapOutDefaultE(name) == nil
+}
+
+// SetRouteMapOutDefaultE is the error-returning variant of SetRouteMapOutDefault
+func (b *BgpNeighborsEntity) SetRouteMapOutDefaultE(name string) error {
 	cmd := b.CommandBuilder(name, "route-map", "", true, false)
 	cmd = cmd + " out"
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetDescription sets the neighbor name(string) using the provided value(string)
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetDescription(name string, value string) bool {
+	return b.SetDescriptionE(name, value) == nil
+}
+
+// SetDescriptionE is the error-returning variant of SetDescription
+func (b *BgpNeighborsEntity) SetDescriptionE(name string, value string) error {
 	cmd := b.CommandBuilder(name, "description", value, false, true)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 // SetDescriptionDefault sets the default configuration value for the neighbor
 // description configuration
 // Returns true(bool) if the commands complete successfully
 func (b *BgpNeighborsEntity) SetDescriptionDefault(name string) bool {
+	return b.SetDescriptionDefaultE(name) == nil
+}
+
+// SetDescriptionDefaultE is the error-returning variant of SetDescriptionDefault
+func (b *BgpNeighborsEntity) SetDescriptionDefaultE(name string) error {
 	cmd := b.CommandBuilder(name, "description", "", true, false)
-	return b.Configure(cmd)
+	return b.ConfigureE(cmd)
 }
 
 type ShowIPBGPSummary struct {
@@ -759,14 +931,5 @@ func (b *ShowIPBGPSummary) GetCmd() string {
 }
 
 func (s *ShowEntity) ShowIPBGPSummary() (ShowIPBGPSummary, error) {
-	handle, _ := s.node.GetHandle("json")
-	var showipbgpsummary ShowIPBGPSummary
-	handle.AddCommand(&showipbgpsummary)
-
-	if err := handle.Call(); err != nil {
-		return showipbgpsummary, err
-	}
-
-	handle.Close()
-	return showipbgpsummary, nil
+	return goeapi.Run[ShowIPBGPSummary](context.Background(), s.node)
 }
@@ -0,0 +1,102 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package module
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aristanetworks/goeapi"
+)
+
+// benchServer returns a server answering every request with the response
+// to enable and the command of the fixture file.
+func benchServer(b *testing.B, file string) *httptest.Server {
+	data, err := ioutil.ReadFile(GetFixture(file))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var results []json.RawMessage
+	if json.Unmarshal(data, &results) == nil {
+		data = []byte(fmt.Sprintf(`{"jsonrpc": "2.0", "id": "1", "result": [{}, %s]}`,
+			results[len(results)-1]))
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	b.Cleanup(srv.Close)
+	return srv
+}
+
+func benchDecode(b *testing.B, file string, v goeapi.EapiCommand, direct bool) {
+	srv := benchServer(b, file)
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	node := &goeapi.Node{}
+	node.SetConnection(goeapi.NewHTTPEapiConnection("http", addr.IP.String(),
+		"admin", "", addr.Port))
+	handle, err := node.GetHandle("json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	handle.SetDirectDecode(direct)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		handle.AddCommand(v)
+		if err := handle.Call(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeShowIPRoute(b *testing.B) {
+	benchDecode(b, "show_ip_route.json", new(ShowIPRoute), false)
+}
+
+func BenchmarkDecodeShowIPRouteDirect(b *testing.B) {
+	benchDecode(b, "show_ip_route.json", new(ShowIPRoute), true)
+}
+
+func BenchmarkDecodeShowInterfaces(b *testing.B) {
+	benchDecode(b, "show_interfaces.json", new(ShowInterface), false)
+}
+
+func BenchmarkDecodeShowInterfacesDirect(b *testing.B) {
+	benchDecode(b, "show_interfaces.json", new(ShowInterface), true)
+}
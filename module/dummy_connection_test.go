@@ -1,6 +1,7 @@
 package module
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,6 +16,14 @@ type DummyConnection struct {
 	err error
 }
 
+func (conn *DummyConnection) ExecuteContext(ctx context.Context,
+	commands []interface{}, encoding string) (*goeapi.JSONRPCResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return &goeapi.JSONRPCResponse{}, err
+	}
+	return conn.Execute(commands, encoding)
+}
+
 func (conn *DummyConnection) Execute(commands []interface{},
 	encoding string) (*goeapi.JSONRPCResponse, error) {
 
@@ -28,7 +37,11 @@ func (conn *DummyConnection) Execute(commands []interface{},
 	}
 	// command 0: enable
 	// command 1: show lldp neighbors
-	cmd := commands[1].(string)
+	cmd, ok := commands[1].(string)
+	if !ok {
+		// command pinned to a model revision
+		cmd = commands[1].(map[string]interface{})["cmd"].(string)
+	}
 	fixtureName := strings.Replace(cmd, " ", "_", -1) + ".json"
 	// cmd: 'show lldp neighbors' will cause us to look for
 	// fixture 'show_lldp_neighbors.json'
@@ -32,6 +32,12 @@
 
 package module
 
+import (
+	"context"
+
+	"github.com/aristanetworks/goeapi"
+)
+
 type ShowEnvironmentPower struct {
 	PowerSupplies map[string]struct {
 		OutputPower  float64 `json:"outputPower"`
@@ -58,14 +64,5 @@ func (b *ShowEnvironmentPower) GetCmd() string {
 }
 
 func (s *ShowEntity) ShowEnvironmentPower() (ShowEnvironmentPower, error) {
-	handle, _ := s.node.GetHandle("json")
-	var showenvironmentpower ShowEnvironmentPower
-	handle.AddCommand(&showenvironmentpower)
-
-	if err := handle.Call(); err != nil {
-		return showenvironmentpower, err
-	}
-
-	handle.Close()
-	return showenvironmentpower, nil
+	return goeapi.Run[ShowEnvironmentPower](context.Background(), s.node)
 }
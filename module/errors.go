@@ -0,0 +1,72 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package module
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidationError is returned by the error-returning (...E) setters when
+// an argument is rejected before any command is sent to the node. The
+// setters returning a bool keep the checks they have always made and
+// otherwise send their arguments to the node unchecked.
+// Failures reported by the node are returned as is, usually as a
+// *goeapi.CommandError.
+type ValidationError struct {
+	Arg    string
+	Value  interface{}
+	Reason string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid %s %q: %s", e.Arg, fmt.Sprint(e.Value),
+		e.Reason)
+}
+
+// invalid returns a ValidationError for the argument arg
+func invalid(arg string, value interface{}, reason string) error {
+	return &ValidationError{Arg: arg, Value: value, Reason: reason}
+}
+
+// validationResult converts the error of an ...E setter to the result of
+// the setters returning (bool, error), which only report validation
+// errors.
+func validationResult(err error) (bool, error) {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return false, err
+	}
+	return err == nil, nil
+}
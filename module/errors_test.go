@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package module
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidationErrors_UnitTest(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		arg  string
+	}{
+		{"Vlan.CreateE", Vlan(dummyNode).CreateE("4095"), "vlan"},
+		{"Vlan.DeleteE", Vlan(dummyNode).DeleteE("0"), "vlan"},
+		{"Vlan.SetNameE", Vlan(dummyNode).SetNameE("abc", "foo"), "vlan"},
+		{"SwitchPort.SetAccessVlanE",
+			SwitchPort(dummyNode).SetAccessVlanE("Ethernet1", "5000"), "vlan"},
+		{"SwitchPort.SetTrunkAllowedVlansE",
+			SwitchPort(dummyNode).SetTrunkAllowedVlansE("Ethernet1", "1-5000"),
+			"vlans"},
+		{"SwitchPort.SetTrunkAllowedVlansE",
+			SwitchPort(dummyNode).SetTrunkAllowedVlansE("Ethernet1", ""), "vlans"},
+		{"Vlan.ConfigureVlanE", Vlan(dummyNode).ConfigureVlanE("", "name foo"),
+			"vlan"},
+		{"IPInterface.SetMtuE", IPInterface(dummyNode).SetMtuE("Ethernet1", 10),
+			"MTU"},
+		{"BGP.CreateE", Bgp(dummyNode).CreateE(0), "AS"},
+		{"STP.SetModeE", Stp(dummyNode).SetModeE("rstp"), "spanning-tree mode"},
+		{"User.SetPrivilegeE", User(dummyNode).SetPrivilegeE("test", 16),
+			"privilege"},
+	}
+	for _, tt := range tests {
+		var verr *ValidationError
+		if !errors.As(tt.err, &verr) {
+			t.Fatalf("%s: expected *ValidationError, got %#v", tt.name, tt.err)
+		}
+		if verr.Arg != tt.arg {
+			t.Fatalf("%s: expected argument %q, got %q", tt.name, tt.arg,
+				verr.Arg)
+		}
+	}
+
+	err := Vlan(dummyNode).CreateE("4095")
+	want := `Invalid vlan "4095": must be in the range 1 to 4094`
+	if err.Error() != want {
+		t.Fatalf("Expected %q, got %q", want, err)
+	}
+	if Vlan(dummyNode).Create("4095") {
+		t.Fatal("Create should fail for an invalid vlan")
+	}
+	if !Vlan(dummyNode).ConfigureVlan("4095", "name foo") {
+		t.Fatal("ConfigureVlan should send the vlan unchecked")
+	}
+	if !Vlan(dummyNode).SetName("abc", "foo") || !Vlan(dummyNode).SetState("abc", "active") {
+		t.Fatal("Vlan setters should send the vlan unchecked")
+	}
+	if !SwitchPort(dummyNode).SetAccessVlan("Ethernet1", "5000") ||
+		!SwitchPort(dummyNode).SetTrunkNativeVlan("Ethernet1", "5000") {
+		t.Fatal("SwitchPort setters should send the vlan unchecked")
+	}
+}
+
+func TestSetterErrors_UnitTest(t *testing.T) {
+	if err := Vlan(dummyNode).CreateE("10"); err != nil {
+		t.Fatalf("CreateE failed: %s", err)
+	}
+	conn := dummyNode.GetConnection().(*DummyEapiConnection)
+	conn.setReturnError(true)
+	err := SwitchPort(dummyNode).SetModeE("Ethernet1", "trunk")
+	var verr *ValidationError
+	if err == nil || errors.As(err, &verr) {
+		t.Fatalf("Expected the connection error, got %#v", err)
+	}
+
+	err = EthernetInterface(dummyNode).CreateE("Ethernet1")
+	if !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("Expected ErrUnsupported, got %#v", err)
+	}
+}
+
+func TestValidationResult_UnitTest(t *testing.T) {
+	ok, err := User(dummyNode).SetPrivilege("test", 16)
+	if ok || err == nil {
+		t.Fatalf("SetPrivilege should return the validation error")
+	}
+	ok, err = User(dummyNode).SetPrivilege("test", 1)
+	if !ok || err != nil {
+		t.Fatalf("SetPrivilege failed: %v", err)
+	}
+	conn := dummyNode.GetConnection().(*DummyEapiConnection)
+	conn.setReturnError(true)
+	ok, err = User(dummyNode).SetPrivilege("test", 1)
+	if ok || err != nil {
+		t.Fatalf("SetPrivilege should only report validation errors: %v", err)
+	}
+}
+
+func TestShowErrors_UnitTest(t *testing.T) {
+	conn := dummyNode.GetConnection().(*DummyEapiConnection)
+	conn.setReturnError(true)
+	if _, err := Show(dummyNode).ShowVersionE(); err == nil {
+		t.Fatal("ShowVersionE should return the connection error")
+	}
+}
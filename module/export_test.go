@@ -32,6 +32,7 @@
 package module
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -136,6 +137,14 @@ func NewDummyEapiConnection(transport string, host string, username string,
 	return &DummyEapiConnection{EapiConnection: conn, retError: false}
 }
 
+func (conn *DummyEapiConnection) ExecuteContext(ctx context.Context,
+	commands []interface{}, encoding string) (*goeapi.JSONRPCResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return &goeapi.JSONRPCResponse{}, err
+	}
+	return conn.Execute(commands, encoding)
+}
+
 func (conn *DummyEapiConnection) Execute(commands []interface{},
 	encoding string) (*goeapi.JSONRPCResponse, error) {
 	if conn.retError {
@@ -33,6 +33,8 @@
 package module
 
 import (
+	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -113,42 +115,77 @@ func (i *BaseInterfaceEntity) parseDescription(config string) string {
 
 // Create creates a new interface on the node
 func (i *BaseInterfaceEntity) Create(name string) bool {
-	return i.Configure("interface " + name)
+	return i.CreateE(name) == nil
+}
+
+// CreateE is the error-returning variant of Create
+func (i *BaseInterfaceEntity) CreateE(name string) error {
+	return i.ConfigureE("interface " + name)
 }
 
 // Delete removes an interface from the node
 func (i *BaseInterfaceEntity) Delete(name string) bool {
-	return i.Configure("no interface " + name)
+	return i.DeleteE(name) == nil
+}
+
+// DeleteE is the error-returning variant of Delete
+func (i *BaseInterfaceEntity) DeleteE(name string) error {
+	return i.ConfigureE("no interface " + name)
 }
 
 // Default reverts back to default config for interface
 func (i *BaseInterfaceEntity) Default(name string) bool {
-	return i.Configure("default interface " + name)
+	return i.DefaultE(name) == nil
+}
+
+// DefaultE is the error-returning variant of Default
+func (i *BaseInterfaceEntity) DefaultE(name string) error {
+	return i.ConfigureE("default interface " + name)
 }
 
 // SetDescription sets the description on the interface name(sting) to value(string)
 func (i *BaseInterfaceEntity) SetDescription(name string, value string) bool {
+	return i.SetDescriptionE(name, value) == nil
+}
+
+// SetDescriptionE is the error-returning variant of SetDescription
+func (i *BaseInterfaceEntity) SetDescriptionE(name string, value string) error {
 	cmd := i.CommandBuilder("description", value, false, true)
-	return i.ConfigureInterface(name, cmd)
+	return i.ConfigureInterfaceE(name, cmd)
 }
 
 // SetDescriptionDefault reverts back to the default description value
 func (i *BaseInterfaceEntity) SetDescriptionDefault(name string) bool {
+	return i.SetDescriptionDefaultE(name) == nil
+}
+
+// SetDescriptionDefaultE is the error-returning variant of SetDescriptionDefault
+func (i *BaseInterfaceEntity) SetDescriptionDefaultE(name string) error {
 	cmd := i.CommandBuilder("description", "", true, false)
-	return i.ConfigureInterface(name, cmd)
+	return i.ConfigureInterfaceE(name, cmd)
 }
 
 // SetShutdown sets the interface name(string) to shutdown(true)
 // or no-shutdown(false)
 func (i *BaseInterfaceEntity) SetShutdown(name string, shut bool) bool {
+	return i.SetShutdownE(name, shut) == nil
+}
+
+// SetShutdownE is the error-returning variant of SetShutdown
+func (i *BaseInterfaceEntity) SetShutdownE(name string, shut bool) error {
 	cmd := i.CommandBuilder("shutdown", "", false, shut)
-	return i.ConfigureInterface(name, cmd)
+	return i.ConfigureInterfaceE(name, cmd)
 }
 
 // SetShutdownDefault reverts back to the default shutdown config for interface
 func (i *BaseInterfaceEntity) SetShutdownDefault(name string) bool {
+	return i.SetShutdownDefaultE(name) == nil
+}
+
+// SetShutdownDefaultE is the error-returning variant of SetShutdownDefault
+func (i *BaseInterfaceEntity) SetShutdownDefaultE(name string) error {
 	cmd := i.CommandBuilder("shutdown", "", true, false)
-	return i.ConfigureInterface(name, cmd)
+	return i.ConfigureInterfaceE(name, cmd)
 }
 
 ///////////////////////////////
@@ -209,26 +246,46 @@ func parseFlowControlReceive(config string) string {
 
 // Create not supported
 func (e *EthernetInterfaceEntity) Create(name string) bool {
-	return false // not implemented
+	return e.CreateE(name) == nil
+}
+
+// CreateE is the error-returning variant of Create
+func (e *EthernetInterfaceEntity) CreateE(name string) error {
+	return fmt.Errorf("Create %s: %w", name, errors.ErrUnsupported)
 }
 
 // Delete not supported
 func (e *EthernetInterfaceEntity) Delete(name string) bool {
-	return false // not implemented
+	return e.DeleteE(name) == nil
+}
+
+// DeleteE is the error-returning variant of Delete
+func (e *EthernetInterfaceEntity) DeleteE(name string) error {
+	return fmt.Errorf("Delete %s: %w", name, errors.ErrUnsupported)
 }
 
 // SetFlowcontrolSend configures the interface flowcontrol send value(true: on)
 func (e *EthernetInterfaceEntity) SetFlowcontrolSend(name string, value bool) bool {
+	return e.SetFlowcontrolSendE(name, value) == nil
+}
+
+// SetFlowcontrolSendE is the error-returning variant of SetFlowcontrolSend
+func (e *EthernetInterfaceEntity) SetFlowcontrolSendE(name string, value bool) error {
 	return e.setFlowcontrol(name, "send", value)
 }
 
 // SetFlowcontrolReceive configures the interface flowcontrol receive value(true: on)
 func (e *EthernetInterfaceEntity) SetFlowcontrolReceive(name string, value bool) bool {
+	return e.SetFlowcontrolReceiveE(name, value) == nil
+}
+
+// SetFlowcontrolReceiveE is the error-returning variant of SetFlowcontrolReceive
+func (e *EthernetInterfaceEntity) SetFlowcontrolReceiveE(name string, value bool) error {
 	return e.setFlowcontrol(name, "receive", value)
 }
 
 // setFlowcontrol configures the interface flowcontrol value
-func (e *EthernetInterfaceEntity) setFlowcontrol(name string, direction string, value bool) bool {
+func (e *EthernetInterfaceEntity) setFlowcontrol(name string, direction string, value bool) error {
 	var str string
 	if value {
 		str = "flowcontrol " + direction + " on"
@@ -239,31 +296,46 @@ func (e *EthernetInterfaceEntity) setFlowcontrol(name string, direction string,
 		"interface " + name,
 		str,
 	}
-	return e.Configure(cmds...)
+	return e.ConfigureE(cmds...)
 }
 
 // DisableFlowcontrolSend disables the interface flowcontrol send value
 func (e *EthernetInterfaceEntity) DisableFlowcontrolSend(name string) bool {
+	return e.DisableFlowcontrolSendE(name) == nil
+}
+
+// DisableFlowcontrolSendE is the error-returning variant of DisableFlowcontrolSend
+func (e *EthernetInterfaceEntity) DisableFlowcontrolSendE(name string) error {
 	return e.disableFlowcontrol(name, "send")
 }
 
 // DisableFlowcontrolReceive disables the interface flowcontrol receive value
 func (e *EthernetInterfaceEntity) DisableFlowcontrolReceive(name string) bool {
+	return e.DisableFlowcontrolReceiveE(name) == nil
+}
+
+// DisableFlowcontrolReceiveE is the error-returning variant of DisableFlowcontrolReceive
+func (e *EthernetInterfaceEntity) DisableFlowcontrolReceiveE(name string) error {
 	return e.disableFlowcontrol(name, "receive")
 }
 
 // DisableFlowcontrol disables the interface flowcontrol
-func (e *EthernetInterfaceEntity) disableFlowcontrol(name string, direction string) bool {
+func (e *EthernetInterfaceEntity) disableFlowcontrol(name string, direction string) error {
 	cmds := []string{
 		"interface " + name,
 		"no flowcontrol " + direction,
 	}
-	return e.Configure(cmds...)
+	return e.ConfigureE(cmds...)
 }
 
 // SetSflow configures the sFlow state (true:enable, false:disable) on the
 // interface name(string)
 func (e *EthernetInterfaceEntity) SetSflow(name string, value bool) bool {
+	return e.SetSflowE(name, value) == nil
+}
+
+// SetSflowE is the error-returning variant of SetSflow
+func (e *EthernetInterfaceEntity) SetSflowE(name string, value bool) error {
 	str := "no sflow enable"
 	if value {
 		str = "sflow enable"
@@ -272,17 +344,22 @@ func (e *EthernetInterfaceEntity) SetSflow(name string, value bool) bool {
 		"interface " + name,
 		str,
 	}
-	return e.Configure(cmds...)
+	return e.ConfigureE(cmds...)
 }
 
 // SetSflowDefault configures the defalt sFlow state on the
 // interface name(string)
 func (e *EthernetInterfaceEntity) SetSflowDefault(name string) bool {
+	return e.SetSflowDefaultE(name) == nil
+}
+
+// SetSflowDefaultE is the error-returning variant of SetSflowDefault
+func (e *EthernetInterfaceEntity) SetSflowDefaultE(name string) error {
 	cmds := []string{
 		"interface " + name,
 		"default sflow",
 	}
-	return e.Configure(cmds...)
+	return e.ConfigureE(cmds...)
 }
 
 ///////////////////////////////
@@ -367,10 +444,15 @@ func (p *PortChannelInterfaceEntity) getMembers(name string) []string {
 
 // SetMembers configures the array of member interfaces for the Port-Channel
 func (p *PortChannelInterfaceEntity) SetMembers(name string, members ...string) bool {
+	return p.SetMembersE(name, members...) == nil
+}
+
+// SetMembersE is the error-returning variant of SetMembers
+func (p *PortChannelInterfaceEntity) SetMembersE(name string, members ...string) error {
 	re := regexp.MustCompile(`(\d+)$`)
 	match := re.FindStringSubmatch(name)
 	if match == nil {
-		return false
+		return invalid("Port-Channel", name, "must end with a number")
 	}
 	grpID := match[1]
 	currentMembers := p.getMembers(name)
@@ -391,23 +473,28 @@ func (p *PortChannelInterfaceEntity) SetMembers(name string, members ...string)
 		commands = append(commands, "interface "+member)
 		commands = append(commands, "channel-group "+grpID+" mode "+lacpMode)
 	}
-	return p.Configure(commands...)
+	return p.ConfigureE(commands...)
 }
 
 // SetLacpMode configures the LACP mode of the member interfaces
 func (p *PortChannelInterfaceEntity) SetLacpMode(name string, mode string) bool {
+	return p.SetLacpModeE(name, mode) == nil
+}
+
+// SetLacpModeE is the error-returning variant of SetLacpMode
+func (p *PortChannelInterfaceEntity) SetLacpModeE(name string, mode string) error {
 	validModes := map[string]bool{
 		"on":      true,
 		"passive": true,
 		"active":  true,
 	}
 	if _, found := validModes[mode]; !found {
-		return false
+		return invalid("LACP mode", mode, "must be on, passive or active")
 	}
 	re := regexp.MustCompile(`(\d+)$`)
 	match := re.FindStringSubmatch(name)
 	if match == nil {
-		return false
+		return invalid("Port-Channel", name, "must end with a number")
 	}
 	grpID := match[1]
 
@@ -419,30 +506,40 @@ func (p *PortChannelInterfaceEntity) SetLacpMode(name string, mode string) bool
 		addCommands = append(addCommands, "interface "+member)
 		addCommands = append(addCommands, "channel-group "+grpID+" mode "+mode)
 	}
-	return p.Configure(append(removeCommands, addCommands...)...)
+	return p.ConfigureE(append(removeCommands, addCommands...)...)
 }
 
 // SetMinimumLinks configures the Port-Channel min-links value
 func (p *PortChannelInterfaceEntity) SetMinimumLinks(name string, value int) bool {
+	return p.SetMinimumLinksE(name, value) == nil
+}
+
+// SetMinimumLinksE is the error-returning variant of SetMinimumLinks
+func (p *PortChannelInterfaceEntity) SetMinimumLinksE(name string, value int) error {
 	if value < 1 || value > 16 {
-		return false
+		return invalid("min-links", value, "must be in the range 1 to 16")
 	}
 	cmd := "port-channel min-links " + strconv.Itoa(value)
 	commands := []string{
 		"interface " + name,
 		cmd,
 	}
-	return p.Configure(commands...)
+	return p.ConfigureE(commands...)
 }
 
 // SetMinimumLinksDefault returns the specified interface min-links config to it's
 // default configuration.
 func (p *PortChannelInterfaceEntity) SetMinimumLinksDefault(name string) bool {
+	return p.SetMinimumLinksDefaultE(name) == nil
+}
+
+// SetMinimumLinksDefaultE is the error-returning variant of SetMinimumLinksDefault
+func (p *PortChannelInterfaceEntity) SetMinimumLinksDefaultE(name string) error {
 	commands := []string{
 		"interface " + name,
 		"default port-channel min-links",
 	}
-	return p.Configure(commands...)
+	return p.ConfigureE(commands...)
 }
 
 // VxlanInterfaceConfig represents the parsed Vxlan interface config
@@ -579,94 +676,154 @@ func (v *VxlanInterfaceEntity) parseFloodList(config string) string {
 // SetSourceInterface sets the vxlan interface to the given value(string).
 // If empty string is specified, then default setting is used.
 func (v *VxlanInterfaceEntity) SetSourceInterface(name string, value string) bool {
+	return v.SetSourceInterfaceE(name, value) == nil
+}
+
+// SetSourceInterfaceE is the error-returning variant of SetSourceInterface
+func (v *VxlanInterfaceEntity) SetSourceInterfaceE(name string, value string) error {
 	var cmd string
 	if value == "" {
 		cmd = v.CommandBuilder("vxlan source-interface", value, false, false)
 	} else {
 		cmd = v.CommandBuilder("vxlan source-interface", value, false, true)
 	}
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
 
 // SetSourceInterfaceDefault sets the vxlan interface source-interface back to
 // default settings
 func (v *VxlanInterfaceEntity) SetSourceInterfaceDefault(name string) bool {
+	return v.SetSourceInterfaceDefaultE(name) == nil
+}
+
+// SetSourceInterfaceDefaultE is the error-returning variant of SetSourceInterfaceDefault
+func (v *VxlanInterfaceEntity) SetSourceInterfaceDefaultE(name string) error {
 	cmd := v.CommandBuilder("vxlan source-interface", "", true, false)
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
 
 // SetMulticastGroup sets the vxlan interface multicast-group configuration to the
 // value specified.
 // If empty string is specified, then default setting is used.
 func (v *VxlanInterfaceEntity) SetMulticastGroup(name string, value string) bool {
+	return v.SetMulticastGroupE(name, value) == nil
+}
+
+// SetMulticastGroupE is the error-returning variant of SetMulticastGroup
+func (v *VxlanInterfaceEntity) SetMulticastGroupE(name string, value string) error {
 	var cmd string
 	if value == "" {
 		cmd = v.CommandBuilder("vxlan multicast-group", value, false, false)
 	} else {
 		cmd = v.CommandBuilder("vxlan multicast-group", value, false, true)
 	}
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
 
 // SetMulticastGroupDefault sets the vxlan interface multicast-group configuration
 // back to default settings.
 func (v *VxlanInterfaceEntity) SetMulticastGroupDefault(name string) bool {
+	return v.SetMulticastGroupDefaultE(name) == nil
+}
+
+// SetMulticastGroupDefaultE is the error-returning variant of SetMulticastGroupDefault
+func (v *VxlanInterfaceEntity) SetMulticastGroupDefaultE(name string) error {
 	cmd := v.CommandBuilder("vxlan multicast-group", "", true, false)
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
 
 // SetUDPPort sets the vxlan interface udp port to the provided port value(int)
 func (v *VxlanInterfaceEntity) SetUDPPort(name string, value int) bool {
+	return v.SetUDPPortE(name, value) == nil
+}
+
+// SetUDPPortE is the error-returning variant of SetUDPPort
+func (v *VxlanInterfaceEntity) SetUDPPortE(name string, value int) error {
 	if value < 1024 || value > 65535 {
-		return v.SetUDPPortDefault(name)
+		return v.SetUDPPortDefaultE(name)
 	}
 	cmd := v.CommandBuilder("vxlan udp-port", strconv.Itoa(value), false, true)
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
 
 // SetUDPPortDefault sets the vxlan interface udp port configuration to the default
 // settings
 func (v *VxlanInterfaceEntity) SetUDPPortDefault(name string) bool {
+	return v.SetUDPPortDefaultE(name) == nil
+}
+
+// SetUDPPortDefaultE is the error-returning variant of SetUDPPortDefault
+func (v *VxlanInterfaceEntity) SetUDPPortDefaultE(name string) error {
 	cmd := v.CommandBuilder("vxlan udp-port", "", true, false)
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
 
 // AddVtepGlobalFlood adds to interface name(string) a vtep(string) endpoint with the
 // global flood list
 func (v *VxlanInterfaceEntity) AddVtepGlobalFlood(name string, vtep string) bool {
+	return v.AddVtepGlobalFloodE(name, vtep) == nil
+}
+
+// AddVtepGlobalFloodE is the error-returning variant of AddVtepGlobalFlood
+func (v *VxlanInterfaceEntity) AddVtepGlobalFloodE(name string, vtep string) error {
 	cmd := "vxlan flood vtep add " + vtep
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
 
 // AddVtepLocalFlood adds to interface name(string) a vtep(string) endpoint with the
 // local vlan(int) flood list
 func (v *VxlanInterfaceEntity) AddVtepLocalFlood(name string, vtep string, vlan int) bool {
+	return v.AddVtepLocalFloodE(name, vtep, vlan) == nil
+}
+
+// AddVtepLocalFloodE is the error-returning variant of AddVtepLocalFlood
+func (v *VxlanInterfaceEntity) AddVtepLocalFloodE(name string, vtep string, vlan int) error {
 	cmd := "vxlan vlan " + strconv.Itoa(vlan) + " flood vtep add " + vtep
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
 
 // RemoveVtepGlobalFlood removes from interface name(string) a global vtep(string) flood list
 func (v *VxlanInterfaceEntity) RemoveVtepGlobalFlood(name string, vtep string) bool {
+	return v.RemoveVtepGlobalFloodE(name, vtep) == nil
+}
+
+// RemoveVtepGlobalFloodE is the error-returning variant of RemoveVtepGlobalFlood
+func (v *VxlanInterfaceEntity) RemoveVtepGlobalFloodE(name string, vtep string) error {
 	cmd := "vxlan flood vtep remove " + vtep
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
 
 // RemoveVtepLocalFlood removes from interface name(string) a vtep(string) endpoint from the
 // local vlan(int) flood list
 func (v *VxlanInterfaceEntity) RemoveVtepLocalFlood(name string, vtep string, vlan int) bool {
+	return v.RemoveVtepLocalFloodE(name, vtep, vlan) == nil
+}
+
+// RemoveVtepLocalFloodE is the error-returning variant of RemoveVtepLocalFlood
+func (v *VxlanInterfaceEntity) RemoveVtepLocalFloodE(name string, vtep string, vlan int) error {
 	cmd := "vxlan vlan " + strconv.Itoa(vlan) + " flood vtep remove " + vtep
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
 
 // UpdateVlan adds a new vlan vid(int) to vni(int) for the interface name(string)
 func (v *VxlanInterfaceEntity) UpdateVlan(name string, vid int, vni int) bool {
+	return v.UpdateVlanE(name, vid, vni) == nil
+}
+
+// UpdateVlanE is the error-returning variant of UpdateVlan
+func (v *VxlanInterfaceEntity) UpdateVlanE(name string, vid int, vni int) error {
 	cmd := "vxlan vlan " + strconv.Itoa(vid) + " vni " + strconv.Itoa(vni)
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
 
 // RemoveVlan removes a vlan vid(int) to vni mapping from a given
 // interface name(string).
 func (v *VxlanInterfaceEntity) RemoveVlan(name string, vid int) bool {
+	return v.RemoveVlanE(name, vid) == nil
+}
+
+// RemoveVlanE is the error-returning variant of RemoveVlan
+func (v *VxlanInterfaceEntity) RemoveVlanE(name string, vid int) error {
 	cmd := "no vxlan vlan " + strconv.Itoa(vid) + " vni"
-	return v.ConfigureInterface(name, cmd)
+	return v.ConfigureInterfaceE(name, cmd)
 }
@@ -32,6 +32,12 @@
 
 package module
 
+import (
+	"context"
+
+	"github.com/aristanetworks/goeapi"
+)
+
 type ShowInterfacesSwitchport struct {
 	Switchports map[string]Switchport `json:"switchports"`
 }
@@ -61,10 +67,11 @@ func (l *ShowInterfacesSwitchport) GetCmd() string {
 }
 
 func (s *ShowEntity) ShowInterfacesSwitchport() ShowInterfacesSwitchport {
-	handle, _ := s.node.GetHandle("json")
-	var showInterfacesSwitchport ShowInterfacesSwitchport
-	handle.AddCommand(&showInterfacesSwitchport)
-	handle.Call()
-	handle.Close()
+	showInterfacesSwitchport, _ := s.ShowInterfacesSwitchportE()
 	return showInterfacesSwitchport
 }
+
+// ShowInterfacesSwitchportE is the error-returning variant of ShowInterfacesSwitchport
+func (s *ShowEntity) ShowInterfacesSwitchportE() (ShowInterfacesSwitchport, error) {
+	return goeapi.Run[ShowInterfacesSwitchport](context.Background(), s.node)
+}
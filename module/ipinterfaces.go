@@ -35,6 +35,7 @@ package module
 import (
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/aristanetworks/goeapi"
 )
@@ -125,17 +126,12 @@ func (i *IPInterfaceEntity) Get(name string) (IPInterfaceConfig, error) {
 //  name that represents all of the IP interfaces on
 //  the current node.
 func (i *IPInterfaceEntity) GetAll() IPInterfaceConfigMap {
-	var re = regexp.MustCompile(`(?m)^interface\s(.+)`)
-
-	config := i.Config()
-
-	interfaces := re.FindAllStringSubmatch(config, -1)
 	response := make(IPInterfaceConfigMap)
 
-	for _, name := range interfaces {
-		intf, _ := i.Get(name[1])
+	for _, name := range i.ConfigTree().Values("interface") {
+		intf, _ := i.Get(name)
 		if intf != nil {
-			response[name[1]] = intf
+			response[name] = intf
 		}
 	}
 	return response
@@ -183,15 +179,12 @@ func (i *IPInterfaceEntity) parseMtu(config string) string {
 // Returns:
 //  []string of interfaces
 func (i *IPInterfaceEntity) GetEthInterfaces() []string {
-	var re = regexp.MustCompile(`(?m)^interface\s(Eth.+)`)
-	config := i.Config()
-
-	interfaces := re.FindAllStringSubmatch(config, -1)
+	response := []string{}
 
-	response := make([]string, len(interfaces))
-
-	for idx, name := range interfaces {
-		response[idx] = name[1]
+	for _, name := range i.ConfigTree().Values("interface") {
+		if strings.HasPrefix(name, "Eth") {
+			response = append(response, name)
+		}
 	}
 	return response
 }
@@ -216,11 +209,16 @@ func (i *IPInterfaceEntity) GetEthInterfaces() []string {
 //  specified interface is already created the this method will
 //  have no effect but will still return True
 func (i *IPInterfaceEntity) Create(name string) bool {
+	return i.CreateE(name) == nil
+}
+
+// CreateE is the error-returning variant of Create
+func (i *IPInterfaceEntity) CreateE(name string) error {
 	commands := []string{
 		"interface " + name,
 		"no switchport",
 	}
-	return i.Configure(commands...)
+	return i.ConfigureE(commands...)
 }
 
 // Delete Deletes an IP interface instance from the running configuration
@@ -237,12 +235,17 @@ func (i *IPInterfaceEntity) Create(name string) bool {
 // Returns:
 //  True if the delete operation succeeds otherwise False.
 func (i *IPInterfaceEntity) Delete(name string) bool {
+	return i.DeleteE(name) == nil
+}
+
+// DeleteE is the error-returning variant of Delete
+func (i *IPInterfaceEntity) DeleteE(name string) error {
 	commands := []string{
 		"interface " + name,
 		"no ip address",
 		"switchport",
 	}
-	return i.Configure(commands...)
+	return i.ConfigureE(commands...)
 }
 
 // SetAddress Configures the interface IP address
@@ -256,13 +259,18 @@ func (i *IPInterfaceEntity) Delete(name string) bool {
 // Returns:
 //  True if the operation succeeds
 func (i *IPInterfaceEntity) SetAddress(name string, value string) bool {
+	return i.SetAddressE(name, value) == nil
+}
+
+// SetAddressE is the error-returning variant of SetAddress
+func (i *IPInterfaceEntity) SetAddressE(name string, value string) error {
 	commands := []string{"interface " + name}
 	if value != "" {
 		commands = append(commands, "ip address "+value)
 	} else {
 		commands = append(commands, "no ip address")
 	}
-	return i.Configure(commands...)
+	return i.ConfigureE(commands...)
 }
 
 // SetAddressDefault Configures the default interface IP address
@@ -272,11 +280,16 @@ func (i *IPInterfaceEntity) SetAddress(name string, value string) bool {
 // Returns:
 //  True if the operation succeeds
 func (i *IPInterfaceEntity) SetAddressDefault(name string) bool {
+	return i.SetAddressDefaultE(name) == nil
+}
+
+// SetAddressDefaultE is the error-returning variant of SetAddressDefault
+func (i *IPInterfaceEntity) SetAddressDefaultE(name string) error {
 	commands := []string{
 		"interface " + name,
 		"default ip address",
 	}
-	return i.Configure(commands...)
+	return i.ConfigureE(commands...)
 }
 
 // SetMtu Configures the interface IP MTU
@@ -289,14 +302,19 @@ func (i *IPInterfaceEntity) SetAddressDefault(name string) bool {
 // Returns:
 //  True if the operation succeeds otherwise False.
 func (i *IPInterfaceEntity) SetMtu(name string, value int) bool {
+	return i.SetMtuE(name, value) == nil
+}
+
+// SetMtuE is the error-returning variant of SetMtu
+func (i *IPInterfaceEntity) SetMtuE(name string, value int) error {
 	if !isValidMtu(value) {
-		return false
+		return invalid("MTU", value, "must be in the range 68 to 65535")
 	}
 	commands := []string{
 		"interface " + name,
 		"mtu " + strconv.Itoa(value),
 	}
-	return i.Configure(commands...)
+	return i.ConfigureE(commands...)
 }
 
 // SetMtuDefault Configures the default interface IP MTU
@@ -306,9 +324,14 @@ func (i *IPInterfaceEntity) SetMtu(name string, value int) bool {
 // Returns:
 //  True if the operation succeeds otherwise False.
 func (i *IPInterfaceEntity) SetMtuDefault(name string) bool {
+	return i.SetMtuDefaultE(name) == nil
+}
+
+// SetMtuDefaultE is the error-returning variant of SetMtuDefault
+func (i *IPInterfaceEntity) SetMtuDefaultE(name string) error {
 	commands := []string{
 		"interface " + name,
 		"default mtu",
 	}
-	return i.Configure(commands...)
+	return i.ConfigureE(commands...)
 }
@@ -32,6 +32,12 @@
 
 package module
 
+import (
+	"context"
+
+	"github.com/aristanetworks/goeapi"
+)
+
 type ShowIPRoute struct {
 	VRFs map[string]Routes `json:"vrfs"`
 }
@@ -58,11 +64,18 @@ func (r *ShowIPRoute) GetCmd() string {
 	return "show ip route"
 }
 
+// GetRevision returns the revision of the 'show ip route' model
+// ShowIPRoute was written against
+func (r *ShowIPRoute) GetRevision() int {
+	return 1
+}
+
 func (s *ShowEntity) ShowIPRoute() ShowIPRoute {
-	handle, _ := s.node.GetHandle("json")
-	var showiproute ShowIPRoute
-	handle.AddCommand(&showiproute)
-	handle.Call()
-	handle.Close()
+	showiproute, _ := s.ShowIPRouteE()
 	return showiproute
 }
+
+// ShowIPRouteE is the error-returning variant of ShowIPRoute
+func (s *ShowEntity) ShowIPRouteE() (ShowIPRoute, error) {
+	return goeapi.Run[ShowIPRoute](context.Background(), s.node)
+}
@@ -32,6 +32,12 @@
 
 package module
 
+import (
+	"context"
+
+	"github.com/aristanetworks/goeapi"
+)
+
 type ShowLLDPNeighbors struct {
 	TablesLastChangeTime float64        `json:"tablesLastChangeTime"`
 	TablesAgeouts        int            `json:"tablesAgeOuts"`
@@ -53,10 +59,11 @@ func (l *ShowLLDPNeighbors) GetCmd() string {
 }
 
 func (s *ShowEntity) ShowLLDPNeighbors() ShowLLDPNeighbors {
-	handle, _ := s.node.GetHandle("json")
-	var showlldpneighbors ShowLLDPNeighbors
-	handle.AddCommand(&showlldpneighbors)
-	handle.Call()
-	handle.Close()
+	showlldpneighbors, _ := s.ShowLLDPNeighborsE()
 	return showlldpneighbors
 }
+
+// ShowLLDPNeighborsE is the error-returning variant of ShowLLDPNeighbors
+func (s *ShowEntity) ShowLLDPNeighborsE() (ShowLLDPNeighbors, error) {
+	return goeapi.Run[ShowLLDPNeighbors](context.Background(), s.node)
+}
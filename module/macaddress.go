@@ -32,6 +32,12 @@
 
 package module
 
+import (
+	"context"
+
+	"github.com/aristanetworks/goeapi"
+)
+
 type ShowMACAddressTable struct {
 	MulticastTable struct {
 		TableEntries []MACAddressTableEntry `json:"tableEntries"`
@@ -55,22 +61,5 @@ func (a *ShowMACAddressTable) GetCmd() string {
 }
 
 func (s *ShowEntity) ShowMACAddressTable() (ShowMACAddressTable, error) {
-	var showmacaddresstable ShowMACAddressTable
-
-	handle, err := s.node.GetHandle("json")
-	if err != nil {
-		return showmacaddresstable, err
-	}
-
-	err = handle.AddCommand(&showmacaddresstable)
-	if err != nil {
-		return showmacaddresstable, err
-	}
-
-	if err := handle.Call(); err != nil {
-		return showmacaddresstable, err
-	}
-
-	handle.Close()
-	return showmacaddresstable, nil
+	return goeapi.Run[ShowMACAddressTable](context.Background(), s.node)
 }
@@ -35,6 +35,7 @@ package module
 import (
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/aristanetworks/goeapi"
 )
@@ -251,22 +252,18 @@ func (m *MlagEntity) parseShutdown(config string) bool {
 // Returns:
 //  InterfaceMlagConfig object
 func (m *MlagEntity) parseInterfaces() InterfaceMlagConfig {
-	config := m.Config()
-
 	var resource = make(InterfaceMlagConfig)
 
-	reIntf := regexp.MustCompile(`(?m)^interface (Po.+)$`)
 	reMlag := regexp.MustCompile(`(?m)mlag (\d+)`)
 
-	interfaces := reIntf.FindAllStringSubmatch(config, -1)
-
-	for _, intf := range interfaces {
-		parent := "interface " + intf[1]
-		config, _ := m.GetBlock(parent)
-
-		match := reMlag.FindStringSubmatch(config)
+	for _, section := range m.ConfigTree().FindAll("interface") {
+		name := strings.TrimPrefix(section.Text(), "interface ")
+		if !strings.HasPrefix(name, "Po") {
+			continue
+		}
+		match := reMlag.FindStringSubmatch(section.String())
 		if match != nil {
-			resource[intf[1]] = match[1]
+			resource[name] = match[1]
 		}
 	}
 	return resource
@@ -297,9 +294,14 @@ func (m *MlagEntity) GetSection() string {
 // Returns:
 //  bool: True if the commands complete successfully
 func (m *MlagEntity) ConfigureMlag(cmd string, value string, def bool, enable bool) bool {
+	return m.ConfigureMlagE(cmd, value, def, enable) == nil
+}
+
+// ConfigureMlagE is the error-returning variant of ConfigureMlag
+func (m *MlagEntity) ConfigureMlagE(cmd string, value string, def bool, enable bool) error {
 	cfg := m.CommandBuilder(cmd, value, def, enable)
 	var commands = []string{"mlag configuration", cfg}
-	return m.Configure(commands...)
+	return m.ConfigureE(commands...)
 }
 
 // SetDomainID Configures the mlag domain-id value
@@ -310,10 +312,15 @@ func (m *MlagEntity) ConfigureMlag(cmd string, value string, def bool, enable bo
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetDomainID(value string) bool {
+	return m.SetDomainIDE(value) == nil
+}
+
+// SetDomainIDE is the error-returning variant of SetDomainID
+func (m *MlagEntity) SetDomainIDE(value string) error {
 	if value == "" {
-		return m.ConfigureMlag("domain-id", value, false, false)
+		return m.ConfigureMlagE("domain-id", value, false, false)
 	}
-	return m.ConfigureMlag("domain-id", value, false, true)
+	return m.ConfigureMlagE("domain-id", value, false, true)
 }
 
 // SetDomainIDDefault Configures the default mlag domain-id value
@@ -321,7 +328,12 @@ func (m *MlagEntity) SetDomainID(value string) bool {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetDomainIDDefault() bool {
-	return m.ConfigureMlag("domain-id", "", true, false)
+	return m.SetDomainIDDefaultE() == nil
+}
+
+// SetDomainIDDefaultE is the error-returning variant of SetDomainIDDefault
+func (m *MlagEntity) SetDomainIDDefaultE() error {
+	return m.ConfigureMlagE("domain-id", "", true, false)
 }
 
 // SetLocalInterface Configures the mlag local-interface value
@@ -332,10 +344,15 @@ func (m *MlagEntity) SetDomainIDDefault() bool {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetLocalInterface(value string) bool {
+	return m.SetLocalInterfaceE(value) == nil
+}
+
+// SetLocalInterfaceE is the error-returning variant of SetLocalInterface
+func (m *MlagEntity) SetLocalInterfaceE(value string) error {
 	if value == "" {
-		return m.ConfigureMlag("local-interface", value, false, false)
+		return m.ConfigureMlagE("local-interface", value, false, false)
 	}
-	return m.ConfigureMlag("local-interface", value, false, true)
+	return m.ConfigureMlagE("local-interface", value, false, true)
 }
 
 // SetLocalInterfaceDefault Configures the default mlag local-interface value
@@ -343,7 +360,12 @@ func (m *MlagEntity) SetLocalInterface(value string) bool {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetLocalInterfaceDefault() bool {
-	return m.ConfigureMlag("local-interface", "", true, false)
+	return m.SetLocalInterfaceDefaultE() == nil
+}
+
+// SetLocalInterfaceDefaultE is the error-returning variant of SetLocalInterfaceDefault
+func (m *MlagEntity) SetLocalInterfaceDefaultE() error {
+	return m.ConfigureMlagE("local-interface", "", true, false)
 }
 
 // SetPeerAddress Configures the mlag peer-address value
@@ -355,10 +377,15 @@ func (m *MlagEntity) SetLocalInterfaceDefault() bool {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetPeerAddress(value string) bool {
+	return m.SetPeerAddressE(value) == nil
+}
+
+// SetPeerAddressE is the error-returning variant of SetPeerAddress
+func (m *MlagEntity) SetPeerAddressE(value string) error {
 	if value == "" {
-		return m.ConfigureMlag("peer-address", value, false, false)
+		return m.ConfigureMlagE("peer-address", value, false, false)
 	}
-	return m.ConfigureMlag("peer-address", value, false, true)
+	return m.ConfigureMlagE("peer-address", value, false, true)
 }
 
 // SetPeerAddressDefault Configures the default mlag peer-address value
@@ -366,7 +393,12 @@ func (m *MlagEntity) SetPeerAddress(value string) bool {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetPeerAddressDefault() bool {
-	return m.ConfigureMlag("peer-address", "", true, false)
+	return m.SetPeerAddressDefaultE() == nil
+}
+
+// SetPeerAddressDefaultE is the error-returning variant of SetPeerAddressDefault
+func (m *MlagEntity) SetPeerAddressDefaultE() error {
+	return m.ConfigureMlagE("peer-address", "", true, false)
 }
 
 // SetPeerLink Configures the mlag peer-link value
@@ -379,10 +411,15 @@ func (m *MlagEntity) SetPeerAddressDefault() bool {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetPeerLink(value string) bool {
+	return m.SetPeerLinkE(value) == nil
+}
+
+// SetPeerLinkE is the error-returning variant of SetPeerLink
+func (m *MlagEntity) SetPeerLinkE(value string) error {
 	if value == "" {
-		return m.ConfigureMlag("peer-link", value, false, false)
+		return m.ConfigureMlagE("peer-link", value, false, false)
 	}
-	return m.ConfigureMlag("peer-link", value, false, true)
+	return m.ConfigureMlagE("peer-link", value, false, true)
 }
 
 // SetPeerLinkDefault Configures the default mlag peer-link value
@@ -390,7 +427,12 @@ func (m *MlagEntity) SetPeerLink(value string) bool {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetPeerLinkDefault() bool {
-	return m.ConfigureMlag("peer-link", "", true, false)
+	return m.SetPeerLinkDefaultE() == nil
+}
+
+// SetPeerLinkDefaultE is the error-returning variant of SetPeerLinkDefault
+func (m *MlagEntity) SetPeerLinkDefaultE() error {
+	return m.ConfigureMlagE("peer-link", "", true, false)
 }
 
 // SetShutdown Configures the mlag shutdown value
@@ -400,7 +442,12 @@ func (m *MlagEntity) SetPeerLinkDefault() bool {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetShutdown(enable bool) bool {
-	return m.ConfigureMlag("shutdown", "", false, enable)
+	return m.SetShutdownE(enable) == nil
+}
+
+// SetShutdownE is the error-returning variant of SetShutdown
+func (m *MlagEntity) SetShutdownE(enable bool) error {
+	return m.ConfigureMlagE("shutdown", "", false, enable)
 }
 
 // SetShutdownDefault Configures the mlag default shutdown value
@@ -408,7 +455,12 @@ func (m *MlagEntity) SetShutdown(enable bool) bool {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetShutdownDefault() bool {
-	return m.ConfigureMlag("shutdown", "", true, false)
+	return m.SetShutdownDefaultE() == nil
+}
+
+// SetShutdownDefaultE is the error-returning variant of SetShutdownDefault
+func (m *MlagEntity) SetShutdownDefaultE() error {
+	return m.ConfigureMlagE("shutdown", "", true, false)
 }
 
 // SetMlagID Configures the interface mlag value for the specified interface
@@ -421,6 +473,11 @@ func (m *MlagEntity) SetShutdownDefault() bool {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetMlagID(name string, value string) bool {
+	return m.SetMlagIDE(name, value) == nil
+}
+
+// SetMlagIDE is the error-returning variant of SetMlagID
+func (m *MlagEntity) SetMlagIDE(name string, value string) error {
 	var cmd string
 	if value == "" {
 		cmd = m.CommandBuilder("mlag", value, false, false)
@@ -428,7 +485,7 @@ func (m *MlagEntity) SetMlagID(name string, value string) bool {
 		cmd = m.CommandBuilder("mlag", value, false, true)
 	}
 	var commands = []string{cmd}
-	return m.ConfigureInterface(name, commands...)
+	return m.ConfigureInterfaceE(name, commands...)
 }
 
 // SetMlagIDDefault Configures the default interface mlag value for the
@@ -441,5 +498,10 @@ func (m *MlagEntity) SetMlagID(name string, value string) bool {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (m *MlagEntity) SetMlagIDDefault(name string) bool {
-	return m.ConfigureInterface(name, []string{"default mlag"}...)
+	return m.SetMlagIDDefaultE(name) == nil
+}
+
+// SetMlagIDDefaultE is the error-returning variant of SetMlagIDDefault
+func (m *MlagEntity) SetMlagIDDefaultE(name string) error {
+	return m.ConfigureInterfaceE(name, []string{"default mlag"}...)
 }
@@ -0,0 +1,535 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package module
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aristanetworks/goeapi"
+)
+
+// The models below are typed counterparts of the map[string]string
+// configs returned by the Get and GetAll methods of the entities. They are
+// returned by the GetTyped and GetAllTyped methods and serialize to JSON
+// and YAML using the same keys as the configs they mirror.
+//
+// GetTyped returns a nil model if the resource is not configured, and an
+// error if its config cannot be read or converted. GetAllTyped returns an
+// error if any of the configs cannot be converted.
+
+// VlanList is a list of VLAN IDs. It is serialized as the compact range
+// form used by EOS, e.g. "1,10-20".
+type VlanList []int
+
+// ParseVlanList parses a VLAN range string such as "1,10-20" into a
+// sorted VlanList. The keywords "all" and "none" are accepted, the
+// latter returning an empty list.
+func ParseVlanList(s string) (VlanList, error) {
+	set, err := ParseVlanSet(s)
+	if err != nil {
+		return nil, err
+	}
+	return set.Vlans(), nil
+}
+
+// Set returns the VlanSet of the VLANs in l
+func (l VlanList) Set() VlanSet {
+	return NewVlanSet(l...)
+}
+
+// String returns the compact range form of l, e.g. "1,10-20", or "none"
+// if l is empty.
+func (l VlanList) String() string {
+	return l.Set().String()
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (l VlanList) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (l *VlanList) UnmarshalText(text []byte) error {
+	list, err := ParseVlanList(string(text))
+	if err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// VlanModel is the typed form of a VlanConfig
+type VlanModel struct {
+	ID          int      `json:"id" yaml:"id"`
+	Name        string   `json:"name" yaml:"name"`
+	State       string   `json:"state" yaml:"state"`
+	TrunkGroups []string `json:"trunk_groups,omitempty" yaml:"trunk_groups,omitempty"`
+}
+
+// newVlanModel converts the VlanConfig of VLAN vid to a VlanModel
+func newVlanModel(vid string, config VlanConfig) (*VlanModel, error) {
+	id, err := strconv.Atoi(vid)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid VLAN ID: %s", vid)
+	}
+	return &VlanModel{
+		ID:          id,
+		Name:        config["name"],
+		State:       config["state"],
+		TrunkGroups: splitList(config["trunk_groups"]),
+	}, nil
+}
+
+// Config returns m as a VlanConfig
+func (m *VlanModel) Config() VlanConfig {
+	return VlanConfig{
+		"name":         m.Name,
+		"state":        m.State,
+		"trunk_groups": strings.Join(m.TrunkGroups, ","),
+	}
+}
+
+// GetTyped returns the VlanModel of vlan, or nil if it is not configured
+func (v *VlanEntity) GetTyped(vlan string) (*VlanModel, error) {
+	config := v.Get(vlan)
+	if config == nil {
+		return nil, nil
+	}
+	return newVlanModel(vlan, config)
+}
+
+// GetAllTyped returns the VlanModels of all VLANs keyed by VLAN ID
+func (v *VlanEntity) GetAllTyped() (map[string]*VlanModel, error) {
+	models := make(map[string]*VlanModel)
+	for vid, config := range v.GetAll() {
+		model, err := newVlanModel(vid, config)
+		if err != nil {
+			return nil, err
+		}
+		models[vid] = model
+	}
+	return models, nil
+}
+
+// SwitchPortModel is the typed form of a SwitchPortConfig
+type SwitchPortModel struct {
+	Name              string   `json:"name" yaml:"name"`
+	Mode              string   `json:"mode" yaml:"mode"`
+	AccessVlan        int      `json:"access_vlan" yaml:"access_vlan"`
+	TrunkNativeVlan   int      `json:"trunk_native_vlan" yaml:"trunk_native_vlan"`
+	TrunkAllowedVlans VlanList `json:"trunk_allowed_vlans" yaml:"trunk_allowed_vlans"`
+	TrunkGroups       []string `json:"trunk_groups,omitempty" yaml:"trunk_groups,omitempty"`
+}
+
+// newSwitchPortModel converts a SwitchPortConfig to a SwitchPortModel
+func newSwitchPortModel(config SwitchPortConfig) (*SwitchPortModel, error) {
+	access, _ := strconv.Atoi(config["access_vlan"])
+	native, _ := strconv.Atoi(config["trunk_native_vlan"])
+	allowed, err := ParseVlanList(config["trunk_allowed_vlans"])
+	if err != nil {
+		return nil, err
+	}
+	return &SwitchPortModel{
+		Name:              config["name"],
+		Mode:              config["mode"],
+		AccessVlan:        access,
+		TrunkNativeVlan:   native,
+		TrunkAllowedVlans: allowed,
+		TrunkGroups:       splitList(config["trunk_groups"]),
+	}, nil
+}
+
+// Config returns m as a SwitchPortConfig
+func (m *SwitchPortModel) Config() SwitchPortConfig {
+	return SwitchPortConfig{
+		"name":                m.Name,
+		"mode":                m.Mode,
+		"access_vlan":         strconv.Itoa(m.AccessVlan),
+		"trunk_native_vlan":   strconv.Itoa(m.TrunkNativeVlan),
+		"trunk_allowed_vlans": m.TrunkAllowedVlans.String(),
+		"trunk_groups":        strings.Join(m.TrunkGroups, ","),
+	}
+}
+
+// GetTyped returns the SwitchPortModel of the interface name, or nil if
+// it is not a switchport
+func (s *SwitchPortEntity) GetTyped(name string) (*SwitchPortModel, error) {
+	config := s.Get(name)
+	if config == nil {
+		return nil, nil
+	}
+	return newSwitchPortModel(config)
+}
+
+// GetAllTyped returns the SwitchPortModels of all switchports keyed by
+// interface name
+func (s *SwitchPortEntity) GetAllTyped() (map[string]*SwitchPortModel, error) {
+	models := make(map[string]*SwitchPortModel)
+	for name, config := range s.GetAll() {
+		model, err := newSwitchPortModel(config)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+		models[name] = model
+	}
+	return models, nil
+}
+
+// InterfaceModel is the typed form of an InterfaceConfig
+type InterfaceModel struct {
+	Name        string `json:"name" yaml:"name"`
+	Type        string `json:"type" yaml:"type"`
+	Shutdown    bool   `json:"shutdown" yaml:"shutdown"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Config returns m as an InterfaceConfig
+func (m *InterfaceModel) Config() InterfaceConfig {
+	return InterfaceConfig{
+		"name":        m.Name,
+		"type":        m.Type,
+		"shutdown":    strconv.FormatBool(m.Shutdown),
+		"description": m.Description,
+	}
+}
+
+// GetTyped returns the InterfaceModel of the interface name, or nil if
+// it is not configured
+func (i *BaseInterfaceEntity) GetTyped(name string) (*InterfaceModel, error) {
+	tree, err := i.node.ConfigTree(goeapi.RunningConfig)
+	if err != nil {
+		return nil, err
+	}
+	if tree.Child("interface "+name) == nil {
+		return nil, nil
+	}
+	config := i.Get(name)
+	return &InterfaceModel{
+		Name:        config["name"],
+		Type:        config["type"],
+		Shutdown:    config["shutdown"] == "true",
+		Description: config["description"],
+	}, nil
+}
+
+// GetAllTyped returns the InterfaceModels of all interfaces keyed by
+// interface name
+func (i *BaseInterfaceEntity) GetAllTyped() (map[string]*InterfaceModel, error) {
+	tree, err := i.node.ConfigTree(goeapi.RunningConfig)
+	if err != nil {
+		return nil, err
+	}
+	models := make(map[string]*InterfaceModel)
+	for _, name := range tree.Values("interface") {
+		model, err := i.GetTyped(name)
+		if err != nil {
+			return nil, err
+		}
+		models[name] = model
+	}
+	return models, nil
+}
+
+// IPInterfaceModel is the typed form of an IPInterfaceConfig. Address is
+// the zero netip.Prefix if no address is configured, and Mtu is 0 if the
+// IP MTU is not configured.
+type IPInterfaceModel struct {
+	Name    string       `json:"name" yaml:"name"`
+	Address netip.Prefix `json:"address" yaml:"address"`
+	Mtu     int          `json:"mtu,omitempty" yaml:"mtu,omitempty"`
+}
+
+// newIPInterfaceModel converts an IPInterfaceConfig to an IPInterfaceModel
+func newIPInterfaceModel(config IPInterfaceConfig) (*IPInterfaceModel, error) {
+	var address netip.Prefix
+	if config["address"] != "" {
+		var err error
+		if address, err = netip.ParsePrefix(config["address"]); err != nil {
+			return nil, err
+		}
+	}
+	mtu, _ := strconv.Atoi(config["mtu"])
+	return &IPInterfaceModel{
+		Name:    config["name"],
+		Address: address,
+		Mtu:     mtu,
+	}, nil
+}
+
+// Config returns m as an IPInterfaceConfig
+func (m *IPInterfaceModel) Config() IPInterfaceConfig {
+	config := IPInterfaceConfig{"name": m.Name, "address": "", "mtu": ""}
+	if m.Address.IsValid() {
+		config["address"] = m.Address.String()
+	}
+	if m.Mtu != 0 {
+		config["mtu"] = strconv.Itoa(m.Mtu)
+	}
+	return config
+}
+
+// GetTyped returns the IPInterfaceModel of the interface name, or nil if
+// it is a switchport
+func (i *IPInterfaceEntity) GetTyped(name string) (*IPInterfaceModel, error) {
+	config, err := i.Get(name)
+	if config == nil || err != nil {
+		return nil, err
+	}
+	return newIPInterfaceModel(config)
+}
+
+// GetAllTyped returns the IPInterfaceModels of all IP interfaces keyed by
+// interface name
+func (i *IPInterfaceEntity) GetAllTyped() (map[string]*IPInterfaceModel, error) {
+	models := make(map[string]*IPInterfaceModel)
+	for name, config := range i.GetAll() {
+		model, err := newIPInterfaceModel(config)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+		models[name] = model
+	}
+	return models, nil
+}
+
+// UserModel is the typed form of a UserConfig
+type UserModel struct {
+	Username   string `json:"username" yaml:"username"`
+	Privilege  int    `json:"privilege" yaml:"privilege"`
+	Role       string `json:"role,omitempty" yaml:"role,omitempty"`
+	Nopassword bool   `json:"nopassword" yaml:"nopassword"`
+	Format     string `json:"format,omitempty" yaml:"format,omitempty"`
+	Secret     string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	SSHKey     string `json:"sshkey,omitempty" yaml:"sshkey,omitempty"`
+}
+
+// newUserModel converts a UserConfig to a UserModel
+func newUserModel(config UserConfig) *UserModel {
+	privilege, _ := strconv.Atoi(config.Privilege())
+	return &UserModel{
+		Username:   config.UserName(),
+		Privilege:  privilege,
+		Role:       config.Role(),
+		Nopassword: config.Nopassword() == "true",
+		Format:     config.Format(),
+		Secret:     config.Secret(),
+		SSHKey:     config.SSHKey(),
+	}
+}
+
+// Config returns m as a UserConfig
+func (m *UserModel) Config() UserConfig {
+	return UserConfig{
+		"username":   m.Username,
+		"privilege":  strconv.Itoa(m.Privilege),
+		"role":       m.Role,
+		"nopassword": strconv.FormatBool(m.Nopassword),
+		"format":     m.Format,
+		"secret":     m.Secret,
+		"sshkey":     m.SSHKey,
+	}
+}
+
+// GetTyped returns the UserModel of the user name, or nil if it is not
+// configured
+func (u *UserEntity) GetTyped(name string) (*UserModel, error) {
+	config := u.Get(name)
+	if config == nil {
+		return nil, nil
+	}
+	return newUserModel(config), nil
+}
+
+// GetAllTyped returns the UserModels of all users keyed by username
+func (u *UserEntity) GetAllTyped() (map[string]*UserModel, error) {
+	models := make(map[string]*UserModel)
+	for name, config := range u.GetAll() {
+		models[name] = newUserModel(config)
+	}
+	return models, nil
+}
+
+// BgpNeighborModel is the typed form of a BgpNeighborConfig. RemoteAS
+// is 0 if the remote AS is inherited from the peer group.
+type BgpNeighborModel struct {
+	Name          string `json:"name" yaml:"name"`
+	PeerGroup     string `json:"peer_group,omitempty" yaml:"peer_group,omitempty"`
+	RemoteAS      uint32 `json:"remote_as,omitempty" yaml:"remote_as,omitempty"`
+	SendCommunity bool   `json:"send_community" yaml:"send_community"`
+	Shutdown      bool   `json:"shutdown" yaml:"shutdown"`
+	Description   string `json:"description,omitempty" yaml:"description,omitempty"`
+	NextHopSelf   bool   `json:"next_hop_self" yaml:"next_hop_self"`
+	RouteInMap    string `json:"route_in_map,omitempty" yaml:"route_in_map,omitempty"`
+	RouteOutMap   string `json:"route_out_map,omitempty" yaml:"route_out_map,omitempty"`
+}
+
+// newBgpNeighborModel converts the BgpNeighborConfig of neighbor name to
+// a BgpNeighborModel
+func newBgpNeighborModel(name string, config BgpNeighborConfig) *BgpNeighborModel {
+	return &BgpNeighborModel{
+		Name:          name,
+		PeerGroup:     config["peer_group"],
+		RemoteAS:      parseASN(config["remote_as"]),
+		SendCommunity: config["send_community"] == "true",
+		Shutdown:      config["shutdown"] == "true",
+		Description:   config["description"],
+		NextHopSelf:   config["next_hop_self"] == "true",
+		RouteInMap:    config["route_in_map"],
+		RouteOutMap:   config["route_out_map"],
+	}
+}
+
+// Config returns m as a BgpNeighborConfig
+func (m *BgpNeighborModel) Config() BgpNeighborConfig {
+	config := BgpNeighborConfig{
+		"peer_group":     m.PeerGroup,
+		"remote_as":      "",
+		"send_community": strconv.FormatBool(m.SendCommunity),
+		"shutdown":       strconv.FormatBool(m.Shutdown),
+		"description":    m.Description,
+		"next_hop_self":  strconv.FormatBool(m.NextHopSelf),
+		"route_in_map":   m.RouteInMap,
+		"route_out_map":  m.RouteOutMap,
+	}
+	if m.RemoteAS != 0 {
+		config["remote_as"] = strconv.FormatUint(uint64(m.RemoteAS), 10)
+	}
+	return config
+}
+
+// GetTyped returns the BgpNeighborModel of the neighbor name, or nil if
+// it is not configured
+func (b *BgpNeighborsEntity) GetTyped(name string) (*BgpNeighborModel, error) {
+	config, err := b.GetBlock(`^router bgp .*`)
+	if err != nil {
+		return nil, err
+	}
+	re := regexp.MustCompile(`(?m)^\s+neighbor ` + regexp.QuoteMeta(name) + `\s`)
+	if !re.MatchString(config) {
+		return nil, nil
+	}
+	return newBgpNeighborModel(name, b.Get(name)), nil
+}
+
+// GetAllTyped returns the BgpNeighborModels of all neighbors keyed by
+// neighbor, or nil if BGP is not configured
+func (b *BgpNeighborsEntity) GetAllTyped() (map[string]*BgpNeighborModel, error) {
+	collection := b.GetAll()
+	if collection == nil {
+		return nil, nil
+	}
+	models := make(map[string]*BgpNeighborModel)
+	for name, config := range collection {
+		models[name] = newBgpNeighborModel(name, config)
+	}
+	return models, nil
+}
+
+// STPInterfaceModel is the typed form of an STPInterfaceConfig
+type STPInterfaceModel struct {
+	Name         string `json:"name" yaml:"name"`
+	BPDUGuard    bool   `json:"bpduguard" yaml:"bpduguard"`
+	Portfast     bool   `json:"portfast" yaml:"portfast"`
+	PortfastType string `json:"portfast_type" yaml:"portfast_type"`
+}
+
+// newSTPInterfaceModel converts the STPInterfaceConfig of interface name
+// to an STPInterfaceModel
+func newSTPInterfaceModel(name string, config STPInterfaceConfig) *STPInterfaceModel {
+	return &STPInterfaceModel{
+		Name:         name,
+		BPDUGuard:    config["bpduguard"] == "true",
+		Portfast:     config["portfast"] == "true",
+		PortfastType: config["portfast_type"],
+	}
+}
+
+// Config returns m as an STPInterfaceConfig
+func (m *STPInterfaceModel) Config() STPInterfaceConfig {
+	return STPInterfaceConfig{
+		"bpduguard":     strconv.FormatBool(m.BPDUGuard),
+		"portfast":      strconv.FormatBool(m.Portfast),
+		"portfast_type": m.PortfastType,
+	}
+}
+
+// GetTyped returns the STPInterfaceModel of the interface name, or nil
+// if it is not configured
+func (s *STPInterfaceEntity) GetTyped(name string) (*STPInterfaceModel, error) {
+	tree, err := s.node.ConfigTree(goeapi.RunningConfig)
+	if err != nil {
+		return nil, err
+	}
+	if tree.Child("interface "+name) == nil {
+		return nil, nil
+	}
+	return newSTPInterfaceModel(name, s.Get(name)), nil
+}
+
+// GetAllTyped returns the STPInterfaceModels of all Ethernet and
+// Port-Channel interfaces keyed by interface name
+func (s *STPInterfaceEntity) GetAllTyped() (map[string]*STPInterfaceModel, error) {
+	models := make(map[string]*STPInterfaceModel)
+	for name, config := range s.GetAll() {
+		models[name] = newSTPInterfaceModel(name, config)
+	}
+	return models, nil
+}
+
+// splitList splits a comma delimited list, returning nil if s is empty
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// parseASN parses an AS number in asplain or asdot notation, returning 0
+// if s is not a valid AS number
+func parseASN(s string) uint32 {
+	high, low, dot := strings.Cut(s, ".")
+	if !dot {
+		asn, _ := strconv.ParseUint(s, 10, 32)
+		return uint32(asn)
+	}
+	h, err1 := strconv.ParseUint(high, 10, 16)
+	l, err2 := strconv.ParseUint(low, 10, 16)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return uint32(h<<16 | l)
+}
@@ -0,0 +1,247 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package module
+
+import (
+	"encoding/json"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestParseVlanList_UnitTest(t *testing.T) {
+	tests := []struct {
+		in   string
+		want VlanList
+		str  string
+	}{
+		{"", nil, "none"},
+		{"none", nil, "none"},
+		{"10", VlanList{10}, "10"},
+		{"1,3-5", VlanList{1, 3, 4, 5}, "1,3-5"},
+		{"20-22,10,21", VlanList{10, 20, 21, 22}, "10,20-22"},
+		{"1-3,4", VlanList{1, 2, 3, 4}, "1-4"},
+	}
+	for _, tt := range tests {
+		got, err := ParseVlanList(tt.in)
+		if err != nil {
+			t.Fatalf("ParseVlanList(%q) failed: %s", tt.in, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("ParseVlanList(%q) = %#v; want %#v", tt.in, got, tt.want)
+		}
+		if got.String() != tt.str {
+			t.Fatalf("String() = %q; want %q", got.String(), tt.str)
+		}
+	}
+	if list, _ := ParseVlanList("all"); len(list) != 4094 ||
+		list.String() != "1-4094" {
+		t.Fatalf("ParseVlanList(\"all\") = %s", list)
+	}
+	for _, in := range []string{"0", "4095", "a", "10-5", "1,,2"} {
+		if _, err := ParseVlanList(in); err == nil {
+			t.Fatalf("ParseVlanList(%q) expected error", in)
+		}
+	}
+}
+
+func TestModelsGetTyped_UnitTest(t *testing.T) {
+	vlan, err := Vlan(dummyNode).GetTyped("10")
+	want := &VlanModel{ID: 10, Name: "VLAN0010", State: "active",
+		TrunkGroups: []string{"tg1"}}
+	if err != nil || !reflect.DeepEqual(vlan, want) {
+		t.Fatalf("Vlan GetTyped() = %#v, %v; want %#v", vlan, err, want)
+	}
+	if vlan, err := Vlan(dummyNode).GetTyped("4000"); vlan != nil || err != nil {
+		t.Fatal("Vlan GetTyped() should return nil for missing vlan")
+	}
+
+	port, err := SwitchPort(dummyNode).GetTyped("Ethernet1")
+	if err != nil || port == nil || port.Mode != "access" || port.AccessVlan != 1 ||
+		len(port.TrunkAllowedVlans) != 4094 ||
+		!reflect.DeepEqual(port.TrunkGroups, []string{"foo", "bar"}) {
+		t.Fatalf("SwitchPort GetTyped() = %#v", port)
+	}
+
+	intf, err := Interface(dummyNode).GetTyped("Ethernet1")
+	if err != nil || intf == nil || intf.Shutdown || intf.Description != "" {
+		t.Fatalf("Interface GetTyped() = %#v", intf)
+	}
+	if intf, err := Interface(dummyNode).GetTyped("Ethernet100"); intf != nil || err != nil {
+		t.Fatal("Interface GetTyped() should return nil for missing interface")
+	}
+
+	ipintf, err := IPInterface(dummyNode).GetTyped("Loopback0")
+	if err != nil || ipintf == nil ||
+		ipintf.Address != netip.MustParsePrefix("1.1.1.1/32") {
+		t.Fatalf("IPInterface GetTyped() = %#v", ipintf)
+	}
+
+	user, err := User(dummyNode).GetTyped("test2")
+	if err != nil || user == nil || user.Privilege != 9 || user.Role != "ops" ||
+		user.Nopassword || user.Format != "5" {
+		t.Fatalf("User GetTyped() = %#v", user)
+	}
+
+	neighbor, err := BgpNeighbors(dummyNode).GetTyped("test")
+	if err != nil || neighbor == nil || neighbor.Name != "test" ||
+		neighbor.RemoteAS != 65001 {
+		t.Fatalf("BgpNeighbor GetTyped() = %#v", neighbor)
+	}
+	if neighbor, err := BgpNeighbors(dummyNode).GetTyped("bogus"); neighbor != nil || err != nil {
+		t.Fatal("BgpNeighbor GetTyped() should return nil for missing neighbor")
+	}
+
+	stp, err := STPInterfaces(dummyNode).GetTyped("Ethernet1")
+	if err != nil || stp == nil || stp.Name != "Ethernet1" {
+		t.Fatalf("STPInterface GetTyped() = %#v", stp)
+	}
+	if stp, err := STPInterfaces(dummyNode).GetTyped("Ethernet100"); stp != nil || err != nil {
+		t.Fatal("STPInterface GetTyped() should return nil for missing interface")
+	}
+}
+
+func TestModelsGetTypedConnectionError_UnitTest(t *testing.T) {
+	dummyConnection.setReturnError(true)
+	if _, err := BgpNeighbors(dummyNode).GetTyped("test"); err == nil {
+		t.Fatal("BgpNeighbor GetTyped() should fail on connection error")
+	}
+	dummyConnection.setReturnError(true)
+	if _, err := STPInterfaces(dummyNode).GetTyped("Ethernet1"); err == nil {
+		t.Fatal("STPInterface GetTyped() should fail on connection error")
+	}
+}
+
+func TestModelsGetAllTyped_UnitTest(t *testing.T) {
+	vlans, err := Vlan(dummyNode).GetAllTyped()
+	if err != nil || len(vlans) != len(Vlan(dummyNode).GetAll()) ||
+		vlans["100"].Name != "mytest" {
+		t.Fatalf("Vlan GetAllTyped() = %#v", vlans)
+	}
+	ports, err := SwitchPort(dummyNode).GetAllTyped()
+	if err != nil || len(ports) != len(SwitchPort(dummyNode).GetAll()) {
+		t.Fatalf("SwitchPort GetAllTyped() = %#v", ports)
+	}
+	intfs, err := Interface(dummyNode).GetAllTyped()
+	if err != nil || intfs["Ethernet1"] == nil {
+		t.Fatalf("Interface GetAllTyped() = %#v", intfs)
+	}
+	ipintfs, err := IPInterface(dummyNode).GetAllTyped()
+	if err != nil || ipintfs["Loopback0"] == nil {
+		t.Fatalf("IPInterface GetAllTyped() = %#v", ipintfs)
+	}
+	users, err := User(dummyNode).GetAllTyped()
+	if err != nil || len(users) != len(User(dummyNode).GetAll()) ||
+		!users["admin"].Nopassword {
+		t.Fatalf("User GetAllTyped() = %#v", users)
+	}
+	neighbors, err := BgpNeighbors(dummyNode).GetAllTyped()
+	if err != nil || neighbors["test1"] == nil ||
+		neighbors["test1"].RouteInMap != "RM-IN" {
+		t.Fatalf("BgpNeighbor GetAllTyped() = %#v", neighbors)
+	}
+	stp, err := STPInterfaces(dummyNode).GetAllTyped()
+	if err != nil || len(stp) == 0 || stp["Ethernet1"] == nil {
+		t.Fatalf("STPInterface GetAllTyped() = %#v", stp)
+	}
+}
+
+func TestModelsJSONRoundTrip_UnitTest(t *testing.T) {
+	vlan, _ := Vlan(dummyNode).GetTyped("10")
+	port, _ := SwitchPort(dummyNode).GetTyped("Ethernet1")
+	intf, _ := Interface(dummyNode).GetTyped("Ethernet1")
+	ipintf, _ := IPInterface(dummyNode).GetTyped("Loopback0")
+	user, _ := User(dummyNode).GetTyped("test2")
+	neighbor, _ := BgpNeighbors(dummyNode).GetTyped("test1")
+	stp, _ := STPInterfaces(dummyNode).GetTyped("Ethernet1")
+	models := []interface{}{
+		vlan,
+		port,
+		intf,
+		ipintf,
+		user,
+		neighbor,
+		stp,
+		&SwitchPortModel{Name: "Ethernet2"},
+		&IPInterfaceModel{Name: "Vlan10"},
+	}
+	for _, model := range models {
+		data, err := json.Marshal(model)
+		if err != nil {
+			t.Fatalf("Marshal(%#v) failed: %s", model, err)
+		}
+		decoded := reflect.New(reflect.TypeOf(model).Elem()).Interface()
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatalf("Unmarshal(%s) failed: %s", data, err)
+		}
+		if !reflect.DeepEqual(decoded, model) {
+			t.Fatalf("Round trip of %s\nexpected: %#v\ngot: %#v", data, model,
+				decoded)
+		}
+	}
+
+	data, _ := json.Marshal(port)
+	var raw map[string]interface{}
+	json.Unmarshal(data, &raw)
+	if raw["trunk_allowed_vlans"] != "1-4094" {
+		t.Fatalf("Expected compact vlan range, got %s", data)
+	}
+}
+
+func TestModelsConfig_UnitTest(t *testing.T) {
+	vlan := Vlan(dummyNode).Get("10")
+	typedVlan, _ := Vlan(dummyNode).GetTyped("10")
+	if got := typedVlan.Config(); !reflect.DeepEqual(got, vlan) {
+		t.Fatalf("Config() = %#v; want %#v", got, vlan)
+	}
+	port := SwitchPort(dummyNode).Get("Ethernet1")
+	typed, _ := SwitchPort(dummyNode).GetTyped("Ethernet1")
+	if got := typed.Config(); !reflect.DeepEqual(got, port) {
+		t.Fatalf("Config() = %#v; want %#v", got, port)
+	}
+	if _, err := newSwitchPortModel(SwitchPortConfig{"trunk_allowed_vlans": "10-bogus"}); err == nil {
+		t.Fatal("Expected malformed trunk_allowed_vlans to fail")
+	}
+	if _, err := newIPInterfaceModel(IPInterfaceConfig{"address": "1.1.1.1"}); err == nil {
+		t.Fatal("Expected malformed address to fail")
+	}
+	user := User(dummyNode).Get("test2")
+	typedUser, _ := User(dummyNode).GetTyped("test2")
+	if got := typedUser.Config(); !reflect.DeepEqual(got, user) {
+		t.Fatalf("Config() = %#v; want %#v", got, user)
+	}
+	if parseASN("1.10") != 65546 || parseASN("65001") != 65001 ||
+		parseASN("") != 0 {
+		t.Fatal("parseASN() failed")
+	}
+}
@@ -1,9 +1,11 @@
 package module
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/aristanetworks/goeapi"
 )
@@ -99,41 +101,61 @@ func (p *PTPEntity) Get() *PtpConfig {
 // command. Returns true (bool) if the commands complete
 // successfully
 func (p *PTPEntity) ConfigurePtp(cmd string) bool {
+	return p.ConfigurePtpE(cmd) == nil
+}
+
+// ConfigurePtpE is the error-returning variant of ConfigurePtp
+func (p *PTPEntity) ConfigurePtpE(cmd string) error {
 	config := p.Get()
 	if config == nil {
-		return false
+		return fmt.Errorf("PTP is not configured")
 	}
 	commands := []string{
 		cmd,
 	}
-	return p.Configure(commands...)
+	return p.ConfigureE(commands...)
 }
 
 // SetSourceIP configures the source ip using the provided value.
 // Returns true(bool) if the commands complete successfully
 func (p *PTPEntity) SetSourceIP(value string) bool {
+	return p.SetSourceIPE(value) == nil
+}
+
+// SetSourceIPE is the error-returning variant of SetSourceIP
+func (p *PTPEntity) SetSourceIPE(value string) error {
 	if value == "" {
-		return p.ConfigurePtp("no ptp source ip")
+		return p.ConfigurePtpE("no ptp source ip")
 	}
-	return p.ConfigurePtp("ptp source ip " + value)
+	return p.ConfigurePtpE("ptp source ip " + value)
 }
 
 // SetMode configures the ptp mode using the provided value.
 // Returns true(bool) if the commands complete successfully
 func (p *PTPEntity) SetMode(value string) bool {
+	return p.SetModeE(value) == nil
+}
+
+// SetModeE is the error-returning variant of SetMode
+func (p *PTPEntity) SetModeE(value string) error {
 	if value == "" {
-		return p.ConfigurePtp("no ptp mode")
+		return p.ConfigurePtpE("no ptp mode")
 	}
-	return p.ConfigurePtp("ptp mode " + value)
+	return p.ConfigurePtpE("ptp mode " + value)
 }
 
 // SetTTL configures the ptp ttl using the provided value.
 // Returns true(bool) if the commands complete successfully
 func (p *PTPEntity) SetTTL(value string) bool {
+	return p.SetTTLE(value) == nil
+}
+
+// SetTTLE is the error-returning variant of SetTTL
+func (p *PTPEntity) SetTTLE(value string) error {
 	if value == "" {
-		return p.ConfigurePtp("no ptp ttl")
+		return p.ConfigurePtpE("no ptp ttl")
 	}
-	return p.ConfigurePtp("ptp ttl " + value)
+	return p.ConfigurePtpE("ptp ttl " + value)
 }
 
 // parse parses the given PTP config for the give pattern value
@@ -198,15 +220,13 @@ func (p PTPInterfaceConfig) GetPTPAdminStatus() string {
 // GetAll returns a collection of PTPInterfaceConfigs key'd by
 // interface name.
 func (p *PTPInterfaceEntity) GetAll() PTPInterfaceCollection {
-	config := p.Config()
-
-	re := regexp.MustCompile(`(?m)^interface\s(Eth.+|Po.+)$`)
-	interfaces := re.FindAllStringSubmatch(config, -1)
 
 	collection := make(PTPInterfaceCollection)
 
-	for _, matchedLine := range interfaces {
-		intf := matchedLine[1]
+	for _, intf := range p.ConfigTree().Values("interface") {
+		if !strings.HasPrefix(intf, "Eth") && !strings.HasPrefix(intf, "Po") {
+			continue
+		}
 		if tmp := p.Get(intf); tmp != nil {
 			collection[intf] = tmp
 		}
@@ -244,12 +264,17 @@ func (p *PTPInterfaceEntity) parsePTPTransport(config string) string {
 // SetEnable enables(true) or disables(false) ptp for the interface
 // name(string). Returns true(bool) if configuration successful
 func (p *PTPInterfaceEntity) SetEnable(name string, enable bool) bool {
+	return p.SetEnableE(name, enable) == nil
+}
+
+// SetEnableE is the error-returning variant of SetEnable
+func (p *PTPInterfaceEntity) SetEnableE(name string, enable bool) error {
 	str := "no ptp enable"
 	if enable {
 		str = "ptp enable"
 	}
 	cmd := p.CommandBuilder(str, "", false, true)
-	return p.ConfigureInterface(name, cmd)
+	return p.ConfigureInterfaceE(name, cmd)
 }
 
 // ShowPTP represents "show ptp" output
@@ -286,19 +311,5 @@ func (b *ShowPTP) GetCmd() string {
 }
 
 func (s *ShowEntity) ShowPTP() (ShowPTP, error) {
-	var showptp ShowPTP
-	handle, err := s.node.GetHandle("json")
-	if err != nil {
-		return showptp, err
-	}
-	err = handle.AddCommand(&showptp)
-	if err != nil {
-		return showptp, err
-	}
-	err = handle.Call()
-	if err != nil {
-		return showptp, err
-	}
-	handle.Close()
-	return showptp, nil
+	return goeapi.Run[ShowPTP](context.Background(), s.node)
 }
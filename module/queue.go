@@ -32,7 +32,12 @@
 
 package module
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+
+	"github.com/aristanetworks/goeapi"
+)
 
 // ShowQueueMonitor represents "show queue-monitor length" output
 type ShowQueueMonitor struct {
@@ -83,45 +88,13 @@ func (l *ShowQueueMonitor) GetCmd() string {
 func (s *ShowEntity) ShowQueueMonitorWithLimit(port string, limitBy string, limitValue int) (ShowQueueMonitor, error) {
 	showqueuemonitor := ShowQueueMonitor{}
 	showqueuemonitor.SetCmd(port, limitBy, limitValue)
-
-	handle, err := s.node.GetHandle("json")
-	if err != nil {
-		return showqueuemonitor, err
-	}
-
-	err = handle.AddCommand(&showqueuemonitor)
-	if err != nil {
-		return showqueuemonitor, err
-	}
-
-	err = handle.Call()
-	if err != nil {
-		return showqueuemonitor, err
-	}
-
-	handle.Close()
-	return showqueuemonitor, nil
+	err := goeapi.RunMany(context.Background(), s.node, &showqueuemonitor)
+	return showqueuemonitor, err
 }
 
 func (s *ShowEntity) ShowQueueMonitor(port string) (ShowQueueMonitor, error) {
 	showqueuemonitor := ShowQueueMonitor{}
 	showqueuemonitor.SetCmd(port, "", 0)
-
-	handle, err := s.node.GetHandle("json")
-	if err != nil {
-		return showqueuemonitor, err
-	}
-
-	err = handle.AddCommand(&showqueuemonitor)
-	if err != nil {
-		return showqueuemonitor, err
-	}
-
-	err = handle.Call()
-	if err != nil {
-		return showqueuemonitor, err
-	}
-
-	handle.Close()
-	return showqueuemonitor, nil
+	err := goeapi.RunMany(context.Background(), s.node, &showqueuemonitor)
+	return showqueuemonitor, err
 }
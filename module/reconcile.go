@@ -0,0 +1,397 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package module
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aristanetworks/goeapi"
+)
+
+// DesiredState represents the intended configuration of the VLANs,
+// switchports and interfaces of a node, as used by Reconcile.
+//
+// Only the attributes present in each config are managed: an attribute
+// missing from a config is left unchanged, while an attribute set to ""
+// is reverted to its default value. The attributes are the keys returned
+// by the Get methods of the corresponding entities:
+//
+//	Vlans (keyed by VLAN ID): name, state, trunk_groups
+//	SwitchPorts (keyed by interface name): mode, access_vlan,
+//	    trunk_native_vlan, trunk_allowed_vlans, trunk_groups
+//	Interfaces (keyed by interface name): description, shutdown
+//
+// Trunk groups are given as a comma delimited list and shutdown as "true"
+// or "false".
+type DesiredState struct {
+	Vlans       VlanConfigMap
+	SwitchPorts SwitchPortConfigMap
+	Interfaces  map[string]InterfaceConfig
+}
+
+// ReconcileOptions controls how Reconcile applies a DesiredState
+type ReconcileOptions struct {
+	// DryRun computes the commands without sending them to the node
+	DryRun bool
+	// Purge removes the objects of the node that are not part of the
+	// DesiredState: unmanaged VLANs (except VLAN 1) are deleted, and
+	// unmanaged switchports as well as the description and shutdown state
+	// of unmanaged interfaces are defaulted. Interfaces themselves are
+	// never deleted and Management interfaces are never purged.
+	Purge bool
+}
+
+// vlanDefaults holds the default value of each VlanConfig attribute. The
+// default name depends on the VLAN ID, see vlanDefault.
+var vlanDefaults = VlanConfig{
+	"state":        "active",
+	"trunk_groups": "",
+}
+
+// switchPortDefaults holds the default value of each SwitchPortConfig
+// attribute.
+var switchPortDefaults = SwitchPortConfig{
+	"mode":                "access",
+	"access_vlan":         "1",
+	"trunk_native_vlan":   "1",
+	"trunk_allowed_vlans": "1-4094",
+	"trunk_groups":        "",
+}
+
+// switchPortCommands maps the SwitchPortConfig attributes to their
+// command, in the order they are configured.
+var switchPortCommands = []struct{ key, cmd string }{
+	{"mode", "switchport mode"},
+	{"access_vlan", "switchport access vlan"},
+	{"trunk_native_vlan", "switchport trunk native vlan"},
+	{"trunk_allowed_vlans", "switchport trunk allowed vlan"},
+}
+
+// Reconcile brings the VLANs, switchports and interfaces of node to the
+// desired state.
+//
+// The current state is read with the GetAll methods of the entities and
+// compared to desired to compute the commands creating, deleting and
+// configuring objects, which are then sent to the node in a single
+// request. VLANs are created first and deleted last so that switchports
+// can be moved between them.
+//
+// Reconcile returns the list of commands, which is empty if node is
+// already in the desired state, and an error if they failed to apply.
+// With opts.DryRun the commands are returned without being sent.
+func Reconcile(node *goeapi.Node, desired DesiredState,
+	opts ReconcileOptions) ([]string, error) {
+	if node == nil {
+		return nil, fmt.Errorf("Invalid node")
+	}
+	for vid := range desired.Vlans {
+		if !isVlan(vid) {
+			return nil, fmt.Errorf("Invalid vlan: %s", vid)
+		}
+	}
+
+	vlanCmds, vlanDeletes := reconcileVlans(Vlan(node), desired.Vlans,
+		opts.Purge)
+	commands := vlanCmds
+	commands = append(commands,
+		reconcileInterfaces(Interface(node), desired, opts.Purge)...)
+	commands = append(commands,
+		reconcileSwitchPorts(SwitchPort(node), desired.SwitchPorts,
+			opts.Purge)...)
+	commands = append(commands, vlanDeletes...)
+
+	if opts.DryRun || len(commands) == 0 {
+		return commands, nil
+	}
+	return commands, node.ConfigWithErr(commands...)
+}
+
+// reconcileVlans returns the commands creating and configuring the
+// desired VLANs, and separately the commands deleting unmanaged VLANs if
+// purge is set.
+func reconcileVlans(v *VlanEntity, desired VlanConfigMap,
+	purge bool) ([]string, []string) {
+	var commands, deletes []string
+	current := v.GetAll()
+
+	for _, vid := range sortedVlans(desired) {
+		config := current[vid]
+		if config == nil {
+			config = vlanDefault(vid)
+		}
+		var cmds []string
+		want := desired[vid]
+		for _, key := range []string{"name", "state"} {
+			value, ok := want[key]
+			if !ok {
+				continue
+			}
+			if value == "" {
+				value = vlanDefault(vid)[key]
+			}
+			if value != config[key] {
+				cmds = append(cmds, setting(key, want[key]))
+			}
+		}
+		if groups, ok := want["trunk_groups"]; ok {
+			cmds = append(cmds, listChanges("trunk group",
+				config["trunk_groups"], groups)...)
+		}
+		if len(cmds) > 0 || current[vid] == nil {
+			commands = append(commands, "vlan "+vid)
+			commands = append(commands, cmds...)
+			commands = append(commands, "exit")
+		}
+	}
+
+	if purge {
+		for _, vid := range sortedVlans(current) {
+			if _, ok := desired[vid]; !ok && vid != "1" {
+				deletes = append(deletes, "no vlan "+vid)
+			}
+		}
+	}
+	return commands, deletes
+}
+
+// reconcileInterfaces returns the commands configuring the desired
+// interfaces and purging unmanaged interfaces if purge is set.
+func reconcileInterfaces(i *BaseInterfaceEntity, desired DesiredState,
+	purge bool) []string {
+	var commands []string
+	tree := i.ConfigTree()
+
+	for _, name := range sortedKeys(desired.Interfaces) {
+		want := desired.Interfaces[name]
+		exists := tree.Child("interface "+name) != nil
+		config := InterfaceConfig{"description": "", "shutdown": "false"}
+		if exists {
+			config = i.Get(name)
+		}
+		var cmds []string
+		if value, ok := want["description"]; ok && value != config["description"] {
+			cmds = append(cmds, setting("description", value))
+		}
+		if value, ok := want["shutdown"]; ok {
+			cmd := shutdownCommand(value)
+			if value == "" {
+				value = "false"
+			}
+			if value != config["shutdown"] {
+				cmds = append(cmds, cmd)
+			}
+		}
+		if len(cmds) > 0 || !exists {
+			commands = append(commands, "interface "+name)
+			commands = append(commands, cmds...)
+			commands = append(commands, "exit")
+		}
+	}
+
+	if !purge {
+		return commands
+	}
+	for _, name := range tree.Values("interface") {
+		if _, ok := desired.Interfaces[name]; ok ||
+			strings.HasPrefix(name, "Management") {
+			continue
+		}
+		config := i.Get(name)
+		var cmds []string
+		if config["description"] != "" {
+			cmds = append(cmds, "default description")
+		}
+		if config["shutdown"] != "false" {
+			cmds = append(cmds, "default shutdown")
+		}
+		if len(cmds) > 0 {
+			commands = append(commands, "interface "+name)
+			commands = append(commands, cmds...)
+			commands = append(commands, "exit")
+		}
+	}
+	return commands
+}
+
+// reconcileSwitchPorts returns the commands configuring the desired
+// switchports and defaulting unmanaged switchports if purge is set.
+func reconcileSwitchPorts(s *SwitchPortEntity, desired SwitchPortConfigMap,
+	purge bool) []string {
+	var commands []string
+	current := s.GetAll()
+
+	for _, name := range sortedKeys(desired) {
+		want := desired[name]
+		config := current[name]
+		var cmds []string
+		if config == nil {
+			config = switchPortDefaults
+			cmds = append(cmds, "no ip address", "switchport")
+		}
+		for _, attr := range switchPortCommands {
+			value, ok := want[attr.key]
+			if !ok {
+				continue
+			}
+			if value == "" {
+				value = switchPortDefaults[attr.key]
+			}
+			if !sameSwitchPortValue(attr.key, value, config[attr.key]) {
+				cmds = append(cmds, setting(attr.cmd, want[attr.key]))
+			}
+		}
+		if groups, ok := want["trunk_groups"]; ok {
+			cmds = append(cmds, listChanges("switchport trunk group",
+				config["trunk_groups"], groups)...)
+		}
+		if len(cmds) > 0 {
+			commands = append(commands, "interface "+name)
+			commands = append(commands, cmds...)
+			commands = append(commands, "exit")
+		}
+	}
+
+	if !purge {
+		return commands
+	}
+	for _, name := range sortedKeys(current) {
+		if _, ok := desired[name]; ok || isDefaultSwitchPort(current[name]) {
+			continue
+		}
+		commands = append(commands, "interface "+name, "default switchport",
+			"exit")
+	}
+	return commands
+}
+
+// sameSwitchPortValue returns true if a and b are the same value of the
+// SwitchPortConfig attribute key. VLAN lists are compared as sets so that
+// e.g. "1-3" and "1,2,3" are equal.
+func sameSwitchPortValue(key string, a string, b string) bool {
+	if key == "trunk_allowed_vlans" {
+		setA, errA := ParseVlanSet(a)
+		setB, errB := ParseVlanSet(b)
+		if errA == nil && errB == nil {
+			return setA == setB
+		}
+	}
+	return a == b
+}
+
+// vlanDefault returns the VlanConfig of a newly created VLAN
+func vlanDefault(vid string) VlanConfig {
+	id, _ := strconv.Atoi(vid)
+	config := VlanConfig{"name": fmt.Sprintf("VLAN%04d", id)}
+	if id == 1 {
+		config["name"] = "default"
+	}
+	for key, value := range vlanDefaults {
+		config[key] = value
+	}
+	return config
+}
+
+// isDefaultSwitchPort returns true if none of the attributes of config
+// differ from their default value.
+func isDefaultSwitchPort(config SwitchPortConfig) bool {
+	for key, value := range switchPortDefaults {
+		if config[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// setting returns the command setting cmd to value, or its default form
+// if value is empty.
+func setting(cmd string, value string) string {
+	if value == "" {
+		return "default " + cmd
+	}
+	return cmd + " " + value
+}
+
+// shutdownCommand returns the command for the shutdown attribute value
+func shutdownCommand(value string) string {
+	switch value {
+	case "true":
+		return "shutdown"
+	case "false":
+		return "no shutdown"
+	}
+	return "default shutdown"
+}
+
+// listChanges returns the commands adding and removing the values of the
+// multi-valued setting cmd to go from the comma delimited list current to
+// the comma delimited list desired.
+func listChanges(cmd string, current string, desired string) []string {
+	var commands []string
+	want := strings.Split(desired, ",")
+	have := strings.Split(current, ",")
+	for _, value := range findDiff(want, have) {
+		commands = append(commands, cmd+" "+value)
+	}
+	for _, value := range findDiff(have, want) {
+		commands = append(commands, "no "+cmd+" "+value)
+	}
+	return commands
+}
+
+// sortedVlans returns the VLAN IDs of vlans in numerical order
+func sortedVlans(vlans VlanConfigMap) []string {
+	var vids []string
+	for vid := range vlans {
+		vids = append(vids, vid)
+	}
+	sort.Slice(vids, func(i, j int) bool {
+		a, _ := strconv.Atoi(vids[i])
+		b, _ := strconv.Atoi(vids[j])
+		return a < b
+	})
+	return vids
+}
+
+// sortedKeys returns the keys of configs, which is a map keyed by
+// interface name, in order.
+func sortedKeys[T any](configs map[string]T) []string {
+	var keys []string
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,144 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package module
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReconcileNoChange_UnitTest(t *testing.T) {
+	desired := DesiredState{
+		Vlans: VlanConfigMap{
+			"10": VlanConfig{"name": "", "state": "active",
+				"trunk_groups": "tg1"},
+		},
+		SwitchPorts: SwitchPortConfigMap{
+			"Ethernet1": SwitchPortConfig{"mode": "access",
+				"trunk_allowed_vlans": "all", "trunk_groups": "bar,foo"},
+		},
+		Interfaces: map[string]InterfaceConfig{
+			"Ethernet1": InterfaceConfig{"description": "",
+				"shutdown": "false"},
+		},
+	}
+	commands, err := Reconcile(dummyNode, desired, ReconcileOptions{})
+	if err != nil {
+		t.Fatalf("Reconcile() failed: %s", err)
+	}
+	if len(commands) != 0 {
+		t.Fatalf("Reconcile() expected no commands, got %#v", commands)
+	}
+}
+
+func TestReconcile_UnitTest(t *testing.T) {
+	desired := DesiredState{
+		Vlans: VlanConfigMap{
+			"100": VlanConfig{"name": ""},
+			"20":  VlanConfig{"name": "web", "trunk_groups": "tg2"},
+		},
+		SwitchPorts: SwitchPortConfigMap{
+			"Ethernet1": SwitchPortConfig{"mode": "trunk",
+				"trunk_allowed_vlans": "20,100", "trunk_groups": "foo,baz"},
+		},
+		Interfaces: map[string]InterfaceConfig{
+			"Ethernet1": InterfaceConfig{"description": "uplink"},
+			"Ethernet2": InterfaceConfig{"shutdown": "true"},
+		},
+	}
+	want := []string{
+		"vlan 20", "name web", "trunk group tg2", "exit",
+		"vlan 100", "default name", "exit",
+		"interface Ethernet1", "description uplink", "exit",
+		"interface Ethernet2", "shutdown", "exit",
+		"interface Ethernet1", "switchport mode trunk",
+		"switchport trunk allowed vlan 20,100",
+		"switchport trunk group baz", "no switchport trunk group bar",
+		"exit",
+	}
+
+	commands, err := Reconcile(dummyNode, desired,
+		ReconcileOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Reconcile() failed: %s", err)
+	}
+	if !reflect.DeepEqual(commands, want) {
+		t.Fatalf("Reconcile() dry-run\nexpected: %#v\ngot: %#v", want, commands)
+	}
+
+	commands, err = Reconcile(dummyNode, desired, ReconcileOptions{})
+	if err != nil {
+		t.Fatalf("Reconcile() failed: %s", err)
+	}
+	if !reflect.DeepEqual(commands, want) {
+		t.Fatalf("Reconcile()\nexpected: %#v\ngot: %#v", want, commands)
+	}
+	sent := dummyConnection.GetCommands()[2:]
+	for idx, cmd := range want {
+		if sent[idx] != cmd {
+			t.Fatalf("Reconcile() sent %#v, expected %#v", sent, want)
+		}
+	}
+}
+
+func TestReconcilePurge_UnitTest(t *testing.T) {
+	desired := DesiredState{
+		Vlans: VlanConfigMap{
+			"10": VlanConfig{},
+		},
+	}
+	commands, err := Reconcile(dummyNode, desired,
+		ReconcileOptions{DryRun: true, Purge: true})
+	if err != nil {
+		t.Fatalf("Reconcile() failed: %s", err)
+	}
+	want := []string{"no vlan 100", "no vlan 300"}
+	if got := commands[len(commands)-2:]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Reconcile() expected %#v last, got %#v", want, commands)
+	}
+	for _, cmd := range commands {
+		if cmd == "no vlan 1" || cmd == "no vlan 10" ||
+			cmd == "interface Management1" {
+			t.Fatalf("Reconcile() should not purge: %q", cmd)
+		}
+	}
+}
+
+func TestReconcileInvalidVlan_UnitTest(t *testing.T) {
+	desired := DesiredState{
+		Vlans: VlanConfigMap{"4095": VlanConfig{}},
+	}
+	if _, err := Reconcile(dummyNode, desired, ReconcileOptions{}); err == nil {
+		t.Fatalf("Reconcile() expected error for invalid vlan")
+	}
+}
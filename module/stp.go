@@ -35,6 +35,7 @@ package module
 import (
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/aristanetworks/goeapi"
 )
@@ -77,13 +78,18 @@ func (s *STPEntity) Instances() *STPInstanceEntity {
 // Returns:
 //  bool: Returns True if the commands complete successfully
 func (s *STPEntity) SetMode(value string) bool {
+	return s.SetModeE(value) == nil
+}
+
+// SetModeE is the error-returning variant of SetMode
+func (s *STPEntity) SetModeE(value string) error {
 	if value == "" {
-		return s.Configure("no spanning-tree mode")
+		return s.ConfigureE("no spanning-tree mode")
 	}
 	if value != "mstp" && value != "none" {
-		return false
+		return invalid("spanning-tree mode", value, "must be mstp or none")
 	}
-	return s.Configure("spanning-tree mode " + value)
+	return s.ConfigureE("spanning-tree mode " + value)
 }
 
 // STPInstanceEntity provides a configuration resource for STPInstance
@@ -146,15 +152,13 @@ func (s *STPInterfaceEntity) Get(name string) STPInterfaceConfig {
 // GetAll returns a collection of STPInterfaceConfigs key'd by
 // interface name.
 func (s *STPInterfaceEntity) GetAll() STPInterfaceCollection {
-	config := s.Config()
-
-	re := regexp.MustCompile(`(?m)^interface\s(Eth.+|Po.+)$`)
-	interfaces := re.FindAllStringSubmatch(config, -1)
 
 	collection := make(STPInterfaceCollection)
 
-	for _, matchedLine := range interfaces {
-		intf := matchedLine[1]
+	for _, intf := range s.ConfigTree().Values("interface") {
+		if !strings.HasPrefix(intf, "Eth") && !strings.HasPrefix(intf, "Po") {
+			continue
+		}
 		if tmp := s.Get(intf); tmp != nil {
 			collection[intf] = tmp
 		}
@@ -193,10 +197,16 @@ func (s *STPInterfaceEntity) parsePortfastType(config string) string {
 // ConfigureInterface (redefined from Base)
 // Returns true(bool) if configuration successful
 func (s *STPInterfaceEntity) ConfigureInterface(name string, cmds ...string) bool {
+	return s.ConfigureInterfaceE(name, cmds...) == nil
+}
+
+// ConfigureInterfaceE is the error-returning variant of ConfigureInterface
+func (s *STPInterfaceEntity) ConfigureInterfaceE(name string, cmds ...string) error {
 	if !isValidStpInterface(name) {
-		return false
+		return invalid("interface", name,
+			"must be an Ethernet or Port-Channel interface")
 	}
-	return s.AbstractBaseEntity.ConfigureInterface(name, cmds...)
+	return s.AbstractBaseEntity.ConfigureInterfaceE(name, cmds...)
 }
 
 // SetPortfastType sets the spanning-tree portfast type for the interface name(string) to
@@ -206,52 +216,78 @@ func (s *STPInterfaceEntity) ConfigureInterface(name string, cmds ...string) boo
 //	normal
 // Returns true(bool) if configuration successful
 func (s *STPInterfaceEntity) SetPortfastType(name string, value string) bool {
+	return s.SetPortfastTypeE(name, value) == nil
+}
+
+// SetPortfastTypeE is the error-returning variant of SetPortfastType
+func (s *STPInterfaceEntity) SetPortfastTypeE(name string, value string) error {
 	validTypes := map[string]bool{
 		"network": true,
 		"edge":    true,
 		"normal":  true,
 	}
 	if _, found := validTypes[value]; !found {
-		return false
+		return invalid("portfast type", value,
+			"must be network, edge or normal")
 	}
 
 	cmds := []string{"spanning-tree portfast " + value}
 	if value == "edge" {
 		cmds = append(cmds, "spanning-tree portfast auto")
 	}
-	return s.ConfigureInterface(name, cmds...)
+	return s.ConfigureInterfaceE(name, cmds...)
 }
 
 // SetPortfast sets the spanning-tree portfast for the interface name(string) to
 // be enabled(true) or disabled(false). Returns true(bool) if configuration successful
 func (s *STPInterfaceEntity) SetPortfast(name string, enable bool) bool {
+	return s.SetPortfastE(name, enable) == nil
+}
+
+// SetPortfastE is the error-returning variant of SetPortfast
+func (s *STPInterfaceEntity) SetPortfastE(name string, enable bool) error {
 	cmds := s.CommandBuilder("spanning-tree portfast", "", false, enable)
-	return s.ConfigureInterface(name, cmds)
+	return s.ConfigureInterfaceE(name, cmds)
 }
 
 // SetPortfastDefault sets the spanning-tree portfast for the interface name(string)
 // back to the default config. Returns true(bool) if configuration successful
 func (s *STPInterfaceEntity) SetPortfastDefault(name string) bool {
+	return s.SetPortfastDefaultE(name) == nil
+}
+
+// SetPortfastDefaultE is the error-returning variant of SetPortfastDefault
+func (s *STPInterfaceEntity) SetPortfastDefaultE(name string) error {
 	cmd := s.CommandBuilder("spanning-tree portfast", "", true, false)
-	return s.ConfigureInterface(name, cmd)
+	return s.ConfigureInterfaceE(name, cmd)
 }
 
 // SetBPDUGuard eables(true) or disables(false) spanning-tree bpduguard for the
 // interface name(string). Returns true(bool) if configuration successful
 func (s *STPInterfaceEntity) SetBPDUGuard(name string, enable bool) bool {
+	return s.SetBPDUGuardE(name, enable) == nil
+}
+
+// SetBPDUGuardE is the error-returning variant of SetBPDUGuard
+func (s *STPInterfaceEntity) SetBPDUGuardE(name string, enable bool) error {
 	param := "disable"
 	if enable {
 		param = "enable"
 	}
 	cmd := s.CommandBuilder("spanning-tree bpduguard", param, false, true)
-	return s.ConfigureInterface(name, cmd)
+	return s.ConfigureInterfaceE(name, cmd)
 }
 
 // SetBPDUGuardDefault sets the spanning-tree bpduguard for the interface name(string)
 // back to the default config. Returns true(bool) if configuration successful
 func (s *STPInterfaceEntity) SetBPDUGuardDefault(name string) bool {
+	return s.SetBPDUGuardDefaultE(name) == nil
+}
+
+// SetBPDUGuardDefaultE is the error-returning variant of SetBPDUGuardDefault
+func (s *STPInterfaceEntity) SetBPDUGuardDefaultE(name string) error {
 	cmd := s.CommandBuilder("spanning-tree bpduguard", "", true, false)
-	return s.ConfigureInterface(name, cmd)
+	return s.ConfigureInterfaceE(name, cmd)
 }
 
 // isValidStpInterface
@@ -33,6 +33,7 @@
 package module
 
 import (
+	"errors"
 	"regexp"
 	"strings"
 
@@ -112,14 +113,11 @@ func SwitchPort(node *goeapi.Node) *SwitchPortEntity {
 //        the specified argument is not a switchport then None
 //        is returned
 func (s *SwitchPortEntity) Get(name string) SwitchPortConfig {
-	parent := "interface " + name
-	config, err := s.GetBlock(parent)
-	if err != nil {
-		return nil
-	}
-	if matched, _ := regexp.MatchString(`no switchport\s*\n`, config); matched {
+	section := s.ConfigTree().Child("interface " + name)
+	if section == nil || section.Child("no switchport") != nil {
 		return nil
 	}
+	config := section.String()
 	return SwitchPortConfig{
 		"name":                name,
 		"mode":                s.parseMode(config),
@@ -138,15 +136,12 @@ func (s *SwitchPortEntity) Get(name string) SwitchPortConfig {
 //    A map'd SwitchPort that represents all configured
 //        switchports in the current running configuration
 func (s *SwitchPortEntity) GetAll() SwitchPortConfigMap {
-	config := s.Config()
-
-	re := regexp.MustCompile(`(?m)^interface\s([Et|Po].+)$`)
-	matches := re.FindAllStringSubmatch(config, -1)
-
 	response := make(SwitchPortConfigMap)
 
-	for _, line := range matches {
-		name := line[1]
+	for _, name := range s.ConfigTree().Values("interface") {
+		if !strings.HasPrefix(name, "Et") && !strings.HasPrefix(name, "Po") {
+			continue
+		}
 		intf := s.Get(name)
 		if intf != nil {
 			response[name] = intf
@@ -163,12 +158,11 @@ func (s *SwitchPortEntity) GetAll() SwitchPortConfigMap {
 // Returns:
 //  Returns string representation of SwitchPort config entry
 func (s *SwitchPortEntity) GetSection(name string) string {
-	parent := "interface " + name
-	config, err := s.GetBlock(parent)
-	if err != nil {
+	section := s.ConfigTree().Child("interface " + name)
+	if section == nil {
 		return ""
 	}
-	return config
+	return section.String()
 }
 
 // parseMode Scans the specified config and parses the switchport mode value
@@ -279,11 +273,16 @@ func (s *SwitchPortEntity) parseTrunkAllowedVlans(config string) string {
 //        interface specified in args is already a switchport then this
 //        method will have no effect but will still return True
 func (s *SwitchPortEntity) Create(name string) bool {
+	return s.CreateE(name) == nil
+}
+
+// CreateE is the error-returning variant of Create
+func (s *SwitchPortEntity) CreateE(name string) error {
 	var commands = []string{"interface " + name,
 		"no ip address",
 		"switchport",
 	}
-	return s.Configure(commands...)
+	return s.ConfigureE(commands...)
 }
 
 // Delete Deletes the logical layer 2 interface
@@ -302,10 +301,15 @@ func (s *SwitchPortEntity) Create(name string) bool {
 //        interface specified in args is already a switchport then this
 //        method will have no effect but will still return True
 func (s *SwitchPortEntity) Delete(name string) bool {
+	return s.DeleteE(name) == nil
+}
+
+// DeleteE is the error-returning variant of Delete
+func (s *SwitchPortEntity) DeleteE(name string) error {
 	var commands = []string{"interface " + name,
 		"no switchport",
 	}
-	return s.Configure(commands...)
+	return s.ConfigureE(commands...)
 }
 
 // Default Defaults the configuration of the switchport interface
@@ -323,11 +327,16 @@ func (s *SwitchPortEntity) Delete(name string) bool {
 //        interface specified in args is already a switchport then this
 //        method will have no effect but will still return True
 func (s *SwitchPortEntity) Default(name string) bool {
+	return s.DefaultE(name) == nil
+}
+
+// DefaultE is the error-returning variant of Default
+func (s *SwitchPortEntity) DefaultE(name string) error {
 	var commands = []string{"interface " + name,
 		"no ip address",
 		"default switchport",
 	}
-	return s.Configure(commands...)
+	return s.ConfigureE(commands...)
 }
 
 // SetMode Configures the switchport mode
@@ -345,8 +354,13 @@ func (s *SwitchPortEntity) Default(name string) bool {
 // Returns:
 //    True if the create operation succeeds otherwise False.
 func (s *SwitchPortEntity) SetMode(name string, value string) bool {
+	return s.SetModeE(name, value) == nil
+}
+
+// SetModeE is the error-returning variant of SetMode
+func (s *SwitchPortEntity) SetModeE(name string, value string) error {
 	command := s.CommandBuilder("switchport mode", value, false, true)
-	return s.ConfigureInterface(name, command)
+	return s.ConfigureInterfaceE(name, command)
 }
 
 // SetModeDefault Configures the switchport mode
@@ -360,7 +374,12 @@ func (s *SwitchPortEntity) SetMode(name string, value string) bool {
 // Returns:
 //    True if the create operation succeeds otherwise False.
 func (s *SwitchPortEntity) SetModeDefault(name string) bool {
-	return s.ConfigureInterface(name, "default switchport mode")
+	return s.SetModeDefaultE(name) == nil
+}
+
+// SetModeDefaultE is the error-returning variant of SetModeDefault
+func (s *SwitchPortEntity) SetModeDefaultE(name string) error {
+	return s.ConfigureInterfaceE(name, "default switchport mode")
 }
 
 // SetAccessVlan Configures the switchport access vlan
@@ -380,6 +399,16 @@ func (s *SwitchPortEntity) SetAccessVlan(name string, value string) bool {
 	return s.ConfigureInterface(name, command)
 }
 
+// SetAccessVlanE is the error-returning variant of SetAccessVlan. Unlike
+// SetAccessVlan, value is validated before anything is sent.
+func (s *SwitchPortEntity) SetAccessVlanE(name string, value string) error {
+	if !isVlan(value) {
+		return invalid("vlan", value, "must be in the range 1 to 4094")
+	}
+	command := s.CommandBuilder("switchport access vlan", value, false, true)
+	return s.ConfigureInterfaceE(name, command)
+}
+
 // SetAccessVlanDefault Configures the default switchport access vlan
 //
 // Args:
@@ -391,7 +420,12 @@ func (s *SwitchPortEntity) SetAccessVlan(name string, value string) bool {
 // Returns:
 //    True if the create operation succeeds otherwise False.
 func (s *SwitchPortEntity) SetAccessVlanDefault(name string) bool {
-	return s.ConfigureInterface(name, "default switchport access vlan")
+	return s.SetAccessVlanDefaultE(name) == nil
+}
+
+// SetAccessVlanDefaultE is the error-returning variant of SetAccessVlanDefault
+func (s *SwitchPortEntity) SetAccessVlanDefaultE(name string) error {
+	return s.ConfigureInterfaceE(name, "default switchport access vlan")
 }
 
 // SetTrunkNativeVlan Configures the switchport trunk native vlan value
@@ -411,6 +445,17 @@ func (s *SwitchPortEntity) SetTrunkNativeVlan(name string, value string) bool {
 	return s.ConfigureInterface(name, command)
 }
 
+// SetTrunkNativeVlanE is the error-returning variant of
+// SetTrunkNativeVlan. Unlike SetTrunkNativeVlan, value is validated before
+// anything is sent.
+func (s *SwitchPortEntity) SetTrunkNativeVlanE(name string, value string) error {
+	if !isVlan(value) {
+		return invalid("vlan", value, "must be in the range 1 to 4094")
+	}
+	command := s.CommandBuilder("switchport trunk native vlan", value, false, true)
+	return s.ConfigureInterfaceE(name, command)
+}
+
 // SetTrunkNativeVlanDefault Configures the default switchport trunk native
 // vlan value
 //
@@ -423,7 +468,12 @@ func (s *SwitchPortEntity) SetTrunkNativeVlan(name string, value string) bool {
 // Returns:
 //    True if the operation succeeds otherwise False.
 func (s *SwitchPortEntity) SetTrunkNativeVlanDefault(name string) bool {
-	return s.ConfigureInterface(name, "default switchport trunk native vlan")
+	return s.SetTrunkNativeVlanDefaultE(name) == nil
+}
+
+// SetTrunkNativeVlanDefaultE is the error-returning variant of SetTrunkNativeVlanDefault
+func (s *SwitchPortEntity) SetTrunkNativeVlanDefaultE(name string) error {
+	return s.ConfigureInterfaceE(name, "default switchport trunk native vlan")
 }
 
 // SetTrunkAllowedVlans Configures the switchport trunk allowed vlans value
@@ -437,10 +487,53 @@ func (s *SwitchPortEntity) SetTrunkNativeVlanDefault(name string) bool {
 //        value must be a valid VLAN ID in the range of 1 to 4094.
 //
 // Returns:
-//    True if the create operation succeeds otherwise False.
+//    True if the create operation succeeds otherwise False.  Unlike
+//    SetTrunkAllowedVlansE, value is sent to the node without being
+//    validated first.
 func (s *SwitchPortEntity) SetTrunkAllowedVlans(name string, value string) bool {
+	return s.setTrunkAllowedVlans(name, value) == nil
+}
+
+// SetTrunkAllowedVlansE is the error-returning variant of
+// SetTrunkAllowedVlans. value is validated before anything is sent: it
+// must be a list of VLAN IDs and ranges in the range 1 to 4094, each
+// optionally preceded by one of the keywords add, remove or except (e.g.
+// "10,20-30", "add 10" or "1-10,add 30").
+func (s *SwitchPortEntity) SetTrunkAllowedVlansE(name string, value string) error {
+	if !isTrunkAllowedVlans(value) {
+		return invalid("vlans", value, "must be a list of vlans in the range 1 to 4094")
+	}
+	return s.setTrunkAllowedVlans(name, value)
+}
+
+// setTrunkAllowedVlans configures the switchport trunk allowed vlans value
+func (s *SwitchPortEntity) setTrunkAllowedVlans(name string, value string) error {
 	command := s.CommandBuilder("switchport trunk allowed vlan", value, false, true)
-	return s.ConfigureInterface(name, command)
+	return s.ConfigureInterfaceE(name, command)
+}
+
+// isTrunkAllowedVlans returns true if value is a valid argument of the
+// switchport trunk allowed vlan command
+func isTrunkAllowedVlans(value string) bool {
+	if strings.TrimSpace(value) == "" {
+		return false
+	}
+	for _, elem := range strings.Split(value, ",") {
+		elem = strings.TrimSpace(elem)
+		for _, keyword := range []string{"add ", "remove ", "except "} {
+			if strings.HasPrefix(elem, keyword) {
+				elem = strings.TrimSpace(strings.TrimPrefix(elem, keyword))
+				break
+			}
+		}
+		if elem == "" {
+			return false
+		}
+		if _, err := ParseVlanSet(elem); err != nil {
+			return false
+		}
+	}
+	return true
 }
 
 // SetTrunkAllowedVlansDefault Configures the default switchport trunk allowed
@@ -454,7 +547,60 @@ func (s *SwitchPortEntity) SetTrunkAllowedVlans(name string, value string) bool
 // Returns:
 //    True if the create operation succeeds otherwise False.
 func (s *SwitchPortEntity) SetTrunkAllowedVlansDefault(name string) bool {
-	return s.ConfigureInterface(name, "default switchport trunk allowed vlan")
+	return s.SetTrunkAllowedVlansDefaultE(name) == nil
+}
+
+// SetTrunkAllowedVlansDefaultE is the error-returning variant of SetTrunkAllowedVlansDefault
+func (s *SwitchPortEntity) SetTrunkAllowedVlansDefaultE(name string) error {
+	return s.ConfigureInterfaceE(name, "default switchport trunk allowed vlan")
+}
+
+// AddTrunkAllowedVlans Adds vlans to the switchport trunk allowed vlans,
+// leaving the VLANs already allowed unchanged
+//
+// Args:
+//    name (string): The interface identifier to configure.  The name
+//        must be the full interface name (eg Ethernet1, not Et1)
+//    vlans (VlanSet): The VLANs to add to the allowed list
+//
+// Returns:
+//    True if the operation succeeds otherwise False.  False is also
+//    returned if vlans is empty.
+func (s *SwitchPortEntity) AddTrunkAllowedVlans(name string, vlans VlanSet) bool {
+	return s.AddTrunkAllowedVlansE(name, vlans) == nil
+}
+
+// AddTrunkAllowedVlansE is the error-returning variant of AddTrunkAllowedVlans
+func (s *SwitchPortEntity) AddTrunkAllowedVlansE(name string, vlans VlanSet) error {
+	if vlans.IsEmpty() {
+		return invalid("vlans", vlans, "must not be empty")
+	}
+	return s.ConfigureInterfaceE(name, "switchport trunk allowed vlan add "+
+		vlans.String())
+}
+
+// RemoveTrunkAllowedVlans Removes vlans from the switchport trunk allowed
+// vlans, leaving the other VLANs allowed unchanged
+//
+// Args:
+//    name (string): The interface identifier to configure.  The name
+//        must be the full interface name (eg Ethernet1, not Et1)
+//    vlans (VlanSet): The VLANs to remove from the allowed list
+//
+// Returns:
+//    True if the operation succeeds otherwise False.  False is also
+//    returned if vlans is empty.
+func (s *SwitchPortEntity) RemoveTrunkAllowedVlans(name string, vlans VlanSet) bool {
+	return s.RemoveTrunkAllowedVlansE(name, vlans) == nil
+}
+
+// RemoveTrunkAllowedVlansE is the error-returning variant of RemoveTrunkAllowedVlans
+func (s *SwitchPortEntity) RemoveTrunkAllowedVlansE(name string, vlans VlanSet) error {
+	if vlans.IsEmpty() {
+		return invalid("vlans", vlans, "must not be empty")
+	}
+	return s.ConfigureInterfaceE(name, "switchport trunk allowed vlan remove "+
+		vlans.String())
 }
 
 // SetTrunkGroups Configures the switchport trunk group value
@@ -466,24 +612,29 @@ func (s *SwitchPortEntity) SetTrunkAllowedVlansDefault(name string) bool {
 // Returns:
 //    True if the config operation succeeds otherwise False
 func (s *SwitchPortEntity) SetTrunkGroups(intf string, value []string) bool {
-	var failure = false
+	return s.SetTrunkGroupsE(intf, value) == nil
+}
+
+// SetTrunkGroupsE is the error-returning variant of SetTrunkGroups
+func (s *SwitchPortEntity) SetTrunkGroupsE(intf string, value []string) error {
+	var errs []error
 
 	currentValue := strings.Split(s.Get(intf)["trunk_groups"], ",")
 
 	diff := findDiff(value, currentValue)
 	for _, name := range diff {
-		if ok := s.AddTrunkGroup(intf, name); !ok {
-			failure = true
+		if err := s.AddTrunkGroupE(intf, name); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
 	diff = findDiff(currentValue, value)
 	for _, name := range diff {
-		if ok := s.RemoveTrunkGroup(intf, name); !ok {
-			failure = true
+		if err := s.RemoveTrunkGroupE(intf, name); err != nil {
+			errs = append(errs, err)
 		}
 	}
-	return !failure
+	return errors.Join(errs...)
 }
 
 // SetTrunkGroupsDefault Configures default switchport trunk group value
@@ -494,7 +645,12 @@ func (s *SwitchPortEntity) SetTrunkGroups(intf string, value []string) bool {
 // Returns:
 //    True if the config operation succeeds otherwise False
 func (s *SwitchPortEntity) SetTrunkGroupsDefault(intf string) bool {
-	return s.ConfigureInterface(intf, "default switchport trunk group")
+	return s.SetTrunkGroupsDefaultE(intf) == nil
+}
+
+// SetTrunkGroupsDefaultE is the error-returning variant of SetTrunkGroupsDefault
+func (s *SwitchPortEntity) SetTrunkGroupsDefaultE(intf string) error {
+	return s.ConfigureInterfaceE(intf, "default switchport trunk group")
 }
 
 // AddTrunkGroup Adds the specified trunk group to the interface
@@ -506,8 +662,13 @@ func (s *SwitchPortEntity) SetTrunkGroupsDefault(intf string) bool {
 // Returns:
 //    True if the operation as successfully applied otherwise false
 func (s *SwitchPortEntity) AddTrunkGroup(intf string, value string) bool {
+	return s.AddTrunkGroupE(intf, value) == nil
+}
+
+// AddTrunkGroupE is the error-returning variant of AddTrunkGroup
+func (s *SwitchPortEntity) AddTrunkGroupE(intf string, value string) error {
 	str := "switchport trunk group " + value
-	return s.ConfigureInterface(intf, str)
+	return s.ConfigureInterfaceE(intf, str)
 }
 
 // RemoveTrunkGroup Removes a specified trunk group to the interface
@@ -519,6 +680,11 @@ func (s *SwitchPortEntity) AddTrunkGroup(intf string, value string) bool {
 // Returns:
 //    True if the operation as successfully applied otherwise false
 func (s *SwitchPortEntity) RemoveTrunkGroup(intf string, value string) bool {
+	return s.RemoveTrunkGroupE(intf, value) == nil
+}
+
+// RemoveTrunkGroupE is the error-returning variant of RemoveTrunkGroup
+func (s *SwitchPortEntity) RemoveTrunkGroupE(intf string, value string) error {
 	str := "no switchport trunk group " + value
-	return s.ConfigureInterface(intf, str)
+	return s.ConfigureInterfaceE(intf, str)
 }
@@ -537,6 +537,45 @@ func TestSwitchPortSetTrunkAllowedVlans_UnitTest(t *testing.T) {
 	}
 }
 
+func TestSwitchPortSetTrunkAllowedVlansE_UnitTest(t *testing.T) {
+	sp := SwitchPort(dummyNode)
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{"10,20-30", true},
+		{"add 10", true},
+		{"remove 20", true},
+		{"except 5", true},
+		{"1-10,add 30", true},
+		{"all", true},
+		{"", false},
+		{"add", false},
+		{"1-5000", false},
+		{"add 5000", false},
+		{"bogus 10", false},
+	}
+	for _, tt := range tests {
+		err := sp.SetTrunkAllowedVlansE("Ethernet1", tt.value)
+		if (err == nil) != tt.valid {
+			t.Fatalf("SetTrunkAllowedVlansE(%q): expected valid %t, got %v",
+				tt.value, tt.valid, err)
+		}
+		if !tt.valid {
+			continue
+		}
+		want := "switchport trunk allowed vlan " + tt.value
+		if got := dummyConnection.GetCommands(); got[len(got)-1] != want {
+			t.Fatalf("Expected %q, got %q", want, got)
+		}
+	}
+
+	// the bool variant sends the value unchecked, as it always did
+	if !sp.SetTrunkAllowedVlans("Ethernet1", "1-5000") {
+		t.Fatal("SetTrunkAllowedVlans should send the value unchecked")
+	}
+}
+
 func TestSwitchPortSetTrunkAllowedVlansDefault_UnitTest(t *testing.T) {
 	sp := SwitchPort(dummyNode)
 
@@ -557,6 +596,39 @@ func TestSwitchPortSetTrunkAllowedVlansDefault_UnitTest(t *testing.T) {
 	}
 }
 
+func TestSwitchPortAddRemoveTrunkAllowedVlans_UnitTest(t *testing.T) {
+	sp := SwitchPort(dummyNode)
+	vlans := NewVlanSet(10, 11, 12, 20)
+	for _, intf := range interfaceList {
+		tests := []struct {
+			fn   func(string, VlanSet) bool
+			want string
+		}{
+			{sp.AddTrunkAllowedVlans, "switchport trunk allowed vlan add 10-12,20"},
+			{sp.RemoveTrunkAllowedVlans, "switchport trunk allowed vlan remove 10-12,20"},
+		}
+		for _, tt := range tests {
+			cmds := []string{
+				"interface " + intf,
+				tt.want,
+			}
+			if !tt.fn(intf, vlans) {
+				t.Fatalf("Expected %q to succeed", tt.want)
+			}
+			// first two commands are 'enable', 'configure terminal'
+			commands := dummyConnection.GetCommands()[2:]
+			for idx, val := range commands {
+				if cmds[idx] != val {
+					t.Fatalf("Expected \"%q\" got \"%q\"", cmds, commands)
+				}
+			}
+			if tt.fn(intf, VlanSet{}) {
+				t.Fatalf("Expected %q with empty set to fail", tt.want)
+			}
+		}
+	}
+}
+
 func TestSwitchPortSetTrunkGroupsAdd_UnitTest(t *testing.T) {
 	sp := SwitchPort(dummyNode)
 
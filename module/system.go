@@ -140,10 +140,15 @@ func (s *SystemEntity) parseIPRouting() bool {
 // Returns:
 //  bool: True if the commands are completed successfully
 func (s *SystemEntity) SetHostname(hostname string) bool {
+	return s.SetHostnameE(hostname) == nil
+}
+
+// SetHostnameE is the error-returning variant of SetHostname
+func (s *SystemEntity) SetHostnameE(hostname string) error {
 	if hostname == "" {
-		return s.Configure("no hostname")
+		return s.ConfigureE("no hostname")
 	}
-	return s.Configure("hostname " + hostname)
+	return s.ConfigureE("hostname " + hostname)
 }
 
 // SetHostnameDefault Configures the global default system hostname setting
@@ -154,7 +159,12 @@ func (s *SystemEntity) SetHostname(hostname string) bool {
 // Returns:
 //  bool: True if the commands are completed successfully
 func (s *SystemEntity) SetHostnameDefault() bool {
-	return s.Configure("default hostname")
+	return s.SetHostnameDefaultE() == nil
+}
+
+// SetHostnameDefaultE is the error-returning variant of SetHostnameDefault
+func (s *SystemEntity) SetHostnameDefaultE() error {
+	return s.ConfigureE("default hostname")
 }
 
 // SetIPRouting Configures the state of global ip routing
@@ -169,8 +179,13 @@ func (s *SystemEntity) SetHostnameDefault() bool {
 // Returns:
 //  bool: True if the commands completed successfully otherwise False
 func (s *SystemEntity) SetIPRouting(value string, enable bool) bool {
+	return s.SetIPRoutingE(value, enable) == nil
+}
+
+// SetIPRoutingE is the error-returning variant of SetIPRouting
+func (s *SystemEntity) SetIPRoutingE(value string, enable bool) error {
 	cmd := s.CommandBuilder("ip routing", value, false, enable)
-	return s.Configure(cmd)
+	return s.ConfigureE(cmd)
 }
 
 // SetIPRoutingDefault Configures the default tate of global ip routing
@@ -181,6 +196,11 @@ func (s *SystemEntity) SetIPRouting(value string, enable bool) bool {
 // Returns:
 //  bool: True if the commands completed successfully otherwise False
 func (s *SystemEntity) SetIPRoutingDefault(value string) bool {
+	return s.SetIPRoutingDefaultE(value) == nil
+}
+
+// SetIPRoutingDefaultE is the error-returning variant of SetIPRoutingDefault
+func (s *SystemEntity) SetIPRoutingDefaultE(value string) error {
 	cmd := s.CommandBuilder("ip routing", value, true, false)
-	return s.Configure(cmd)
+	return s.ConfigureE(cmd)
 }
@@ -33,7 +33,6 @@
 package module
 
 import (
-	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -261,14 +260,19 @@ func parseUsername(config string) UserConfig {
 //	True if the operation was successful otherwise False
 func (u *UserEntity) Create(name string, nopassword bool, secret string,
 	encryption string) (bool, error) {
+	return validationResult(u.CreateE(name, nopassword, secret, encryption))
+}
+
+// CreateE is the error-returning variant of Create
+func (u *UserEntity) CreateE(name string, nopassword bool, secret string,
+	encryption string) error {
 	if secret != "" || encryption == "nologin" {
-		return u.CreateWithSecret(name, secret, encryption)
+		return u.CreateWithSecretE(name, secret, encryption)
 	} else if nopassword {
-		return u.CreateWithNoPassword(name), nil
-	} else {
-		return false, fmt.Errorf("either \"nopassword\" or \"secret\" must be" +
-			" specified to create a user")
+		return u.CreateWithNoPasswordE(name)
 	}
+	return invalid("secret", secret, "either \"nopassword\" or \"secret\""+
+		" must be specified to create a user")
 }
 
 // CreateWithSecret Creates a new user on the local node
@@ -286,19 +290,23 @@ func (u *UserEntity) Create(name string, nopassword bool, secret string,
 //	True if the operation was successful otherwise False
 func (u *UserEntity) CreateWithSecret(name string, secret string,
 	encryption string) (bool, error) {
-	var enc string
+	return validationResult(u.CreateWithSecretE(name, secret, encryption))
+}
 
+// CreateWithSecretE is the error-returning variant of CreateWithSecret
+func (u *UserEntity) CreateWithSecretE(name string, secret string,
+	encryption string) error {
 	enc, found := encryptionMap[encryption]
 	if !found {
-		return false, fmt.Errorf("encryption must be one of \"cleartext\", " +
-			"\"md5\", \"nologin\" or \"sha512\"")
+		return invalid("encryption", encryption, "must be one of "+
+			"\"cleartext\", \"md5\", \"nologin\" or \"sha512\"")
 	}
 
 	cmd := "username " + name + " secret " + enc
 	if encryption != "nologin" {
 		cmd += " " + secret
 	}
-	return u.Configure(cmd), nil
+	return u.ConfigureE(cmd)
 }
 
 // CreateWithNoPassword Creates a new user on the local node
@@ -311,8 +319,13 @@ func (u *UserEntity) CreateWithSecret(name string, secret string,
 //
 //	True if the operation was successful otherwise False
 func (u *UserEntity) CreateWithNoPassword(name string) bool {
+	return u.CreateWithNoPasswordE(name) == nil
+}
+
+// CreateWithNoPasswordE is the error-returning variant of CreateWithNoPassword
+func (u *UserEntity) CreateWithNoPasswordE(name string) error {
 	var cmd = "username " + name + " nopassword"
-	return u.Configure(cmd)
+	return u.ConfigureE(cmd)
 }
 
 // Delete Deletes the local username from the config
@@ -325,8 +338,13 @@ func (u *UserEntity) CreateWithNoPassword(name string) bool {
 //
 //	True if the operation was successful otherwise False
 func (u *UserEntity) Delete(name string) bool {
+	return u.DeleteE(name) == nil
+}
+
+// DeleteE is the error-returning variant of Delete
+func (u *UserEntity) DeleteE(name string) error {
 	var cmd = "no username " + name
-	return u.Configure(cmd)
+	return u.ConfigureE(cmd)
 }
 
 // Default Configures the local username using the default keyword
@@ -339,8 +357,13 @@ func (u *UserEntity) Delete(name string) bool {
 //
 //	True if the operation was successful otherwise False
 func (u *UserEntity) Default(name string) bool {
+	return u.DefaultE(name) == nil
+}
+
+// DefaultE is the error-returning variant of Default
+func (u *UserEntity) DefaultE(name string) error {
 	var cmd = "default username " + name
-	return u.Configure(cmd)
+	return u.ConfigureE(cmd)
 }
 
 // SetPrivilege Configures the user privilege value in EOS
@@ -355,11 +378,16 @@ func (u *UserEntity) Default(name string) bool {
 //
 //	True if the operation was successful otherwise False
 func (u *UserEntity) SetPrivilege(name string, value int) (bool, error) {
+	return validationResult(u.SetPrivilegeE(name, value))
+}
+
+// SetPrivilegeE is the error-returning variant of SetPrivilege
+func (u *UserEntity) SetPrivilegeE(name string, value int) error {
 	if !isPrivilege(value) {
-		return false, fmt.Errorf("priviledge value must be between 0 and 15")
+		return invalid("privilege", value, "must be between 0 and 15")
 	}
 	var cmd = "username " + name + " privilege " + strconv.Itoa(value)
-	return u.Configure(cmd), nil
+	return u.ConfigureE(cmd)
 }
 
 // SetRole Configures the user role vale in EOS
@@ -373,13 +401,18 @@ func (u *UserEntity) SetPrivilege(name string, value int) (bool, error) {
 //
 //	True if the operation was successful otherwise False
 func (u *UserEntity) SetRole(name string, value string) bool {
+	return u.SetRoleE(name, value) == nil
+}
+
+// SetRoleE is the error-returning variant of SetRole
+func (u *UserEntity) SetRoleE(name string, value string) error {
 	var cmd = "username " + name
 	if value != "" {
 		cmd = cmd + " role " + value
 	} else {
 		cmd = "default " + cmd + " role"
 	}
-	return u.Configure(cmd)
+	return u.ConfigureE(cmd)
 }
 
 // SetSshkey Configures the user sshkey
@@ -393,6 +426,11 @@ func (u *UserEntity) SetRole(name string, value string) bool {
 //
 //	True if the operation was successful otherwise False
 func (u *UserEntity) SetSshkey(name string, value string) bool {
+	return u.SetSshkeyE(name, value) == nil
+}
+
+// SetSshkeyE is the error-returning variant of SetSshkey
+func (u *UserEntity) SetSshkeyE(name string, value string) error {
 	versionRegex := regexp.MustCompile(`\d.\d+`)
 	versionNumber := versionRegex.FindString(u.Version())
 
@@ -406,5 +444,5 @@ func (u *UserEntity) SetSshkey(name string, value string) bool {
 	} else {
 		cmd = "no " + cmd + " " + sshkey
 	}
-	return u.Configure(cmd)
+	return u.ConfigureE(cmd)
 }
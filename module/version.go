@@ -33,6 +33,8 @@
 package module
 
 import (
+	"context"
+
 	"github.com/aristanetworks/goeapi"
 )
 
@@ -158,6 +160,12 @@ func (s ShowInterface) GetCmd() string {
 	return "show interfaces"
 }
 
+// GetRevision returns the revision of the 'show interfaces' model
+// ShowInterface was written against
+func (s ShowInterface) GetRevision() int {
+	return 1
+}
+
 // ShowTrunkGroup defined data structure for mapping JSON response
 // of 'show vlan trunk group' to manageable object
 type ShowTrunkGroup struct {
@@ -186,34 +194,37 @@ func Show(node *goeapi.Node) *ShowEntity {
 // (with "json" key in the struct field's tag value) for the
 // decoded response from 'show version' command
 func (s *ShowEntity) ShowVersion() ShowVersion {
-	handle, _ := s.node.GetHandle("json")
-	var showversion ShowVersion
-	handle.AddCommand(&showversion)
-	handle.Call()
-	handle.Close()
+	showversion, _ := s.ShowVersionE()
 	return showversion
 }
 
+// ShowVersionE is the error-returning variant of ShowVersion
+func (s *ShowEntity) ShowVersionE() (ShowVersion, error) {
+	return goeapi.Run[ShowVersion](context.Background(), s.node)
+}
+
 // ShowInterfaces returns the pre-defined structure
 // (with "json" key in the struct field's tag value) for the
 // decoded response from 'show interfaces' command
 func (s *ShowEntity) ShowInterfaces() ShowInterface {
-	handle, _ := s.node.GetHandle("json")
-	var showinterface ShowInterface
-	handle.AddCommand(&showinterface)
-	handle.Call()
-	handle.Close()
+	showinterface, _ := s.ShowInterfacesE()
 	return showinterface
 }
 
+// ShowInterfacesE is the error-returning variant of ShowInterfaces
+func (s *ShowEntity) ShowInterfacesE() (ShowInterface, error) {
+	return goeapi.Run[ShowInterface](context.Background(), s.node)
+}
+
 // ShowTrunkGroups returns the pre-defined structure
 // (with "json" key in the struct field's tag value) for the
 // decoded response from 'show vlan trunk group' command
 func (s *ShowEntity) ShowTrunkGroups() ShowTrunkGroup {
-	handle, _ := s.node.GetHandle("json")
-	var showTrunkGroups ShowTrunkGroup
-	handle.AddCommand(&showTrunkGroups)
-	handle.Call()
-	handle.Close()
+	showTrunkGroups, _ := s.ShowTrunkGroupsE()
 	return showTrunkGroups
 }
+
+// ShowTrunkGroupsE is the error-returning variant of ShowTrunkGroups
+func (s *ShowEntity) ShowTrunkGroupsE() (ShowTrunkGroup, error) {
+	return goeapi.Run[ShowTrunkGroup](context.Background(), s.node)
+}
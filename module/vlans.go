@@ -33,7 +33,7 @@
 package module
 
 import (
-	"fmt"
+	"errors"
 	"regexp"
 	"strconv"
 	"strings"
@@ -126,11 +126,11 @@ func isVlan(vlan string) bool {
 //  VlanConfig object containing the VLAN attributes as
 //  key/value pairs.
 func (v *VlanEntity) Get(vlan string) VlanConfig {
-	parent := "vlan " + vlan
-	config, err := v.GetBlock(parent)
-	if err != nil {
+	section := v.ConfigTree().Child("vlan " + vlan)
+	if section == nil {
 		return nil
 	}
+	config := section.String()
 	var resource = make(VlanConfig)
 	resource["name"] = v.parseName(config)
 	resource["state"] = v.parseState(config)
@@ -142,13 +142,13 @@ func (v *VlanEntity) Get(vlan string) VlanConfig {
 // Returns:
 //  A VlanConfigMap type of all Vlan attributes
 func (v *VlanEntity) GetAll() VlanConfigMap {
-	config := v.Config()
-
-	matches := vlansRegex.FindAllStringSubmatch(config, -1)
 	var resources = make(VlanConfigMap)
-	for _, val := range matches {
-		vid := val[1]
-		resources[vid] = v.Get(vid)
+	for _, section := range v.ConfigTree().FindAll("vlan") {
+		match := vlansRegex.FindStringSubmatch(section.Text())
+		if match == nil {
+			continue
+		}
+		resources[match[1]] = v.Get(match[1])
 	}
 	return resources
 }
@@ -161,13 +161,15 @@ func (v *VlanEntity) GetAll() VlanConfigMap {
 // Returns:
 //  Returns string representation of Vlan config entry
 func (v *VlanEntity) GetSection(vlan string) string {
-	parent := fmt.Sprintf(`vlan\s+(%s$)|(%s,.*)|(.*,%s,)|(.*,%s$)`,
-		vlan, vlan, vlan, vlan)
-	config, err := v.GetBlock(parent)
-	if err != nil {
-		return ""
+	for _, section := range v.ConfigTree().FindAll("vlan") {
+		vids := strings.Split(strings.TrimPrefix(section.Text(), "vlan "), ",")
+		for _, vid := range vids {
+			if vid == vlan {
+				return section.String()
+			}
+		}
 	}
-	return config
+	return ""
 }
 
 // parseName scans the provided configuration block and extracts
@@ -246,11 +248,16 @@ func (v *VlanEntity) parseTrunkGroups(config string) string {
 // Returns:
 //  True if create was successful otherwise False
 func (v *VlanEntity) Create(vid string) bool {
+	return v.CreateE(vid) == nil
+}
+
+// CreateE is the error-returning variant of Create
+func (v *VlanEntity) CreateE(vid string) error {
 	var commands = []string{"vlan " + vid}
 	if isVlan(vid) {
-		return v.Configure(commands...)
+		return v.ConfigureE(commands...)
 	}
-	return false
+	return invalid("vlan", vid, "must be in the range 1 to 4094")
 }
 
 // Delete Deletes a VLAN from the running configuration
@@ -261,11 +268,16 @@ func (v *VlanEntity) Create(vid string) bool {
 // Returns:
 //  True if the operation was successful otherwise False
 func (v *VlanEntity) Delete(vid string) bool {
+	return v.DeleteE(vid) == nil
+}
+
+// DeleteE is the error-returning variant of Delete
+func (v *VlanEntity) DeleteE(vid string) error {
 	var commands = []string{"no vlan " + vid}
 	if isVlan(vid) {
-		return v.Configure(commands...)
+		return v.ConfigureE(commands...)
 	}
-	return false
+	return invalid("vlan", vid, "must be in the range 1 to 4094")
 }
 
 // Default Defaults the VLAN configuration
@@ -280,11 +292,16 @@ func (v *VlanEntity) Delete(vid string) bool {
 // Returns:
 //  True if the operation was successful otherwise False
 func (v *VlanEntity) Default(vid string) bool {
+	return v.DefaultE(vid) == nil
+}
+
+// DefaultE is the error-returning variant of Default
+func (v *VlanEntity) DefaultE(vid string) error {
 	var commands = []string{"default vlan " + vid}
 	if isVlan(vid) {
-		return v.Configure(commands...)
+		return v.ConfigureE(commands...)
 	}
-	return false
+	return invalid("vlan", vid, "must be in the range 1 to 4094")
 }
 
 // ConfigureVlan Configures the specified Vlan using commands
@@ -294,11 +311,27 @@ func (v *VlanEntity) Default(vid string) bool {
 //  commands: The list of commands to configure
 //
 // Returns:
-//  True if the commands completed successfully
+//  True if the commands completed successfully.  Unlike ConfigureVlanE,
+//  vid is sent to the node without being validated first.
 func (v *VlanEntity) ConfigureVlan(vid string, cmds ...string) bool {
+	return v.configureVlan(vid, cmds...) == nil
+}
+
+// ConfigureVlanE is the error-returning variant of ConfigureVlan. vid is
+// validated before anything is sent: it must be a list of VLAN IDs and
+// ranges in the range 1 to 4094.
+func (v *VlanEntity) ConfigureVlanE(vid string, cmds ...string) error {
+	if _, err := ParseVlanSet(vid); err != nil || strings.TrimSpace(vid) == "" {
+		return invalid("vlan", vid, "must be a list of vlans in the range 1 to 4094")
+	}
+	return v.configureVlan(vid, cmds...)
+}
+
+// configureVlan configures the specified Vlan using commands
+func (v *VlanEntity) configureVlan(vid string, cmds ...string) error {
 	var commands = []string{"vlan " + vid}
 	commands = append(commands, cmds...)
-	return v.Configure(commands...)
+	return v.ConfigureE(commands...)
 }
 
 // SetName Configures the VLAN name
@@ -316,6 +349,11 @@ func (v *VlanEntity) SetName(vid string, name string) bool {
 	return v.ConfigureVlan(vid, "name "+name)
 }
 
+// SetNameE is the error-returning variant of SetName
+func (v *VlanEntity) SetNameE(vid string, name string) error {
+	return v.ConfigureVlanE(vid, "name "+name)
+}
+
 // SetNameDefault Configures the VLAN name
 //
 // EosVersion:
@@ -330,6 +368,11 @@ func (v *VlanEntity) SetNameDefault(vid string) bool {
 	return v.ConfigureVlan(vid, "default name")
 }
 
+// SetNameDefaultE is the error-returning variant of SetNameDefault
+func (v *VlanEntity) SetNameDefaultE(vid string) error {
+	return v.ConfigureVlanE(vid, "default name")
+}
+
 // SetState Configures the VLAN state
 //
 // EosVersion:
@@ -348,6 +391,14 @@ func (v *VlanEntity) SetState(vid string, value string) bool {
 	return v.ConfigureVlan(vid, "state "+value)
 }
 
+// SetStateE is the error-returning variant of SetState
+func (v *VlanEntity) SetStateE(vid string, value string) error {
+	if value == "" {
+		return v.ConfigureVlanE(vid, "no state")
+	}
+	return v.ConfigureVlanE(vid, "state "+value)
+}
+
 // SetStateDefault Configures the VLAN state
 //
 // EosVersion:
@@ -362,6 +413,11 @@ func (v *VlanEntity) SetStateDefault(vid string) bool {
 	return v.ConfigureVlan(vid, "default state")
 }
 
+// SetStateDefaultE is the error-returning variant of SetStateDefault
+func (v *VlanEntity) SetStateDefaultE(vid string) error {
+	return v.ConfigureVlanE(vid, "default state")
+}
+
 // SetTrunkGroup Configures the list of trunk groups support on a vlan
 //
 // This method handles configuring the vlan trunk group value to default
@@ -380,23 +436,28 @@ func (v *VlanEntity) SetStateDefault(vid string) bool {
 // Returns:
 //  True if the operation was successful otherwise False
 func (v *VlanEntity) SetTrunkGroup(vid string, value []string) bool {
-	var failure = false
+	return v.SetTrunkGroupE(vid, value) == nil
+}
+
+// SetTrunkGroupE is the error-returning variant of SetTrunkGroup
+func (v *VlanEntity) SetTrunkGroupE(vid string, value []string) error {
+	var errs []error
 
 	currentValue := strings.Split(v.Get(vid)["trunk_groups"], ",")
 
 	diff := findDiff(value, currentValue)
 	for _, name := range diff {
-		if ok := v.AddTrunkGroup(vid, name); !ok {
-			failure = true
+		if err := v.AddTrunkGroupE(vid, name); err != nil {
+			errs = append(errs, err)
 		}
 	}
 	diff = findDiff(currentValue, value)
 	for _, name := range diff {
-		if ok := v.RemoveTrunkGroup(vid, name); !ok {
-			failure = true
+		if err := v.RemoveTrunkGroupE(vid, name); err != nil {
+			errs = append(errs, err)
 		}
 	}
-	return !failure
+	return errors.Join(errs...)
 }
 
 // SetTrunkGroupDefault Configures the default list of trunk groups support on a vlan
@@ -418,6 +479,11 @@ func (v *VlanEntity) SetTrunkGroupDefault(vid string) bool {
 	return v.ConfigureVlan(vid, "default trunk group")
 }
 
+// SetTrunkGroupDefaultE is the error-returning variant of SetTrunkGroupDefault
+func (v *VlanEntity) SetTrunkGroupDefaultE(vid string) error {
+	return v.ConfigureVlanE(vid, "default trunk group")
+}
+
 // AddTrunkGroup Adds a new trunk group to the Vlan in the running-config
 //
 // EosVersion:
@@ -434,6 +500,12 @@ func (v *VlanEntity) AddTrunkGroup(vid string, name string) bool {
 	return v.ConfigureVlan(vid, commands...)
 }
 
+// AddTrunkGroupE is the error-returning variant of AddTrunkGroup
+func (v *VlanEntity) AddTrunkGroupE(vid string, name string) error {
+	var commands = []string{"trunk group " + name}
+	return v.ConfigureVlanE(vid, commands...)
+}
+
 // RemoveTrunkGroup Removes a trunk group from the list of configured trunk
 // groups for the specified VLAN ID
 //
@@ -450,3 +522,9 @@ func (v *VlanEntity) RemoveTrunkGroup(vid string, name string) bool {
 	var commands = []string{"no trunk group " + name}
 	return v.ConfigureVlan(vid, commands...)
 }
+
+// RemoveTrunkGroupE is the error-returning variant of RemoveTrunkGroup
+func (v *VlanEntity) RemoveTrunkGroupE(vid string, name string) error {
+	var commands = []string{"no trunk group " + name}
+	return v.ConfigureVlanE(vid, commands...)
+}
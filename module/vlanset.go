@@ -0,0 +1,185 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package module
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VlanSet is a set of VLAN IDs in the range 1 to 4094. It parses and
+// renders the EOS range syntax, e.g. "1,10-20,4094". The zero value is an
+// empty set and VlanSets can be compared with ==.
+type VlanSet struct {
+	bits [64]uint64
+}
+
+// NewVlanSet returns the VlanSet containing vids. IDs outside of the
+// range 1 to 4094 are ignored.
+func NewVlanSet(vids ...int) VlanSet {
+	var set VlanSet
+	for _, vid := range vids {
+		set.add(vid)
+	}
+	return set
+}
+
+// ParseVlanSet parses an EOS VLAN range expression such as "1,10-20,4094".
+// The keywords "all" and "none" are accepted.
+func ParseVlanSet(s string) (VlanSet, error) {
+	var set VlanSet
+	s = strings.TrimSpace(s)
+	switch s {
+	case "", "none":
+		return set, nil
+	case "all":
+		s = "1-4094"
+	}
+	for _, elem := range strings.Split(s, ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(elem), "-")
+		if !isRange {
+			last = first
+		}
+		start, err1 := strconv.Atoi(first)
+		end, err2 := strconv.Atoi(last)
+		if err1 != nil || err2 != nil || !isVlan(first) || !isVlan(last) ||
+			end < start {
+			return VlanSet{}, fmt.Errorf("Invalid vlan range: %q", elem)
+		}
+		for vid := start; vid <= end; vid++ {
+			set.add(vid)
+		}
+	}
+	return set, nil
+}
+
+// add adds vid to s if it is a valid VLAN ID
+func (s *VlanSet) add(vid int) {
+	if vid >= 1 && vid <= 4094 {
+		s.bits[vid/64] |= 1 << (vid % 64)
+	}
+}
+
+// Contains returns true if vid is in s
+func (s VlanSet) Contains(vid int) bool {
+	if vid < 1 || vid > 4094 {
+		return false
+	}
+	return s.bits[vid/64]&(1<<(vid%64)) != 0
+}
+
+// Len returns the number of VLANs in s
+func (s VlanSet) Len() int {
+	return len(s.Vlans())
+}
+
+// IsEmpty returns true if s contains no VLAN
+func (s VlanSet) IsEmpty() bool {
+	return s == VlanSet{}
+}
+
+// Union returns the VLANs that are in s or other
+func (s VlanSet) Union(other VlanSet) VlanSet {
+	for idx := range s.bits {
+		s.bits[idx] |= other.bits[idx]
+	}
+	return s
+}
+
+// Intersect returns the VLANs that are in both s and other
+func (s VlanSet) Intersect(other VlanSet) VlanSet {
+	for idx := range s.bits {
+		s.bits[idx] &= other.bits[idx]
+	}
+	return s
+}
+
+// Difference returns the VLANs of s that are not in other
+func (s VlanSet) Difference(other VlanSet) VlanSet {
+	for idx := range s.bits {
+		s.bits[idx] &^= other.bits[idx]
+	}
+	return s
+}
+
+// Vlans returns the VLAN IDs of s in ascending order
+func (s VlanSet) Vlans() VlanList {
+	var vids VlanList
+	for vid := 1; vid <= 4094; vid++ {
+		if s.Contains(vid) {
+			vids = append(vids, vid)
+		}
+	}
+	return vids
+}
+
+// String returns s in EOS range syntax, e.g. "1,10-20", or "none" if s
+// is empty.
+func (s VlanSet) String() string {
+	var ranges []string
+	for vid := 1; vid <= 4094; vid++ {
+		if !s.Contains(vid) {
+			continue
+		}
+		end := vid
+		for s.Contains(end + 1) {
+			end++
+		}
+		if vid == end {
+			ranges = append(ranges, strconv.Itoa(vid))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", vid, end))
+		}
+		vid = end
+	}
+	if ranges == nil {
+		return "none"
+	}
+	return strings.Join(ranges, ",")
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s VlanSet) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (s *VlanSet) UnmarshalText(text []byte) error {
+	set, err := ParseVlanSet(string(text))
+	if err != nil {
+		return err
+	}
+	*s = set
+	return nil
+}
@@ -0,0 +1,121 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package module
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseVlanSet_UnitTest(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		len  int
+	}{
+		{"", "none", 0},
+		{"none", "none", 0},
+		{"all", "1-4094", 4094},
+		{"4094", "4094", 1},
+		{"1,10-20,4094", "1,10-20,4094", 13},
+		{" 5-6, 1 ,2,3 ", "1-3,5-6", 5},
+		{"10-10", "10", 1},
+	}
+	for _, tt := range tests {
+		set, err := ParseVlanSet(tt.in)
+		if err != nil {
+			t.Fatalf("ParseVlanSet(%q) failed: %s", tt.in, err)
+		}
+		if set.String() != tt.want || set.Len() != tt.len {
+			t.Fatalf("ParseVlanSet(%q) = %q (%d); want %q (%d)", tt.in,
+				set, set.Len(), tt.want, tt.len)
+		}
+	}
+	for _, in := range []string{"0", "4095", "1-4095", "a", "20-10", "1,,2",
+		"1-2-3"} {
+		if _, err := ParseVlanSet(in); err == nil {
+			t.Fatalf("ParseVlanSet(%q) expected error", in)
+		}
+	}
+}
+
+func TestVlanSetAlgebra_UnitTest(t *testing.T) {
+	a, _ := ParseVlanSet("1-10,20")
+	b, _ := ParseVlanSet("5-15")
+	tests := []struct {
+		got  VlanSet
+		want string
+	}{
+		{a.Union(b), "1-15,20"},
+		{a.Intersect(b), "5-10"},
+		{a.Difference(b), "1-4,20"},
+		{b.Difference(a), "11-15"},
+		{a.Difference(a), "none"},
+	}
+	for _, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Fatalf("got %q; want %q", tt.got, tt.want)
+		}
+	}
+	if a.String() != "1-10,20" {
+		t.Fatalf("operations modified the receiver: %q", a)
+	}
+	if !a.Contains(20) || a.Contains(11) || a.Contains(0) || a.Contains(5000) {
+		t.Fatalf("Contains() failed for %q", a)
+	}
+	if NewVlanSet(20, 1, 2, 3, 0, 4095) != NewVlanSet(1, 2, 3, 20) {
+		t.Fatal("NewVlanSet() should ignore invalid vlans")
+	}
+	if !reflect.DeepEqual(NewVlanSet(3, 1).Vlans(), VlanList{1, 3}) {
+		t.Fatalf("Vlans() = %v", NewVlanSet(3, 1).Vlans())
+	}
+	if !(VlanSet{}).IsEmpty() || a.IsEmpty() {
+		t.Fatal("IsEmpty() failed")
+	}
+}
+
+func TestVlanSetJSON_UnitTest(t *testing.T) {
+	set := NewVlanSet(1, 2, 3, 100)
+	data, err := json.Marshal(set)
+	if err != nil || string(data) != `"1-3,100"` {
+		t.Fatalf("Marshal() = %s, %v", data, err)
+	}
+	var decoded VlanSet
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != set {
+		t.Fatalf("Unmarshal(%s) = %q, %v", data, decoded, err)
+	}
+	if err := json.Unmarshal([]byte(`"0-2"`), &decoded); err == nil {
+		t.Fatal("Unmarshal() expected error")
+	}
+}
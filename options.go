@@ -0,0 +1,157 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// RequestOptions holds the optional parameters of an eAPI runCmds request.
+// The zero value sends the same request as ExecuteContext.
+type RequestOptions struct {
+	// Version selects the revision of the command models: "" or "1" for
+	// the original revision, "latest" for the newest one, or another
+	// revision number.
+	Version string
+	// AutoComplete allows abbreviated commands such as "sh ver".
+	AutoComplete bool
+	// ExpandAliases expands the aliases configured on the node.
+	ExpandAliases bool
+	// Timestamps requests the start time and duration of each command,
+	// which are reported in JSONRPCResponse.Meta.
+	Timestamps bool
+	// Streaming asks the node to stream the response as it is produced.
+	Streaming bool
+}
+
+// version returns the value of the runCmds version parameter.
+func (o RequestOptions) version() (interface{}, error) {
+	switch o.Version {
+	case "":
+		return 1, nil
+	case "latest":
+		return o.Version, nil
+	}
+	version, err := strconv.Atoi(o.Version)
+	if err != nil || version < 1 {
+		return nil, fmt.Errorf("Invalid version: %q", o.Version)
+	}
+	return version, nil
+}
+
+// requestParameters is the params member of a runCmds request.
+type requestParameters struct {
+	Version       interface{}   `json:"version"`
+	Cmds          []interface{} `json:"cmds"`
+	Format        string        `json:"format"`
+	AutoComplete  bool          `json:"autoComplete,omitempty"`
+	ExpandAliases bool          `json:"expandAliases,omitempty"`
+	Timestamps    bool          `json:"timestamps,omitempty"`
+	Streaming     bool          `json:"streaming,omitempty"`
+}
+
+// request is a runCmds request as sent to the node.
+type request struct {
+	Jsonrpc string            `json:"jsonrpc"`
+	Method  string            `json:"method"`
+	Params  requestParameters `json:"params"`
+	ID      string            `json:"id"`
+}
+
+// CommandMeta holds the execution metadata eAPI reports for a command
+// when RequestOptions.Timestamps is set.
+type CommandMeta struct {
+	// ExecStartTime is the time the command started, in seconds since
+	// the epoch.
+	ExecStartTime float64
+	// ExecDuration is the time the command took, in seconds.
+	ExecDuration float64
+}
+
+// StartTime returns ExecStartTime as a time.Time.
+func (m CommandMeta) StartTime() time.Time {
+	sec, frac := math.Modf(m.ExecStartTime)
+	return time.Unix(int64(sec), int64(frac*1e9))
+}
+
+// Duration returns ExecDuration as a time.Duration.
+func (m CommandMeta) Duration() time.Duration {
+	return time.Duration(m.ExecDuration * float64(time.Second))
+}
+
+// extractMeta moves the "_meta" member eAPI adds to each result when
+// timestamps are requested into resp.Meta, so that it is not mistaken for
+// part of the command output. resp.Meta is left nil if no result has one.
+func extractMeta(resp *JSONRPCResponse) {
+	for index, result := range resp.Result {
+		meta, ok := result["_meta"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if resp.Meta == nil {
+			resp.Meta = make([]CommandMeta, len(resp.Result))
+		}
+		start, _ := meta["execStartTime"].(float64)
+		duration, _ := meta["execDuration"].(float64)
+		resp.Meta[index] = CommandMeta{ExecStartTime: start, ExecDuration: duration}
+		delete(result, "_meta")
+	}
+}
+
+// execute sends commands over conn. ExecuteWithOptions is only used if
+// opts are set, so connections that implement ExecuteContext alone, such
+// as those embedding EapiConnection, keep working. Connections that
+// implement neither ContextExecutor nor OptionsExecutor are sent the
+// commands with Execute, once ctx has been checked.
+func execute(ctx context.Context, conn EapiConnectionEntity,
+	commands []interface{}, encoding string,
+	opts RequestOptions) (*JSONRPCResponse, error) {
+	if opts != (RequestOptions{}) {
+		executor, ok := conn.(OptionsExecutor)
+		if !ok {
+			return nil, fmt.Errorf("Connection does not support request options")
+		}
+		return executor.ExecuteWithOptions(ctx, commands, encoding, opts)
+	}
+	if executor, ok := conn.(ContextExecutor); ok {
+		return executor.ExecuteContext(ctx, commands, encoding)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return conn.Execute(commands, encoding)
+}
@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuildJSONRequestOptions_UnitTest(t *testing.T) {
+	cmds := cmdsToInterface([]string{"enable", "show version"})
+	tests := []struct {
+		opts RequestOptions
+		want map[string]interface{}
+	}{
+		{RequestOptions{}, map[string]interface{}{
+			"version": 1.0, "cmds": []interface{}{"enable", "show version"},
+			"format": "json"}},
+		{RequestOptions{Version: "latest", AutoComplete: true, ExpandAliases: true,
+			Timestamps: true, Streaming: true}, map[string]interface{}{
+			"version": "latest", "cmds": []interface{}{"enable", "show version"},
+			"format": "json", "autoComplete": true, "expandAliases": true,
+			"timestamps": true, "streaming": true}},
+		{RequestOptions{Version: "2"}, map[string]interface{}{
+			"version": 2.0, "cmds": []interface{}{"enable", "show version"},
+			"format": "json"}},
+	}
+	for _, tt := range tests {
+		data, err := buildJSONRequest(cmds, "json", "1", tt.opts)
+		if err != nil {
+			t.Fatalf("buildJSONRequest(%#v) failed: %v", tt.opts, err)
+		}
+		var req struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		json.Unmarshal(data, &req)
+		if !reflect.DeepEqual(req.Params, tt.want) {
+			t.Errorf("Expected params %v, got %v", tt.want, req.Params)
+		}
+	}
+
+	for _, version := range []string{"0", "newest", "-1"} {
+		if _, err := buildJSONRequest(cmds, "json", "1",
+			RequestOptions{Version: version}); err == nil {
+			t.Errorf("Expected an error for version %q", version)
+		}
+	}
+}
+
+func TestExtractMeta_UnitTest(t *testing.T) {
+	resp := &JSONRPCResponse{Result: []map[string]interface{}{
+		{},
+		{"version": "4.30", "_meta": map[string]interface{}{
+			"execStartTime": 1700000000.25, "execDuration": 0.5}},
+	}}
+	extractMeta(resp)
+	want := []CommandMeta{{}, {ExecStartTime: 1700000000.25, ExecDuration: 0.5}}
+	if !reflect.DeepEqual(resp.Meta, want) {
+		t.Fatalf("Expected %#v, got %#v", want, resp.Meta)
+	}
+	if _, found := resp.Result[1]["_meta"]; found {
+		t.Fatal("_meta left in the result")
+	}
+	if got := resp.Meta[1].StartTime(); !got.Equal(time.Unix(1700000000, 250000000)) {
+		t.Fatalf("Unexpected StartTime %v", got)
+	}
+	if got := resp.Meta[1].Duration(); got != 500*time.Millisecond {
+		t.Fatalf("Unexpected Duration %v", got)
+	}
+
+	resp = &JSONRPCResponse{Result: []map[string]interface{}{{"version": "4.30"}}}
+	extractMeta(resp)
+	if resp.Meta != nil {
+		t.Fatalf("Expected no meta, got %#v", resp.Meta)
+	}
+}
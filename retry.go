@@ -0,0 +1,242 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how a Connection resends requests that fail with a
+// transient error, such as when a switch is reloading or eAPI restarts.
+//
+// Network errors and HTTP 5xx responses are retried; command errors are
+// retried only if their code is listed in RetryCodes, which holds the eAPI
+// busy codes by default. Requests made only of show commands are retried
+// by default. Requests containing configuration commands are retried only
+// if RetryConfig is set, since a request that timed out may have been
+// applied by the node.
+//
+// A zero RetryPolicy disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times a request is sent,
+	// including the first. Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+	// Multiplier scales the delay after each retry
+	Multiplier float64
+	// Jitter randomizes each delay by up to the given fraction of it, in
+	// either direction, so that clients don't retry in lockstep.
+	Jitter float64
+	// RetryConfig allows retrying requests that contain commands other
+	// than show commands.
+	RetryConfig bool
+	// Retryable, if set, replaces IsRetryable to classify errors
+	Retryable func(err error) bool
+	// RetryCodes lists the eAPI error codes of the command errors that are
+	// retried. DefaultRetryPolicy lists the codes of CommandError.Busy.
+	RetryCodes []int
+}
+
+// busyCodes are the eAPI error codes of the requests it was temporarily
+// unable to handle: the JSON-RPC internal error and the server errors.
+var busyCodes = func() []int {
+	codes := []int{-32603}
+	for code := -32000; code >= -32099; code-- {
+		codes = append(codes, code)
+	}
+	return codes
+}()
+
+// DefaultRetryPolicy returns the RetryPolicy used by connections unless
+// SetRetryPolicy is called: up to 3 attempts of show requests, backing off
+// from 200ms to at most 5s, including those eAPI was too busy to handle.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryCodes:     append([]int(nil), busyCodes...),
+	}
+}
+
+// backoff returns the delay before the given retry, starting at 1
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < retry && p.Multiplier > 1; i++ {
+		delay *= p.Multiplier
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// SetRetryPolicy sets the RetryPolicy for Connection
+func (conn *EapiConnection) SetRetryPolicy(policy RetryPolicy) {
+	if conn == nil {
+		return
+	}
+	conn.retryPolicy = &policy
+}
+
+// getRetryPolicy returns the RetryPolicy for Connection
+func (conn *EapiConnection) getRetryPolicy() RetryPolicy {
+	if conn.retryPolicy == nil {
+		return DefaultRetryPolicy()
+	}
+	return *conn.retryPolicy
+}
+
+// retry calls send until it succeeds, fails with an error that is not
+// retryable, or the attempts allowed by the RetryPolicy for commands are
+// exhausted. Waiting between attempts is aborted if ctx is done.
+func (conn *EapiConnection) retry(ctx context.Context, commands []interface{},
+	send func() (*JSONRPCResponse, error)) (*JSONRPCResponse, error) {
+	policy := conn.getRetryPolicy()
+	attempts := policy.MaxAttempts
+	if !policy.RetryConfig && !readOnly(commands) {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := send()
+		if err == nil {
+			conn.ClearError()
+			return resp, nil
+		}
+		if attempt >= attempts || ctx.Err() != nil || !policy.retryable(err) {
+			return resp, err
+		}
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			conn.SetError(ctx.Err())
+			return resp, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether err is retried under the policy
+func (p RetryPolicy) retryable(err error) bool {
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		for _, code := range p.RetryCodes {
+			if cmdErr.Code == code {
+				return true
+			}
+		}
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// IsRetryable reports whether err is a transient failure that may succeed
+// if the request is sent again: a network error or an HTTP 5xx response.
+// Command errors, including busy ones, TLS certificate errors and the
+// cancellation of the request context are not retryable; command errors
+// are retried according to RetryPolicy.RetryCodes.
+func IsRetryable(err error) bool {
+	var cmdErr *CommandError
+	var httpErr *HTTPError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &cmdErr):
+		return false
+	case errors.As(err, &httpErr):
+		return httpErr.StatusCode >= 500
+	case isCertificateError(err):
+		return false
+	case errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.As(err, &netErr):
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isCertificateError returns true if err is caused by the verification of
+// a TLS certificate.
+func isCertificateError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &verifyErr) || errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &invalidErr)
+}
+
+// readOnly returns true if commands are made only of enable and show
+// commands.
+func readOnly(commands []interface{}) bool {
+	for _, command := range commands {
+		var cmd string
+		switch c := command.(type) {
+		case string:
+			cmd = c
+		case map[string]string:
+			cmd = c["cmd"]
+		case map[string]interface{}:
+			cmd, _ = c["cmd"].(string)
+		default:
+			return false
+		}
+		cmd = strings.TrimSpace(cmd)
+		if cmd != "enable" && !strings.HasPrefix(cmd, "show ") {
+			return false
+		}
+	}
+	return true
+}
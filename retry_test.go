@@ -0,0 +1,320 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newRetryTestServer returns a server that answers the first failures
+// requests with status and the following ones with a successful response.
+func newRetryTestServer(failures int32, status int) (*httptest.Server, *int32) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc": "2.0", "id": "1", "result": [{}, {}]}`)
+	}))
+	return srv, &requests
+}
+
+func newRetryTestConnection(srv *httptest.Server) *HTTPEapiConnection {
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	conn := NewHTTPEapiConnection("http", addr.IP.String(), "admin", "", addr.Port).(*HTTPEapiConnection)
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	conn.SetRetryPolicy(policy)
+	return conn
+}
+
+func TestRetryShowCommands_UnitTest(t *testing.T) {
+	srv, requests := newRetryTestServer(2, http.StatusServiceUnavailable)
+	defer srv.Close()
+	conn := newRetryTestConnection(srv)
+
+	_, err := conn.Execute([]interface{}{"enable", "show version"}, "json")
+	if err != nil {
+		t.Fatalf("Execute failed: %s", err)
+	}
+	if *requests != 3 {
+		t.Fatalf("Expected 3 requests, got %d", *requests)
+	}
+	if conn.Error() != nil {
+		t.Fatalf("Connection error not cleared: %s", conn.Error())
+	}
+}
+
+func TestRetryMaxAttempts_UnitTest(t *testing.T) {
+	srv, requests := newRetryTestServer(5, http.StatusBadGateway)
+	defer srv.Close()
+	conn := newRetryTestConnection(srv)
+
+	_, err := conn.Execute([]interface{}{"enable", "show version"}, "json")
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected *HTTPError 502, got %#v", err)
+	}
+	if *requests != 3 {
+		t.Fatalf("Expected 3 requests, got %d", *requests)
+	}
+}
+
+func TestRetryConfigCommands_UnitTest(t *testing.T) {
+	srv, requests := newRetryTestServer(1, http.StatusServiceUnavailable)
+	defer srv.Close()
+	conn := newRetryTestConnection(srv)
+
+	cmds := []interface{}{"enable", "configure", "hostname test"}
+	if _, err := conn.Execute(cmds, "json"); err == nil {
+		t.Fatal("Config request should not be retried by default")
+	}
+	if *requests != 1 {
+		t.Fatalf("Expected 1 request, got %d", *requests)
+	}
+
+	atomic.StoreInt32(requests, 0)
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.RetryConfig = true
+	conn.SetRetryPolicy(policy)
+	if _, err := conn.Execute(cmds, "json"); err != nil {
+		t.Fatalf("Execute failed: %s", err)
+	}
+	if *requests != 2 {
+		t.Fatalf("Expected 2 requests, got %d", *requests)
+	}
+}
+
+func TestRetryCommandError_UnitTest(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc": "2.0", "id": "1", "error": {"code": 1002,
+			"message": "CLI command 2 of 2 'show bogus' failed: invalid command",
+			"data": [{}, {"errors": ["Invalid input"]}]}}`)
+	}))
+	defer srv.Close()
+	conn := newRetryTestConnection(srv)
+
+	_, err := conn.Execute([]interface{}{"enable", "show bogus"}, "json")
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Expected *CommandError, got %#v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("Expected 1 request, got %d", requests)
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.RetryCodes = []int{1002}
+	conn.SetRetryPolicy(policy)
+	conn.Execute([]interface{}{"enable", "show bogus"}, "json")
+	if requests != 3 {
+		t.Fatalf("Expected 3 requests with RetryCodes, got %d", requests)
+	}
+}
+
+func TestRetryBusy_UnitTest(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&requests, 1) == 1 {
+			fmt.Fprint(w, `{"jsonrpc": "2.0", "id": "1", "error": {"code": -32603,
+				"message": "Internal error"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"jsonrpc": "2.0", "id": "1", "result": [{}, {}]}`)
+	}))
+	defer srv.Close()
+	conn := newRetryTestConnection(srv)
+
+	if _, err := conn.Execute([]interface{}{"enable", "show version"}, "json"); err != nil {
+		t.Fatalf("Execute failed: %s", err)
+	}
+	if requests != 2 {
+		t.Fatalf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestRetryCommandErrorBusy_UnitTest(t *testing.T) {
+	tests := []struct {
+		code int
+		want bool
+	}{
+		{1002, false},
+		{-32603, true},
+		{-32000, true},
+		{-32001, true},
+		{-32099, true},
+		{-32100, false},
+		{-32700, false},
+	}
+	for _, tt := range tests {
+		if got := (&CommandError{Code: tt.code}).Busy(); got != tt.want {
+			t.Fatalf("Busy(%d): expected %t, got %t", tt.code, tt.want, got)
+		}
+	}
+}
+
+func TestRetryContextCanceled_UnitTest(t *testing.T) {
+	srv, requests := newRetryTestServer(5, http.StatusServiceUnavailable)
+	defer srv.Close()
+	conn := newRetryTestConnection(srv)
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+	conn.SetRetryPolicy(policy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := conn.ExecuteContext(ctx, []interface{}{"enable", "show version"}, "json")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if *requests != 1 {
+		t.Fatalf("Expected 1 request, got %d", *requests)
+	}
+	if conn.Error() != err {
+		t.Fatalf("Connection error not set: %v", conn.Error())
+	}
+}
+
+func TestRetryDisabled_UnitTest(t *testing.T) {
+	srv, requests := newRetryTestServer(1, http.StatusServiceUnavailable)
+	defer srv.Close()
+	conn := newRetryTestConnection(srv)
+	conn.SetRetryPolicy(RetryPolicy{})
+
+	if _, err := conn.Execute([]interface{}{"enable", "show version"}, "json"); err == nil {
+		t.Fatal("Request should not be retried with a zero RetryPolicy")
+	}
+	if *requests != 1 {
+		t.Fatalf("Expected 1 request, got %d", *requests)
+	}
+}
+
+func TestRetryBackoff_UnitTest(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{10, time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.backoff(tt.retry); got != tt.want {
+			t.Fatalf("backoff(%d): expected %s, got %s", tt.retry, tt.want, got)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 50*time.Millisecond ||
+			got > 150*time.Millisecond {
+			t.Fatalf("backoff with jitter out of bounds: %s", got)
+		}
+	}
+}
+
+func TestRetryIsRetryable_UnitTest(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&HTTPError{StatusCode: 503}, true},
+		{&HTTPError{StatusCode: 401}, false},
+		{&CommandError{Code: 1002}, false},
+		{&CommandError{Code: -32603}, false},
+		{&CommandError{Code: -32001}, false},
+		{&CommandError{Code: -32700}, false},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, false},
+		{syscall.ECONNRESET, true},
+		{&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{io.ErrUnexpectedEOF, true},
+		{fmt.Errorf("Post: %w", io.EOF), true},
+		{errors.New("bogus"), false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Fatalf("IsRetryable(%#v): expected %t, got %t", tt.err, tt.want, got)
+		}
+	}
+}
+
+func TestRetryReadOnly_UnitTest(t *testing.T) {
+	tests := []struct {
+		cmds []interface{}
+		want bool
+	}{
+		{[]interface{}{"enable", "show version"}, true},
+		{[]interface{}{map[string]interface{}{"cmd": "enable", "input": "pw"},
+			"show running-config"}, true},
+		{[]interface{}{map[string]string{"cmd": "enable", "input": "pw"},
+			"show vlan"}, true},
+		{[]interface{}{"enable", "configure", "hostname test"}, false},
+		{[]interface{}{"enable", "copy running-config startup-config"}, false},
+		{[]interface{}{"enable", 1}, false},
+	}
+	for _, tt := range tests {
+		if got := readOnly(tt.cmds); got != tt.want {
+			t.Fatalf("readOnly(%#v): expected %t, got %t", tt.cmds, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import "context"
+
+// Run sends the command of the EapiCommand type T to node and returns the
+// decoded response. T is the response struct; its pointer implements
+// EapiCommand, so adding a new show command is just declaring a struct
+// with a GetCmd method:
+//
+//	type ShowHostname struct {
+//		Hostname string `json:"hostname"`
+//		Fqdn     string `json:"fqdn"`
+//	}
+//
+//	func (s *ShowHostname) GetCmd() string {
+//		return "show hostname"
+//	}
+//
+//	hostname, err := goeapi.Run[ShowHostname](ctx, node)
+//
+// Returns:
+//
+//	The decoded response, and an error if the request failed. If the
+//	command is rejected by the node, the error is a *CommandError.
+func Run[T any, PT interface {
+	*T
+	EapiCommand
+}](ctx context.Context, node *Node) (T, error) {
+	var v T
+	err := RunMany(ctx, node, PT(&v))
+	return v, err
+}
+
+// RunMany sends the commands of cmds to node in a single request and
+// decodes each response into its EapiCommand. The commands can be of
+// different types:
+//
+//	var version ShowVersion
+//	var hostname ShowHostname
+//	err := goeapi.RunMany(ctx, node, &version, &hostname)
+//
+// Returns:
+//
+//	error if the request failed. If a command is rejected by the node,
+//	the error is a *CommandError whose CommandIndex refers to cmds.
+func RunMany(ctx context.Context, node *Node, cmds ...EapiCommand) error {
+	handle, err := node.GetHandle("json")
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+	for _, cmd := range cmds {
+		if err := handle.AddCommand(cmd); err != nil {
+			return err
+		}
+	}
+	return handle.CallContext(ctx)
+}
@@ -0,0 +1,155 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type runTestHostname struct {
+	Hostname string `json:"hostname"`
+	Fqdn     string `json:"fqdn"`
+}
+
+func (s *runTestHostname) GetCmd() string {
+	return "show hostname"
+}
+
+type runTestClock struct {
+	UTCTime  float64 `json:"utcTime"`
+	Timezone string  `json:"timezone"`
+}
+
+func (s *runTestClock) GetCmd() string {
+	return "show clock"
+}
+
+type runTestBogus struct{}
+
+func (s *runTestBogus) GetCmd() string {
+	return "show bogus"
+}
+
+// newRunTestNode returns a Node whose server answers show hostname and
+// show clock and rejects any other show command.
+func newRunTestNode(t *testing.T) *Node {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := JSONRPCResponse{Jsonrpc: "2.0", ID: req.ID}
+		var data []interface{}
+		for _, cmd := range req.Params.Cmds {
+			var result map[string]interface{}
+			switch cmd {
+			case "enable":
+				result = map[string]interface{}{}
+			case "show hostname":
+				result = map[string]interface{}{"hostname": "veos", "fqdn": "veos.example.com"}
+			case "show clock":
+				result = map[string]interface{}{"utcTime": 1.5e9, "timezone": "UTC"}
+			default:
+				data = append(data, map[string]interface{}{
+					"errors": []interface{}{"Invalid input"}})
+				resp.Result = nil
+				resp.Error = &RespError{Code: 1002, Message: "CLI command failed", Data: data}
+				json.NewEncoder(w).Encode(resp)
+				return
+			}
+			data = append(data, result)
+			resp.Result = append(resp.Result, result)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	conn := NewHTTPEapiConnection("http", addr.IP.String(), "admin", "", addr.Port)
+	return &Node{conn: conn}
+}
+
+func TestRun_UnitTest(t *testing.T) {
+	node := newRunTestNode(t)
+
+	hostname, err := Run[runTestHostname](context.Background(), node)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if hostname.Hostname != "veos" || hostname.Fqdn != "veos.example.com" {
+		t.Fatalf("Unexpected response: %#v", hostname)
+	}
+}
+
+func TestRunMany_UnitTest(t *testing.T) {
+	node := newRunTestNode(t)
+
+	var hostname runTestHostname
+	var clock runTestClock
+	if err := RunMany(context.Background(), node, &hostname, &clock); err != nil {
+		t.Fatalf("RunMany failed: %v", err)
+	}
+	if hostname.Hostname != "veos" {
+		t.Fatalf("Unexpected hostname: %#v", hostname)
+	}
+	if clock.UTCTime != 1.5e9 || clock.Timezone != "UTC" {
+		t.Fatalf("Unexpected clock: %#v", clock)
+	}
+}
+
+func TestRunErrors_UnitTest(t *testing.T) {
+	if _, err := Run[runTestHostname](context.Background(), nil); err == nil {
+		t.Fatal("Expected an error for a nil node")
+	}
+
+	node := newRunTestNode(t)
+	var hostname runTestHostname
+	err := RunMany(context.Background(), node, &hostname, &runTestBogus{})
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Expected a CommandError, got %v", err)
+	}
+	if cmdErr.CommandIndex != 1 || cmdErr.Command != "show bogus" {
+		t.Fatalf("Unexpected CommandError: %#v", cmdErr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Run[runTestHostname](ctx, node); err == nil {
+		t.Fatal("Expected an error for a canceled context")
+	}
+}
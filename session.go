@@ -0,0 +1,181 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ConfigSession represents an EOS configuration session on a Node
+//
+// Commands added to a ConfigSession are staged on the device and only
+// take effect once the session is committed, so a partially applied change
+// never leaves the running-config in a mixed state. A ConfigSession is
+// obtained with Node.NewSession.
+type ConfigSession struct {
+	node        *Node
+	sessionNode *Node
+	name        string
+}
+
+// NewSession creates a ConfigSession for this Node.
+//
+// Args:
+//
+//	name (string): The name of the configuration session. If empty, a
+//	               unique name is generated.
+//
+// Returns:
+//
+//	Pointer to a ConfigSession
+func (n *Node) NewSession(name string) *ConfigSession {
+	if name == "" {
+		name = fmt.Sprintf("goeapi-%d", time.Now().UnixNano())
+	}
+	s := &ConfigSession{node: n, name: name}
+	s.sessionNode = n.derive()
+	s.sessionNode.session = s
+	return s
+}
+
+// Name returns the name of the configuration session
+func (s *ConfigSession) Name() string {
+	return s.name
+}
+
+// Node returns a Node bound to this ConfigSession. Configuration sent
+// through the returned Node (including by the module entities built on
+// it) is staged into the session instead of being applied immediately.
+//
+// Example:
+//
+//	session := node.NewSession("vlans")
+//	module.Vlan(session.Node()).Create("10")
+//	session.Commit()
+func (s *ConfigSession) Node() *Node {
+	return s.sessionNode
+}
+
+// enter returns the command used to enter the configuration session
+func (s *ConfigSession) enter() string {
+	return "configure session " + s.name
+}
+
+// run sends commands to the node from within the configuration session
+func (s *ConfigSession) run(ctx context.Context, encoding string,
+	commands ...string) (*JSONRPCResponse, error) {
+	commands = append([]string{s.enter()}, commands...)
+	result, err := s.node.RunCommandsContext(ctx, commands, encoding)
+	return result, rebaseCommandError(err, cmdsToInterface(commands), 1)
+}
+
+// Add stages the commands into the configuration session. The commands
+// are not applied to the running-config until the session is committed.
+//
+// Returns:
+//
+//	error on failure. If a command is rejected by the node, the error is
+//	a *CommandError whose CommandIndex refers to commands.
+func (s *ConfigSession) Add(commands ...string) error {
+	return s.AddContext(context.Background(), commands...)
+}
+
+// AddContext stages the commands into the configuration session using ctx.
+func (s *ConfigSession) AddContext(ctx context.Context, commands ...string) error {
+	_, err := s.run(ctx, "json", commands...)
+	return err
+}
+
+// Diff returns the differences between the running-config and the
+// configuration session as reported by 'show session-config diffs'.
+//
+// Returns:
+//
+//	String format of the diff or error on failure
+func (s *ConfigSession) Diff() (string, error) {
+	result, err := s.run(context.Background(), "text", "show session-config diffs")
+	if err != nil {
+		return "", err
+	}
+	if len(result.Result) < 2 {
+		return "", fmt.Errorf("No result for 'show session-config diffs'")
+	}
+	output, _ := result.Result[1]["output"].(string)
+	return output, nil
+}
+
+// Commit commits the configuration session, applying the staged commands
+// to the running-config.
+func (s *ConfigSession) Commit() error {
+	return s.finish("commit")
+}
+
+// CommitTimer commits the configuration session with a commit timer. The
+// changes are applied immediately but are rolled back automatically
+// unless Confirm is called before timeout expires.
+//
+// Args:
+//
+//	timeout (time.Duration): The time to wait for a confirmation before
+//	                         rolling back. Must be between 1 second and
+//	                         24 hours.
+func (s *ConfigSession) CommitTimer(timeout time.Duration) error {
+	if timeout < time.Second || timeout > 24*time.Hour {
+		return fmt.Errorf("Invalid commit timer: %s", timeout)
+	}
+	secs := int(timeout / time.Second)
+	return s.finish(fmt.Sprintf("commit timer %02d:%02d:%02d",
+		secs/3600, (secs/60)%60, secs%60))
+}
+
+// Confirm confirms a configuration session previously committed with
+// CommitTimer, preventing it from being rolled back.
+func (s *ConfigSession) Confirm() error {
+	_, err := s.node.RunCommands([]string{s.enter() + " commit"}, "json")
+	s.node.Refresh()
+	return err
+}
+
+// Abort aborts the configuration session, discarding the staged commands.
+func (s *ConfigSession) Abort() error {
+	return s.finish("abort")
+}
+
+// finish sends a command terminating the configuration session
+func (s *ConfigSession) finish(command string) error {
+	_, err := s.run(context.Background(), "json", command)
+	s.node.Refresh()
+	return err
+}
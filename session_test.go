@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSessionAdd_UnitTest(t *testing.T) {
+	session := dummyNode.NewSession("test")
+	if session.Name() != "test" {
+		t.Fatalf("Expected session name test, got %s", session.Name())
+	}
+	if err := session.Add("vlan 10", "name foo"); err != nil {
+		t.Fatalf("Add failed: %s", err)
+	}
+	want := []interface{}{"enable", "configure session test", "vlan 10", "name foo"}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+}
+
+func TestSessionNode_UnitTest(t *testing.T) {
+	session := dummyNode.NewSession("test")
+	if ok := session.Node().Config("vlan 10"); !ok {
+		t.Fatal("Config failed")
+	}
+	want := []interface{}{"enable", "configure session test", "vlan 10"}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+	if ok := dummyNode.Config("vlan 10"); !ok {
+		t.Fatal("Config failed")
+	}
+	if got := dummyConnection.GetCommands()[1]; got != "configure terminal" {
+		t.Fatalf("Parent node should not use the session, got %s", got)
+	}
+}
+
+func TestSessionCommitAbort_UnitTest(t *testing.T) {
+	session := dummyNode.NewSession("")
+	if session.Name() == "" {
+		t.Fatal("Expected generated session name")
+	}
+	enter := "configure session " + session.Name()
+	tests := [...]struct {
+		fn   func() error
+		want []interface{}
+	}{
+		{session.Commit, []interface{}{"enable", enter, "commit"}},
+		{session.Abort, []interface{}{"enable", enter, "abort"}},
+		{session.Confirm, []interface{}{"enable", enter + " commit"}},
+		{func() error { return session.CommitTimer(90 * time.Minute) },
+			[]interface{}{"enable", enter, "commit timer 01:30:00"}},
+	}
+	for idx, tt := range tests {
+		if err := tt.fn(); err != nil {
+			t.Fatalf("Test[%d] failed: %s", idx, err)
+		}
+		if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("Test[%d] expected %#v, got %#v", idx, tt.want, got)
+		}
+	}
+	if err := session.CommitTimer(0); err == nil {
+		t.Fatal("Expected error for invalid commit timer")
+	}
+}
+
+func TestSessionDiff_UnitTest(t *testing.T) {
+	session := dummyNode.NewSession("test")
+	if _, err := session.Diff(); err != nil {
+		t.Fatalf("Diff failed: %s", err)
+	}
+	want := []interface{}{"enable", "configure session test",
+		"show session-config diffs"}
+	if got := dummyConnection.GetCommands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %#v, got %#v", want, got)
+	}
+
+	dummyConnection.setReturnError(true)
+	if _, err := session.Diff(); err == nil {
+		t.Fatal("Expected error on connection failure")
+	}
+
+	node := &Node{conn: &truncatedConnection{}}
+	if _, err := node.NewSession("test").Diff(); err == nil {
+		t.Fatal("Expected error on missing diff result")
+	}
+}
+
+// truncatedConnection only returns the result for enable.
+type truncatedConnection struct {
+	basicConnection
+}
+
+func (conn *truncatedConnection) Execute(commands []interface{},
+	encoding string) (*JSONRPCResponse, error) {
+	return &JSONRPCResponse{Result: make([]map[string]interface{}, 1)}, nil
+}
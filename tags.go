@@ -0,0 +1,210 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TagSelector reports whether a connection with the given set of tags is
+// selected. Selectors are built with AllTags, AnyTags and NoTags and can be
+// combined with TagsAnd, TagsOr and TagsNot.
+type TagSelector func(tags map[string]bool) bool
+
+// AllTags selects connections carrying every one of tags
+func AllTags(tags ...string) TagSelector {
+	return func(have map[string]bool) bool {
+		for _, tag := range tags {
+			if !have[tag] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyTags selects connections carrying at least one of tags
+func AnyTags(tags ...string) TagSelector {
+	return func(have map[string]bool) bool {
+		for _, tag := range tags {
+			if have[tag] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// NoTags selects connections carrying none of tags
+func NoTags(tags ...string) TagSelector {
+	return TagsNot(AnyTags(tags...))
+}
+
+// TagsAnd selects connections matched by every one of selectors
+func TagsAnd(selectors ...TagSelector) TagSelector {
+	return func(have map[string]bool) bool {
+		for _, sel := range selectors {
+			if !sel(have) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// TagsOr selects connections matched by at least one of selectors
+func TagsOr(selectors ...TagSelector) TagSelector {
+	return func(have map[string]bool) bool {
+		for _, sel := range selectors {
+			if sel(have) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// TagsNot selects connections not matched by selector
+func TagsNot(selector TagSelector) TagSelector {
+	return func(have map[string]bool) bool {
+		return !selector(have)
+	}
+}
+
+// Tags returns the tags of a connection
+//
+// Tags are read from the comma separated 'tags' key of the connection
+// profile, for instance:
+//
+//	[connection:leaf1]
+//	host=192.168.1.16
+//	tags=leaf, pod1
+//
+// Args:
+//
+//	name (string): The name of the connection
+//
+// Returns:
+//
+//	List of tags or nil if the connection is not found or has no tags
+func (e *EapiConfig) Tags(name string) []string {
+	section := e.GetConnection(name)
+	if section == nil {
+		return nil
+	}
+	var tags []string
+	for _, tag := range strings.Split(section["tags"], ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ConnectionsMatching returns the sorted names of the connections whose
+// tags are matched by selector
+//
+// Args:
+//
+//	selector (TagSelector): The selector to apply to each connection
+//
+// Returns:
+//
+//	Sorted list of connection names
+func (e *EapiConfig) ConnectionsMatching(selector TagSelector) []string {
+	var names []string
+	for _, name := range e.Connections() {
+		have := make(map[string]bool)
+		for _, tag := range e.Tags(name) {
+			have[tag] = true
+		}
+		if selector(have) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConnectionsByTag returns the sorted names of the connections carrying
+// any of tags
+func (e *EapiConfig) ConnectionsByTag(tags ...string) []string {
+	return e.ConnectionsMatching(AnyTags(tags...))
+}
+
+// ConnectionsByTag returns the sorted names of the connections of the
+// global config carrying any of tags
+func ConnectionsByTag(tags ...string) []string {
+	return configGlobal.ConnectionsByTag(tags...)
+}
+
+// ConnectToMatching creates a Node instance for every connection whose
+// tags are matched by selector
+//
+// Returns:
+//
+//	Map of Node keyed by connection name, or error if any of the Nodes
+//	could not be created. On error the Nodes created so far are closed.
+func (e *EapiConfig) ConnectToMatching(selector TagSelector) (map[string]*Node, error) {
+	nodes := make(map[string]*Node)
+	for _, name := range e.ConnectionsMatching(selector) {
+		node, err := e.ConnectTo(name)
+		if err != nil {
+			for _, node := range nodes {
+				closeConnection(node.GetConnection())
+			}
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		nodes[name] = node
+	}
+	return nodes, nil
+}
+
+// ConnectToTag creates a Node instance for every connection carrying any
+// of tags
+//
+// Returns:
+//
+//	Map of Node keyed by connection name, or error if any of the Nodes
+//	could not be created
+func (e *EapiConfig) ConnectToTag(tags ...string) (map[string]*Node, error) {
+	return e.ConnectToMatching(AnyTags(tags...))
+}
+
+// ConnectToTag creates a Node instance for every connection of the global
+// config carrying any of tags
+func ConnectToTag(tags ...string) (map[string]*Node, error) {
+	return configGlobal.ConnectToTag(tags...)
+}
@@ -0,0 +1,100 @@
+//
+// Copyright (c) 2015-2016, Arista Networks, Inc.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//   * Redistributions of source code must retain the above copyright notice,
+//   this list of conditions and the following disclaimer.
+//
+//   * Redistributions in binary form must reproduce the above copyright
+//   notice, this list of conditions and the following disclaimer in the
+//   documentation and/or other materials provided with the distribution.
+//
+//   * Neither the name of Arista Networks nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL ARISTA NETWORKS
+// BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR
+// BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY,
+// WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE
+// OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN
+// IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package goeapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagsFromConfig_UnitTest(t *testing.T) {
+	config := &EapiConfig{}
+	if err := config.Read(GetFixture("eapi.conf")); err != nil {
+		t.Fatal(err)
+	}
+	if got := config.Tags("test1"); !reflect.DeepEqual(got, []string{"tag1", "tag2"}) {
+		t.Fatalf("Unexpected tags for test1: %#v", got)
+	}
+	if got := config.Tags("localhost"); got != nil {
+		t.Fatalf("Unexpected tags for localhost: %#v", got)
+	}
+	if got := config.Tags("bogus"); got != nil {
+		t.Fatalf("Unexpected tags for bogus: %#v", got)
+	}
+
+	tests := [...]struct {
+		selector TagSelector
+		want     []string
+	}{
+		{AnyTags("tag1"), []string{"test1", "test2"}},
+		{AnyTags("tag2", "bogus"), []string{"test1"}},
+		{AllTags("tag1", "tag2"), []string{"test1"}},
+		{AllTags("tag1", "bogus"), nil},
+		{NoTags("tag2"), []string{"localhost", "test2"}},
+		{TagsAnd(AnyTags("tag1"), TagsNot(AnyTags("tag2"))), []string{"test2"}},
+		{TagsOr(AllTags("tag2"), NoTags("tag1")), []string{"localhost", "test1"}},
+	}
+	for idx, tt := range tests {
+		if got := config.ConnectionsMatching(tt.selector); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("Test[%d] expected %#v, got %#v", idx, tt.want, got)
+		}
+	}
+	if got := config.ConnectionsByTag("tag2"); !reflect.DeepEqual(got, []string{"test1"}) {
+		t.Fatalf("Unexpected ConnectionsByTag: %#v", got)
+	}
+}
+
+func TestTagsConnectToTag_UnitTest(t *testing.T) {
+	config := &EapiConfig{}
+	if err := config.Read(GetFixture("eapi.conf")); err != nil {
+		t.Fatal(err)
+	}
+	// Point the profiles at an unused local port so connecting fails fast
+	for _, name := range []string{"test1", "test2"} {
+		config.GetConnection(name)["host"] = "127.0.0.1"
+		config.GetConnection(name)["port"] = "1"
+		config.GetConnection(name)["transport"] = "http"
+	}
+	nodes, err := config.ConnectToTag("tag1")
+	if err != nil {
+		t.Fatalf("ConnectToTag failed: %s", err)
+	}
+	if len(nodes) != 2 || nodes["test1"] == nil || nodes["test2"] == nil {
+		t.Fatalf("Unexpected nodes: %#v", nodes)
+	}
+
+	config.GetConnection("test2")["transport"] = "bogus"
+	if _, err := config.ConnectToTag("tag1"); err == nil {
+		t.Fatal("Expected error for invalid transport")
+	}
+}